@@ -7,10 +7,13 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"code.cloudfoundry.org/filelock"
 	"code.cloudfoundry.org/winc/hcs"
 	"code.cloudfoundry.org/winc/network"
+	"code.cloudfoundry.org/winc/network/audit"
 	"code.cloudfoundry.org/winc/network/endpoint"
 	"code.cloudfoundry.org/winc/network/mtu"
 	"code.cloudfoundry.org/winc/network/netinterface"
@@ -18,10 +21,18 @@ import (
 	"code.cloudfoundry.org/winc/network/netsh"
 	"code.cloudfoundry.org/winc/network/port_allocator"
 	"code.cloudfoundry.org/winc/network/port_allocator/serial"
+	"code.cloudfoundry.org/winc/network/reconciler"
+	"code.cloudfoundry.org/winc/network/status"
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli"
 )
 
+const (
+	defaultPortRangeStart = 40000
+	defaultPortRangeSize  = 5000
+	defaultPortStateFile  = "C:\\var\\vcap\\data\\winc-network\\port-state.json"
+)
+
 func main() {
 	app := cli.NewApp()
 	app.Name = "winc-network.exe"
@@ -29,7 +40,7 @@ func main() {
 	app.Flags = []cli.Flag{
 		cli.StringFlag{
 			Name:  "action",
-			Usage: "network action e.g. up,down,create,delete",
+			Usage: "network action e.g. up,down,create,delete,net-in,net-out,bandwidth,reconcile,status",
 			Value: "",
 		},
 		cli.StringFlag{
@@ -101,15 +112,10 @@ func main() {
 		}
 		handle := context.String("handle")
 		action := context.String("action")
-		if (action == "up" || action == "down") && handle == "" {
+		if (action == "up" || action == "down" || action == "net-in" || action == "net-out" || action == "bandwidth") && handle == "" {
 			return fmt.Errorf("missing required flag 'handle'")
 		}
 
-		networkManager, err := wireNetworkManager(config, handle)
-		if err != nil {
-			fatal(err)
-		}
-
 		switch action {
 		case "up":
 			var inputs network.UpInputs
@@ -117,6 +123,16 @@ func main() {
 				return fmt.Errorf("networkUp: %s", err.Error())
 			}
 
+			networkConfig, err := config.ForNetwork(inputs.Network)
+			if err != nil {
+				return fmt.Errorf("networkUp: %s", err.Error())
+			}
+
+			networkManager, err := wireNetworkManager(networkConfig, handle)
+			if err != nil {
+				fatal(err)
+			}
+
 			outputs, err := networkManager.Up(inputs)
 			if err != nil {
 				return fmt.Errorf("networkUp: %s", err.Error())
@@ -126,17 +142,122 @@ func main() {
 				return fmt.Errorf("networkUp: %s", err.Error())
 			}
 
+		case "net-in":
+			var rule netrules.NetIn
+			if err := json.NewDecoder(os.Stdin).Decode(&rule); err != nil {
+				return fmt.Errorf("netIn: %s", err.Error())
+			}
+
+			networkManager, err := wireNetworkManager(containerNetworkConfig(config, handle), handle)
+			if err != nil {
+				fatal(err)
+			}
+
+			outputs, err := networkManager.NetIn(rule)
+			if err != nil {
+				return fmt.Errorf("netIn: %s", err.Error())
+			}
+
+			if err := json.NewEncoder(os.Stdout).Encode(outputs); err != nil {
+				return fmt.Errorf("netIn: %s", err.Error())
+			}
+
+		case "net-out":
+			var rule netrules.NetOut
+			if err := json.NewDecoder(os.Stdin).Decode(&rule); err != nil {
+				return fmt.Errorf("netOut: %s", err.Error())
+			}
+
+			networkManager, err := wireNetworkManager(containerNetworkConfig(config, handle), handle)
+			if err != nil {
+				fatal(err)
+			}
+
+			if err := networkManager.NetOut(rule); err != nil {
+				return fmt.Errorf("netOut: %s", err.Error())
+			}
+
+		case "bandwidth":
+			content, err := ioutil.ReadAll(os.Stdin)
+			if err != nil {
+				return fmt.Errorf("bandwidth: %s", err.Error())
+			}
+
+			limits, err := network.ParseBandwidthLimits(content)
+			if err != nil {
+				return fmt.Errorf("bandwidth: %s", err.Error())
+			}
+
+			networkManager, err := wireNetworkManager(containerNetworkConfig(config, handle), handle)
+			if err != nil {
+				fatal(err)
+			}
+
+			if err := networkManager.SetBandwidth(limits); err != nil {
+				return fmt.Errorf("bandwidth: %s", err.Error())
+			}
+
+		case "reconcile":
+			report, err := reconciler.New(&hcs.Client{}, wirePortAllocator(config)).Reconcile()
+			if err != nil {
+				return fmt.Errorf("reconcile: %s", err.Error())
+			}
+
+			if err := json.NewEncoder(os.Stdout).Encode(report); err != nil {
+				return fmt.Errorf("reconcile: %s", err.Error())
+			}
+
+		case "status":
+			portAllocator := wirePortAllocator(config)
+			s, err := status.New(&hcs.Client{}, portAllocator, portRange(config), config.NetworkNames()).Status()
+			if err != nil {
+				return fmt.Errorf("status: %s", err.Error())
+			}
+
+			if err := json.NewEncoder(os.Stdout).Encode(s); err != nil {
+				return fmt.Errorf("status: %s", err.Error())
+			}
+
 		case "create":
-			if err := networkManager.CreateHostNATNetwork(); err != nil {
+			if err := checkPortRange(config); err != nil {
 				return fmt.Errorf("network create: %s", err.Error())
 			}
 
+			for _, networkConfig := range config.AllNetworks() {
+				networkManager, err := wireNetworkManager(networkConfig, handle)
+				if err != nil {
+					fatal(err)
+				}
+
+				if err := networkManager.CreateHostNATNetwork(); err != nil {
+					return fmt.Errorf("network create: %s: %s", networkConfig.NetworkName, err.Error())
+				}
+			}
+
 		case "delete":
-			if err := networkManager.DeleteHostNATNetwork(); err != nil {
-				return fmt.Errorf("network delete: %s", err.Error())
+			// delete every network even if one of them fails
+			var errs []string
+			for _, networkConfig := range config.AllNetworks() {
+				networkManager, err := wireNetworkManager(networkConfig, handle)
+				if err != nil {
+					fatal(err)
+				}
+
+				if err := networkManager.DeleteHostNATNetwork(); err != nil {
+					errs = append(errs, fmt.Sprintf("%s: %s", networkConfig.NetworkName, err.Error()))
+				}
+			}
+
+			if len(errs) > 0 {
+				return fmt.Errorf("network delete: %s", strings.Join(errs, ", "))
 			}
 
 		case "down":
+			networkManager, err := wireNetworkManager(config, handle)
+			if err != nil {
+				fatal(err)
+			}
+
 			if err := networkManager.Down(); err != nil {
 				return fmt.Errorf("networkDown: %s", err.Error())
 			}
@@ -165,26 +286,67 @@ func parseConfig(configFile string) (network.Config, error) {
 		}
 	}
 
+	if config.PortRangeStart == 0 {
+		config.PortRangeStart = defaultPortRangeStart
+	}
+	if config.PortRangeSize == 0 {
+		config.PortRangeSize = defaultPortRangeSize
+	}
+	if config.PortStateFile == "" {
+		config.PortStateFile = defaultPortStateFile
+	}
+
+	if err := port_allocator.ValidateRange(portRange(config), nil); err != nil {
+		return config, err
+	}
+
+	if err := config.Validate(); err != nil {
+		return config, err
+	}
+
 	return config, nil
 }
 
-func wireNetworkManager(config network.Config, handle string) (*network.NetworkManager, error) {
-	hcsClient := &hcs.Client{}
-	runner := netsh.NewRunner(hcsClient, handle, config.WaitTimeoutInSeconds)
+// containerNetworkConfig returns the config for the network the container's
+// endpoint is attached to, falling back to the network at the top level of
+// the config
+func containerNetworkConfig(config network.Config, handle string) network.Config {
+	endpoint, err := (&hcs.Client{}).GetHNSEndpointByName(handle)
+	if err != nil {
+		return config
+	}
 
-	tracker := &port_allocator.Tracker{
-		StartPort: 40000,
-		Capacity:  5000,
+	networkConfig, err := config.ForNetwork(endpoint.VirtualNetworkName)
+	if err != nil {
+		logrus.Warnf("endpoint %s: %s", handle, err.Error())
+		return config
 	}
 
-	locker := filelock.NewLocker("C:\\var\\vcap\\data\\winc-network\\port-state.json")
+	return networkConfig
+}
 
-	portAllocator := &port_allocator.PortAllocator{
-		Tracker:    tracker,
-		Serializer: &serial.Serial{},
-		Locker:     locker,
+// checkPortRange makes sure the allocation range is valid and does not
+// overlap the host's dynamic port ranges. netsh reports those in the host's
+// display language, so when they cannot be read only a warning is logged.
+func checkPortRange(config network.Config) error {
+	dynamicPorts, err := port_allocator.DynamicPortRanges()
+	if err != nil {
+		logrus.Warnf("not checking the port range against the dynamic port ranges: %s", err.Error())
 	}
 
+	return port_allocator.ValidateRange(portRange(config), dynamicPorts)
+}
+
+func portRange(config network.Config) port_allocator.PortRange {
+	return port_allocator.PortRange{Start: config.PortRangeStart, Size: config.PortRangeSize}
+}
+
+func wireNetworkManager(config network.Config, handle string) (*network.NetworkManager, error) {
+	hcsClient := &hcs.Client{}
+	runner := netsh.NewRunner(hcsClient, handle, config.WaitTimeoutInSeconds)
+
+	portAllocator := wirePortAllocator(config)
+
 	applier, err := wireApplier(runner, handle, portAllocator)
 	if err != nil {
 		return nil, err
@@ -195,7 +357,13 @@ func wireNetworkManager(config network.Config, handle string) (*network.NetworkM
 		return nil, err
 	}
 
-	m := mtu.New(handle, config.NetworkName, &netinterface.NetInterface{})
+	netInterface := &netinterface.NetInterface{}
+	m := mtu.New(handle, config.NetworkName, netInterface)
+
+	auditor, err := wireAuditor(config, handle)
+	if err != nil {
+		return nil, err
+	}
 
 	return network.NewNetworkManager(
 		hcsClient,
@@ -204,9 +372,40 @@ func wireNetworkManager(config network.Config, handle string) (*network.NetworkM
 		handle,
 		config,
 		m,
+		auditor,
+		netInterface,
 	), nil
 }
 
+func wireAuditor(config network.Config, handle string) (*audit.Logger, error) {
+	if config.AuditLogFile == "" {
+		return audit.New(nil, handle), nil
+	}
+
+	f, err := os.OpenFile(config.AuditLogFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("open audit log: %s", err.Error())
+	}
+
+	return audit.New(f, handle), nil
+}
+
+func wirePortAllocator(config network.Config) *port_allocator.PortAllocator {
+	tracker := &port_allocator.Tracker{
+		StartPort:  config.PortRangeStart,
+		Capacity:   config.PortRangeSize,
+		Quarantine: time.Duration(config.PortReuseQuarantineInSeconds) * time.Second,
+	}
+
+	locker := filelock.NewLocker(config.PortStateFile)
+
+	return &port_allocator.PortAllocator{
+		Tracker:    tracker,
+		Serializer: &serial.Serial{},
+		Locker:     locker,
+	}
+}
+
 func fatal(err error) {
 	logrus.Error(err)
 	fmt.Fprintln(os.Stderr, err)
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/urfave/cli"
+)
+
+var cpCommand = cli.Command{
+	Name:  "cp",
+	Usage: "copy files between a container and the host",
+	ArgsUsage: `<container-id>:<container-path> <host-path>
+   winc cp <host-path> <container-id>:<container-path>
+
+Where "<container-id>" is the name for the instance of the container and
+"<container-path>" is a path inside the container.`,
+	Description: `The cp command copies a file or directory out of or into a created or
+running container. If the destination is an existing directory the source is
+copied into it. Symlinks inside the container are never followed out of the
+container's root.
+
+EXAMPLE:
+For example, to copy the logs out of the container "windows01":
+
+       # winc cp windows01:C:\app\logs C:\tmp\logs`,
+	Action: func(context *cli.Context) error {
+		if err := checkArgs(context, 2, exactArgs); err != nil {
+			return err
+		}
+
+		source := context.Args().Get(0)
+		destination := context.Args().Get(1)
+
+		sourceId, sourcePath, sourceInContainer := splitContainerPath(source)
+		destinationId, destinationPath, destinationInContainer := splitContainerPath(destination)
+
+		switch {
+		case sourceInContainer && !destinationInContainer:
+			return run.CopyOut(sourceId, sourcePath, destination)
+		case !sourceInContainer && destinationInContainer:
+			return run.CopyIn(destinationId, source, destinationPath)
+		default:
+			return &InvalidCopyPathsError{Source: source, Destination: destination}
+		}
+	},
+}
+
+// splitContainerPath splits <container-id>:<path> into its parts. Absolute
+// host paths and paths starting with a drive letter are host paths.
+func splitContainerPath(arg string) (string, string, bool) {
+	if filepath.IsAbs(arg) {
+		return "", arg, false
+	}
+
+	i := strings.Index(arg, ":")
+	if i < 1 || (i == 1 && filepath.VolumeName(arg) != "") {
+		return "", arg, false
+	}
+
+	return arg[:i], arg[i+1:], true
+}
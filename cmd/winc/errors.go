@@ -11,3 +11,12 @@ type InvalidLogFormatError struct {
 func (e *InvalidLogFormatError) Error() string {
 	return fmt.Sprintf("invalid log format %s", e.Format)
 }
+
+type InvalidCopyPathsError struct {
+	Source      string
+	Destination string
+}
+
+func (e *InvalidCopyPathsError) Error() string {
+	return fmt.Sprintf("exactly one of %s and %s must be a container path of the form <container-id>:<path>", e.Source, e.Destination)
+}
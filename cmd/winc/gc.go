@@ -0,0 +1,30 @@
+package main
+
+import (
+	"os"
+
+	"github.com/urfave/cli"
+)
+
+var gcCommand = cli.Command{
+	Name:  "gc",
+	Usage: "remove mount points and state left behind by containers that no longer exist",
+	Description: `The gc command removes the c:\proc\<pid> mount points, the sandbox
+layers mounted by winc and the state directories of containers that no
+longer exist in HCS, for example after a failed delete or a host restart.
+Mount points are left in place while HCS reports containers without state in
+the root dir, as their pids are unknown. It outputs what it removed.`,
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "only report what would be removed",
+		},
+	},
+	Action: func(context *cli.Context) error {
+		if err := checkArgs(context, 0, exactArgs); err != nil {
+			return err
+		}
+
+		return run.GC(context.Bool("dry-run"), os.Stdout)
+	},
+}
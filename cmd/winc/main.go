@@ -14,7 +14,9 @@ import (
 	"code.cloudfoundry.org/winc/runtime"
 	"code.cloudfoundry.org/winc/runtime/container"
 	"code.cloudfoundry.org/winc/runtime/hcsprocess"
+	"code.cloudfoundry.org/winc/runtime/layer"
 	"code.cloudfoundry.org/winc/runtime/mount"
+	"code.cloudfoundry.org/winc/runtime/rootfs"
 	"code.cloudfoundry.org/winc/runtime/state"
 	"code.cloudfoundry.org/winc/runtime/winsyscall"
 	"github.com/sirupsen/logrus"
@@ -99,9 +101,13 @@ func main() {
 			Value: "C:\\ProgramData\\winc",
 			Usage: "directory for storage of container state",
 		},
+		cli.BoolFlag{
+			Name:  "mount-layers",
+			Usage: "mount the container sandbox from windows.layerFolders instead of requiring a pre-mounted root.path",
+		},
 		cli.StringFlag{
 			Name:  "credential-spec",
-			Usage: "path to credential spec file",
+			Usage: "path to credential spec file, used when a bundle does not specify windows.credentialSpec",
 		},
 	}
 
@@ -113,6 +119,10 @@ func main() {
 		startCommand,
 		execCommand,
 		eventsCommand,
+		cpCommand,
+		streamInCommand,
+		streamOutCommand,
+		gcCommand,
 	}
 
 	app.Before = func(context *cli.Context) error {
@@ -122,6 +132,7 @@ func main() {
 		logFormat := context.GlobalString("log-format")
 		rootDir := context.GlobalString("root")
 		credentialSpecPath := context.String("credential-spec")
+		mountLayers := context.GlobalBool("mount-layers")
 
 		if debug {
 			logrus.SetLevel(logrus.DebugLevel)
@@ -178,9 +189,11 @@ func main() {
 		stateFactory := &stateFactory{}
 		mounter := &mount.Mounter{}
 		hcsClient := &hcs.Client{}
+		layerManager := layer.New(hcsClient)
+		streamer := &rootfs.Streamer{}
 		processWrapper := &processWrapper{}
 
-		run = runtime.New(stateFactory, containerFactory, mounter, hcsClient, processWrapper, rootDir, credentialSpecPath)
+		run = runtime.New(stateFactory, containerFactory, mounter, layerManager, streamer, hcsClient, processWrapper, rootDir, credentialSpecPath, mountLayers)
 		return nil
 	}
 
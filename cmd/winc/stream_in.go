@@ -0,0 +1,33 @@
+package main
+
+import (
+	"os"
+
+	"github.com/urfave/cli"
+)
+
+var streamInCommand = cli.Command{
+	Name:  "stream-in",
+	Usage: "extract a tar stream from stdin into a container",
+	ArgsUsage: `<container-id> <container-path>
+
+Where "<container-id>" is the name for the instance of the container and
+"<container-path>" is the directory inside the container to extract into.`,
+	Description: `The stream-in command extracts a tar stream read from stdin into a
+created or running container, creating <container-path> if needed.
+
+EXAMPLE:
+For example, to stream an archive into the container "windows01":
+
+       # winc stream-in windows01 C:\app < app.tar`,
+	Action: func(context *cli.Context) error {
+		if err := checkArgs(context, 2, exactArgs); err != nil {
+			return err
+		}
+
+		containerId := context.Args().Get(0)
+		containerPath := context.Args().Get(1)
+
+		return run.StreamIn(containerId, containerPath, os.Stdin)
+	},
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"os"
+
+	"github.com/urfave/cli"
+)
+
+var streamOutCommand = cli.Command{
+	Name:  "stream-out",
+	Usage: "write a tar stream of a path in a container to stdout",
+	ArgsUsage: `<container-id> <container-path>
+
+Where "<container-id>" is the name for the instance of the container and
+"<container-path>" is the file or directory inside the container to stream.`,
+	Description: `The stream-out command writes a tar stream of <container-path> in a
+created or running container to stdout.
+
+EXAMPLE:
+For example, to stream the logs out of the container "windows01":
+
+       # winc stream-out windows01 C:\app\logs > logs.tar`,
+	Action: func(context *cli.Context) error {
+		if err := checkArgs(context, 2, exactArgs); err != nil {
+			return err
+		}
+
+		containerId := context.Args().Get(0)
+		containerPath := context.Args().Get(1)
+
+		return run.StreamOut(containerId, containerPath, os.Stdout)
+	},
+}
@@ -21,6 +21,22 @@ func (c *Client) GetLayerMountPath(info hcsshim.DriverInfo, id string) (string,
 	return hcsshim.GetLayerMountPath(info, id)
 }
 
+func (c *Client) ActivateLayer(info hcsshim.DriverInfo, id string) error {
+	return hcsshim.ActivateLayer(info, id)
+}
+
+func (c *Client) PrepareLayer(info hcsshim.DriverInfo, id string, parentLayerPaths []string) error {
+	return hcsshim.PrepareLayer(info, id, parentLayerPaths)
+}
+
+func (c *Client) UnprepareLayer(info hcsshim.DriverInfo, id string) error {
+	return hcsshim.UnprepareLayer(info, id)
+}
+
+func (c *Client) DeactivateLayer(info hcsshim.DriverInfo, id string) error {
+	return hcsshim.DeactivateLayer(info, id)
+}
+
 func (c *Client) CreateContainer(id string, config *hcsshim.ContainerConfig) (Container, error) {
 	return hcsshim.CreateContainer(id, config)
 }
@@ -115,6 +131,10 @@ func (c *Client) HNSListNetworkRequest() ([]hcsshim.HNSNetwork, error) {
 	return hcsshim.HNSListNetworkRequest("GET", "", "")
 }
 
+func (c *Client) HNSListEndpointRequest() ([]hcsshim.HNSEndpoint, error) {
+	return hcsshim.HNSListEndpointRequest()
+}
+
 func (c *Client) GetHNSEndpointByID(id string) (*hcsshim.HNSEndpoint, error) {
 	return hcsshim.GetHNSEndpointByID(id)
 }
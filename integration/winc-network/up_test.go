@@ -116,6 +116,11 @@ var _ = Describe("Up", func() {
 				hostIP, err := localip.LocalIP()
 				Expect(err).NotTo(HaveOccurred())
 
+				Expect(outputs.MappedPorts).To(HaveLen(2))
+				Expect(outputs.MappedPorts[0].HostPort).To(Equal(hostPort1))
+				Expect(outputs.MappedPorts[0].HostIPs).To(ContainElement(hostIP))
+				Expect(outputs.MappedPorts[1].Protocol).To(Equal("tcp"))
+
 				address := fmt.Sprintf("http://%s:%d", hostIP, hostPort1)
 				var resp http.Response
 				Eventually(httpGetInto(address, &resp), "30s").Should(Succeed())
@@ -182,7 +187,9 @@ var _ = Describe("Up", func() {
 					outputs := helpers.NetworkUp(containerId, `{"Pid": 123, "Properties": {} }`, networkConfigFile)
 
 					Expect(outputs.Properties.MappedPorts).To(Equal("[]"))
-					Expect(outputs.Properties.DeprecatedHostIP).To(Equal("255.255.255.255"))
+					Expect(outputs.MappedPorts).To(BeEmpty())
+					Expect(net.ParseIP(outputs.Properties.DeprecatedHostIP)).NotTo(BeNil())
+					Expect(outputs.Properties.DeprecatedHostIP).NotTo(Equal("255.255.255.255"))
 
 					_, network, err := net.ParseCIDR(networkConfig.SubnetRange)
 					Expect(err).NotTo(HaveOccurred())
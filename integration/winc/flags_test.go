@@ -227,15 +227,16 @@ var _ = Describe("Flags", func() {
 			Expect(os.RemoveAll(bundlePath)).To(Succeed())
 		})
 
-		It("creates a container and uses the credential spec path", func() {
+		It("validates the credential spec at the path", func() {
 			file, err := os.CreateTemp(bundlePath, "credential-spec")
 			defer file.Close()
 			Expect(err).NotTo(HaveOccurred())
 
 			args := []string{"--credential-spec", file.Name(), "create", containerId, "-b", bundlePath}
-			_, _, err = helpers.Execute(exec.Command(wincBin, args...))
-			Expect(err).NotTo(HaveOccurred())
-			Expect(helpers.ContainerExists(containerId)).To(BeTrue())
+			stdOut, stdErr, err := helpers.Execute(exec.Command(wincBin, args...))
+			Expect(err).To(HaveOccurred(), stdOut.String(), stdErr.String())
+			Expect(stdErr.String()).To(ContainSubstring(file.Name()))
+			Expect(helpers.ContainerExists(containerId)).To(BeFalse())
 		})
 
 		Context("when the credential file does not exist", func() {
@@ -0,0 +1,83 @@
+package audit
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/winc/network/netrules"
+	"github.com/sirupsen/logrus"
+)
+
+// Record describes a NetOut rule applied to a container with logging enabled
+type Record struct {
+	Time         time.Time `json:"time"`
+	Handle       string    `json:"handle"`
+	ContainerIP  string    `json:"container_ip"`
+	Action       string    `json:"action"`
+	Protocol     string    `json:"protocol"`
+	Destinations []string  `json:"destinations"`
+	Ports        []string  `json:"ports"`
+	ICMPs        string    `json:"icmps,omitempty"`
+}
+
+// Logger writes one JSON record per line to the audit log. Without an audit
+// log the records are written to the regular log instead.
+type Logger struct {
+	out    io.Writer
+	handle string
+	mu     sync.Mutex
+}
+
+func New(out io.Writer, handle string) *Logger {
+	return &Logger{
+		out:    out,
+		handle: handle,
+	}
+}
+
+func (l *Logger) NetOut(containerIP string, rule netrules.NetOut) error {
+	record := Record{
+		Time:         time.Now().UTC(),
+		Handle:       l.handle,
+		ContainerIP:  containerIP,
+		Action:       string(netrules.NetOutActionAllow),
+		Protocol:     rule.Protocol.String(),
+		Destinations: []string{},
+		Ports:        []string{},
+	}
+
+	if rule.IsDeny() {
+		record.Action = string(netrules.NetOutActionDeny)
+	}
+
+	for _, ipr := range rule.Networks {
+		record.Destinations = append(record.Destinations, ipr.String())
+	}
+
+	for _, pr := range rule.Ports {
+		record.Ports = append(record.Ports, pr.String())
+	}
+
+	if rule.ICMPs != nil {
+		record.ICMPs = rule.ICMPs.String()
+	}
+
+	if l.out == nil {
+		logrus.WithFields(logrus.Fields{
+			"handle":       record.Handle,
+			"container_ip": record.ContainerIP,
+			"action":       record.Action,
+			"protocol":     record.Protocol,
+			"destinations": record.Destinations,
+			"ports":        record.Ports,
+			"icmps":        record.ICMPs,
+		}).Info("netout-audit")
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return json.NewEncoder(l.out).Encode(record)
+}
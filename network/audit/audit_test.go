@@ -0,0 +1,99 @@
+package audit_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/winc/network/audit"
+	"code.cloudfoundry.org/winc/network/netrules"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/sirupsen/logrus"
+)
+
+var _ = Describe("Logger", func() {
+	var (
+		out    *bytes.Buffer
+		logger *audit.Logger
+		rule   netrules.NetOut
+	)
+
+	BeforeEach(func() {
+		out = &bytes.Buffer{}
+		logger = audit.New(out, "some-handle")
+
+		rule = netrules.NetOut{
+			Protocol: netrules.ProtocolTCP,
+			Networks: []netrules.IPRange{{Start: net.ParseIP("10.0.0.1"), End: net.ParseIP("10.0.0.255")}},
+			Ports:    []netrules.PortRange{{Start: 80, End: 80}, {Start: 8080, End: 8090}},
+			Log:      true,
+		}
+	})
+
+	It("writes a JSON record of the rule", func() {
+		Expect(logger.NetOut("172.30.0.2", rule)).To(Succeed())
+
+		var record audit.Record
+		Expect(json.Unmarshal(out.Bytes(), &record)).To(Succeed())
+		Expect(record.Time).To(BeTemporally("~", time.Now(), time.Minute))
+		record.Time = time.Time{}
+
+		Expect(record).To(Equal(audit.Record{
+			Handle:       "some-handle",
+			ContainerIP:  "172.30.0.2",
+			Action:       "allow",
+			Protocol:     "tcp",
+			Destinations: []string{"10.0.0.1-10.0.0.255"},
+			Ports:        []string{"80-80", "8080-8090"},
+		}))
+	})
+
+	It("writes one line per rule", func() {
+		Expect(logger.NetOut("172.30.0.2", rule)).To(Succeed())
+		Expect(logger.NetOut("172.30.0.2", netrules.NetOut{Protocol: netrules.ProtocolAll, Log: true})).To(Succeed())
+
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		Expect(lines).To(HaveLen(2))
+		Expect(lines[1]).To(ContainSubstring(`"protocol":"all","destinations":[],"ports":[]`))
+	})
+
+	It("records deny rules", func() {
+		rule.Action = netrules.NetOutActionDeny
+		Expect(logger.NetOut("172.30.0.2", rule)).To(Succeed())
+
+		var record audit.Record
+		Expect(json.Unmarshal(out.Bytes(), &record)).To(Succeed())
+		Expect(record.Action).To(Equal("deny"))
+	})
+
+	It("includes the ICMP type and code", func() {
+		code := netrules.ICMPCode(0)
+		rule = netrules.NetOut{Protocol: netrules.ProtocolICMP, ICMPs: &netrules.ICMPControl{Type: 8, Code: &code}, Log: true}
+		Expect(logger.NetOut("172.30.0.2", rule)).To(Succeed())
+
+		var record audit.Record
+		Expect(json.Unmarshal(out.Bytes(), &record)).To(Succeed())
+		Expect(record.Protocol).To(Equal("icmp"))
+		Expect(record.ICMPs).To(Equal("8:0"))
+	})
+
+	Context("there is no audit log", func() {
+		var logOut *bytes.Buffer
+
+		BeforeEach(func() {
+			logOut = &bytes.Buffer{}
+			logrus.SetOutput(logOut)
+			logger = audit.New(nil, "some-handle")
+		})
+
+		It("writes the record to the regular log", func() {
+			Expect(logger.NetOut("172.30.0.2", rule)).To(Succeed())
+			Expect(logOut.String()).To(ContainSubstring("netout-audit"))
+			Expect(logOut.String()).To(ContainSubstring("handle=some-handle"))
+			Expect(logOut.String()).To(ContainSubstring("container_ip=172.30.0.2"))
+		})
+	})
+})
@@ -0,0 +1,114 @@
+package network
+
+import "fmt"
+
+// NetworkDefinition describes a network containers may be attached to in
+// addition to the one named at the top level of the config
+type NetworkDefinition struct {
+	NetworkName        string `json:"network_name"`
+	SubnetRange        string `json:"subnet_range"`
+	GatewayAddress     string `json:"gateway_address"`
+	NetworkType        string `json:"network_type"`
+	NetworkAdapterName string `json:"network_adapter_name"`
+	VLANID             uint   `json:"vlan_id"`
+	IPAssignment       string `json:"ip_assignment"`
+}
+
+// ForNetwork returns the config for the named network, which shares every
+// setting but the network definition with c. An empty name selects the
+// network at the top level of the config.
+func (c Config) ForNetwork(name string) (Config, error) {
+	if name == "" || name == c.NetworkName {
+		return c, nil
+	}
+
+	for _, definition := range c.Networks {
+		if definition.NetworkName == name {
+			return c.withNetwork(definition), nil
+		}
+	}
+
+	return Config{}, &UnknownNetworkError{Name: name}
+}
+
+// AllNetworks returns the config for every configured network, starting with
+// the one at the top level of the config
+func (c Config) AllNetworks() []Config {
+	configs := []Config{c}
+	for _, definition := range c.Networks {
+		configs = append(configs, c.withNetwork(definition))
+	}
+	return configs
+}
+
+// NetworkNames returns the names of every configured network, starting with
+// the one at the top level of the config
+func (c Config) NetworkNames() []string {
+	names := []string{}
+	for _, config := range c.AllNetworks() {
+		names = append(names, config.NetworkName)
+	}
+	return names
+}
+
+// withNetwork returns c with the definition of another network. The other
+// definitions, including the top level one, stay in Networks so that every
+// network is still known to the returned config.
+func (c Config) withNetwork(definition NetworkDefinition) Config {
+	networks := []NetworkDefinition{c.definition()}
+	for _, other := range c.Networks {
+		if other.NetworkName != definition.NetworkName {
+			networks = append(networks, other)
+		}
+	}
+	c.Networks = networks
+
+	c.NetworkName = definition.NetworkName
+	c.SubnetRange = definition.SubnetRange
+	c.GatewayAddress = definition.GatewayAddress
+	c.NetworkType = definition.NetworkType
+	c.NetworkAdapterName = definition.NetworkAdapterName
+	c.VLANID = definition.VLANID
+	c.IPAssignment = definition.IPAssignment
+	return c
+}
+
+func (c Config) definition() NetworkDefinition {
+	return NetworkDefinition{
+		NetworkName:        c.NetworkName,
+		SubnetRange:        c.SubnetRange,
+		GatewayAddress:     c.GatewayAddress,
+		NetworkType:        c.NetworkType,
+		NetworkAdapterName: c.NetworkAdapterName,
+		VLANID:             c.VLANID,
+		IPAssignment:       c.IPAssignment,
+	}
+}
+
+func (c *Config) validateNetworks() error {
+	if err := c.validateNetwork(); err != nil {
+		return err
+	}
+
+	names := map[string]bool{c.NetworkName: true}
+	for i, definition := range c.Networks {
+		field := fmt.Sprintf("networks[%d]", i)
+
+		if definition.NetworkName == "" {
+			return &NetworkConfigError{Field: field + ".network_name", Message: "must not be empty"}
+		}
+
+		if names[definition.NetworkName] {
+			return &NetworkConfigError{Field: field + ".network_name", Message: fmt.Sprintf("duplicate network name %s", definition.NetworkName)}
+		}
+		names[definition.NetworkName] = true
+
+		config := c.withNetwork(definition)
+		if err := config.validateNetwork(); err != nil {
+			err.Field = field + "." + err.Field
+			return err
+		}
+	}
+
+	return nil
+}
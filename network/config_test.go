@@ -0,0 +1,106 @@
+package network_test
+
+import (
+	"code.cloudfoundry.org/winc/network"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Config", func() {
+	var config network.Config
+
+	BeforeEach(func() {
+		config = network.Config{
+			MTU:            1434,
+			NetworkName:    "winc-nat",
+			SubnetRange:    "172.30.0.0/22",
+			GatewayAddress: "172.30.0.1",
+			Networks: []network.NetworkDefinition{
+				{
+					NetworkName:        "routable",
+					SubnetRange:        "10.0.0.0/24",
+					GatewayAddress:     "10.0.0.1",
+					NetworkType:        network.NetworkTypeL2Bridge,
+					NetworkAdapterName: "Ethernet 2",
+					VLANID:             12,
+				},
+			},
+		}
+	})
+
+	Describe("ForNetwork", func() {
+		It("returns the config itself for the top level network", func() {
+			Expect(config.ForNetwork("")).To(Equal(config))
+			Expect(config.ForNetwork("winc-nat")).To(Equal(config))
+		})
+
+		It("returns the config with the definition of the named network", func() {
+			networkConfig, err := config.ForNetwork("routable")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(networkConfig.MTU).To(Equal(1434))
+			Expect(networkConfig.NetworkName).To(Equal("routable"))
+			Expect(networkConfig.SubnetRange).To(Equal("10.0.0.0/24"))
+			Expect(networkConfig.GatewayAddress).To(Equal("10.0.0.1"))
+			Expect(networkConfig.NetworkType).To(Equal(network.NetworkTypeL2Bridge))
+			Expect(networkConfig.NetworkAdapterName).To(Equal("Ethernet 2"))
+			Expect(networkConfig.VLANID).To(Equal(uint(12)))
+			Expect(networkConfig.IsNAT()).To(BeFalse())
+		})
+
+		It("keeps knowing the other networks", func() {
+			networkConfig, err := config.ForNetwork("routable")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(networkConfig.NetworkNames()).To(ConsistOf("routable", "winc-nat"))
+
+			topLevel, err := networkConfig.ForNetwork("winc-nat")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(topLevel.SubnetRange).To(Equal("172.30.0.0/22"))
+			Expect(topLevel.IsNAT()).To(BeTrue())
+		})
+
+		It("returns an error for networks that are not configured", func() {
+			_, err := config.ForNetwork("other")
+			Expect(err).To(MatchError(&network.UnknownNetworkError{Name: "other"}))
+			Expect(err).To(MatchError("network other is not configured"))
+		})
+	})
+
+	Describe("AllNetworks", func() {
+		It("returns the config of every network, starting with the top level one", func() {
+			configs := config.AllNetworks()
+			Expect(configs).To(HaveLen(2))
+			Expect(configs[0].NetworkName).To(Equal("winc-nat"))
+			Expect(configs[1].NetworkName).To(Equal("routable"))
+			Expect(configs[1].MTU).To(Equal(1434))
+		})
+	})
+
+	Describe("NetworkNames", func() {
+		It("returns the name of every network, starting with the top level one", func() {
+			Expect(config.NetworkNames()).To(Equal([]string{"winc-nat", "routable"}))
+		})
+	})
+
+	Describe("Validate", func() {
+		It("accepts the networks", func() {
+			Expect(config.Validate()).To(Succeed())
+		})
+
+		It("rejects unnamed networks", func() {
+			config.Networks[0].NetworkName = ""
+			Expect(config.Validate()).To(MatchError(&network.NetworkConfigError{Field: "networks[0].network_name", Message: "must not be empty"}))
+		})
+
+		It("rejects duplicate names", func() {
+			config.Networks = append(config.Networks, network.NetworkDefinition{NetworkName: "winc-nat"})
+			Expect(config.Validate()).To(MatchError(&network.NetworkConfigError{Field: "networks[1].network_name", Message: "duplicate network name winc-nat"}))
+		})
+
+		It("validates each network definition", func() {
+			config.Networks[0].IPAssignment = network.IPAssignmentDHCP
+			Expect(config.Validate()).To(MatchError("invalid network config: networks[0].ip_assignment: dhcp is only supported on transparent networks"))
+		})
+	})
+})
@@ -3,6 +3,7 @@ package endpoint
 import (
 	"encoding/json"
 	"fmt"
+	"net"
 	"strings"
 
 	"code.cloudfoundry.org/winc/network"
@@ -19,6 +20,7 @@ type HCSClient interface {
 	UpdateEndpoint(*hcsshim.HNSEndpoint) (*hcsshim.HNSEndpoint, error)
 	GetHNSEndpointByID(string) (*hcsshim.HNSEndpoint, error)
 	GetHNSEndpointByName(string) (*hcsshim.HNSEndpoint, error)
+	HNSListEndpointRequest() ([]hcsshim.HNSEndpoint, error)
 	DeleteEndpoint(*hcsshim.HNSEndpoint) (*hcsshim.HNSEndpoint, error)
 	HotAttachEndpoint(containerID string, endpointID string, endpointReady func() (bool, error)) error
 	HotDetachEndpoint(containerID string, endpointID string) error
@@ -38,17 +40,40 @@ func NewEndpointManager(hcsClient HCSClient, containerId string, config network.
 	}
 }
 
-func (e *EndpointManager) Create() (hcsshim.HNSEndpoint, error) {
+// Create creates and attaches the container's endpoint. HNS picks a free
+// address unless requestedIP is given. An endpoint left behind by an earlier
+// attempt is adopted rather than duplicated, as long as it has the requested
+// address and DNS settings. DNS settings given for the container take the
+// place of the configured ones.
+func (e *EndpointManager) Create(requestedIP net.IP, dns network.DNSSettings) (hcsshim.HNSEndpoint, error) {
 	network, err := e.hcsClient.GetHNSNetworkByName(e.config.NetworkName)
 	if err != nil {
 		return hcsshim.HNSEndpoint{}, err
 	}
 
+	existing, err := e.hcsClient.GetHNSEndpointByName(e.containerId)
+	if err != nil {
+		if _, ok := err.(hcsshim.EndpointNotFoundError); !ok {
+			return hcsshim.HNSEndpoint{}, err
+		}
+	}
+
+	if existing != nil {
+		return e.adoptEndpoint(existing, network, requestedIP, dns)
+	}
+
 	endpoint := &hcsshim.HNSEndpoint{
 		VirtualNetwork: network.Id,
 		Name:           e.containerId,
 	}
 
+	if requestedIP != nil {
+		if err := e.checkRequestedIP(requestedIP); err != nil {
+			return hcsshim.HNSEndpoint{}, err
+		}
+		endpoint.IPAddress = requestedIP
+	}
+
 	if e.config.MaximumOutgoingBandwidth != 0 {
 		policy, err := json.Marshal(hcsshim.QosPolicy{
 			Type: hcsshim.QOS,
@@ -61,9 +86,7 @@ func (e *EndpointManager) Create() (hcsshim.HNSEndpoint, error) {
 		endpoint.Policies = []json.RawMessage{policy}
 	}
 
-	if len(e.config.DNSServers) > 0 {
-		endpoint.DNSServerList = strings.Join(e.config.DNSServers, ",")
-	}
+	endpoint.DNSServerList, endpoint.DNSSuffix = e.dnsSettings(dns)
 
 	createdEndpoint, err := e.createEndpoint(endpoint)
 	if err != nil {
@@ -82,6 +105,92 @@ func (e *EndpointManager) Create() (hcsshim.HNSEndpoint, error) {
 	return *attachedEndpoint, nil
 }
 
+func (e *EndpointManager) adoptEndpoint(endpoint *hcsshim.HNSEndpoint, network *hcsshim.HNSNetwork, requestedIP net.IP, dns network.DNSSettings) (hcsshim.HNSEndpoint, error) {
+	if endpoint.VirtualNetwork != network.Id {
+		return hcsshim.HNSEndpoint{}, fmt.Errorf("endpoint %s exists on network %s", endpoint.Name, endpoint.VirtualNetworkName)
+	}
+
+	if requestedIP != nil && !requestedIP.Equal(endpoint.IPAddress) {
+		return hcsshim.HNSEndpoint{}, fmt.Errorf("endpoint %s exists with ip %s", endpoint.Name, endpoint.IPAddress)
+	}
+
+	// HNS cannot change the DNS settings of an existing endpoint, and may
+	// fill in those that were not asked for
+	servers, suffix := e.dnsSettings(dns)
+	if (servers != "" && endpoint.DNSServerList != servers) || (suffix != "" && endpoint.DNSSuffix != suffix) {
+		return hcsshim.HNSEndpoint{}, fmt.Errorf("endpoint %s exists with dns servers %q and search domains %q", endpoint.Name, endpoint.DNSServerList, endpoint.DNSSuffix)
+	}
+
+	logrus.Debugf("adopting existing endpoint %s", endpoint.Id)
+
+	for _, container := range endpoint.SharedContainers {
+		if strings.EqualFold(container, e.containerId) {
+			return *endpoint, nil
+		}
+	}
+
+	attachedEndpoint, err := e.attachEndpoint(endpoint)
+	if err != nil {
+		return hcsshim.HNSEndpoint{}, err
+	}
+
+	return *attachedEndpoint, nil
+}
+
+// dnsSettings returns the DNS server list and suffix of the container's
+// endpoint
+func (e *EndpointManager) dnsSettings(dns network.DNSSettings) (string, string) {
+	return strings.Join(e.config.EndpointDNSServers(dns), ","), strings.Join(dns.SearchDomains, ",")
+}
+
+func (e *EndpointManager) checkRequestedIP(ip net.IP) error {
+	// HNS ignores the address of endpoints whose address is leased
+	if e.config.IPAssignment == network.IPAssignmentDHCP {
+		return &network.RequestedIPError{IP: ip.String(), Reason: fmt.Sprintf("network %s assigns addresses with dhcp", e.config.NetworkName)}
+	}
+
+	_, subnet, err := net.ParseCIDR(e.config.SubnetRange)
+	if err != nil {
+		return &network.RequestedIPError{IP: ip.String(), Reason: fmt.Sprintf("network %s has no subnet to assign it from", e.config.NetworkName)}
+	}
+
+	if !subnet.Contains(ip) {
+		return &network.RequestedIPError{IP: ip.String(), Reason: fmt.Sprintf("not in subnet %s", e.config.SubnetRange)}
+	}
+
+	if ip.Equal(subnet.IP) || ip.Equal(broadcastAddress(subnet)) || ip.Equal(net.ParseIP(e.config.GatewayAddress)) {
+		return &network.RequestedIPError{IP: ip.String(), Reason: "reserved by the network"}
+	}
+
+	endpoints, err := e.hcsClient.HNSListEndpointRequest()
+	if err != nil {
+		return err
+	}
+
+	for _, endpoint := range endpoints {
+		if endpoint.IPAddress.Equal(ip) {
+			return &network.RequestedIPError{IP: ip.String(), Reason: fmt.Sprintf("in use by endpoint %s", endpoint.Name)}
+		}
+	}
+
+	return nil
+}
+
+// broadcastAddress returns the last address of an IPv4 subnet, or nil for
+// IPv6 subnets, which have no broadcast address
+func broadcastAddress(subnet *net.IPNet) net.IP {
+	ip := subnet.IP.To4()
+	if ip == nil || len(subnet.Mask) != net.IPv4len {
+		return nil
+	}
+
+	broadcast := make(net.IP, net.IPv4len)
+	for i := range ip {
+		broadcast[i] = ip[i] | ^subnet.Mask[i]
+	}
+	return broadcast
+}
+
 func (e *EndpointManager) attachEndpoint(endpoint *hcsshim.HNSEndpoint) (*hcsshim.HNSEndpoint, error) {
 	endpointReady := func() (bool, error) {
 		interfaceAlias := fmt.Sprintf("vEthernet (%s)", e.containerId)
@@ -101,12 +210,28 @@ func (e *EndpointManager) attachEndpoint(endpoint *hcsshim.HNSEndpoint) (*hcsshi
 	return allocatedEndpoint, nil
 }
 
+// Get returns the endpoint of the running container
+func (e *EndpointManager) Get() (hcsshim.HNSEndpoint, error) {
+	endpoint, err := e.hcsClient.GetHNSEndpointByName(e.containerId)
+	if err != nil {
+		return hcsshim.HNSEndpoint{}, err
+	}
+
+	return *endpoint, nil
+}
+
 func (e *EndpointManager) ApplyPolicies(endpoint hcsshim.HNSEndpoint, nats []*hcsshim.NatPolicy, acls []*hcsshim.ACLPolicy) (hcsshim.HNSEndpoint, error) {
 	var policies []json.RawMessage
 
-	if len(acls) == 0 {
+	// policies may be added to a running container, so drop any block all
+	// ACLs applied earlier and only restore them if there are still no rules
+	// allowing traffic
+	existing, hasAllowACLs := withoutDefaultBlockACLs(endpoint.Policies)
+	endpoint.Policies = existing
+
+	if !hasAllowACLs && !containsAllowACL(acls) {
 		// make sure everything's blocked if no netout rules present
-		acls = []*hcsshim.ACLPolicy{
+		acls = append(acls, []*hcsshim.ACLPolicy{
 			{
 				Type:      hcsshim.ACL,
 				Action:    hcsshim.Block,
@@ -119,7 +244,7 @@ func (e *EndpointManager) ApplyPolicies(endpoint hcsshim.HNSEndpoint, nats []*hc
 				Direction: hcsshim.In,
 				Protocol:  uint16(firewall.NET_FW_IP_PROTOCOL_ANY),
 			},
-		}
+		}...)
 	}
 
 	for _, acl := range acls {
@@ -148,6 +273,77 @@ func (e *EndpointManager) ApplyPolicies(endpoint hcsshim.HNSEndpoint, nats []*hc
 	return *updatedEndpoint, nil
 }
 
+// SetMaximumOutgoingBandwidth replaces the QoS policy of the endpoint; zero
+// removes the limit
+func (e *EndpointManager) SetMaximumOutgoingBandwidth(endpoint hcsshim.HNSEndpoint, bytes uint64) (hcsshim.HNSEndpoint, error) {
+	policies := []json.RawMessage{}
+	for _, policy := range endpoint.Policies {
+		p := hcsshim.Policy{}
+		if err := json.Unmarshal(policy, &p); err == nil && p.Type == hcsshim.QOS {
+			continue
+		}
+		policies = append(policies, policy)
+	}
+
+	if bytes != 0 {
+		policy, err := json.Marshal(hcsshim.QosPolicy{Type: hcsshim.QOS, MaximumOutgoingBandwidthInBytes: bytes})
+		if err != nil {
+			return hcsshim.HNSEndpoint{}, err
+		}
+		policies = append(policies, policy)
+	}
+	endpoint.Policies = policies
+
+	updatedEndpoint, err := e.hcsClient.UpdateEndpoint(&endpoint)
+	if err != nil {
+		return hcsshim.HNSEndpoint{}, err
+	}
+
+	return *updatedEndpoint, nil
+}
+
+func withoutDefaultBlockACLs(policies []json.RawMessage) ([]json.RawMessage, bool) {
+	kept := []json.RawMessage{}
+	hasAllowACLs := false
+
+	for _, policy := range policies {
+		acl := hcsshim.ACLPolicy{}
+		if err := json.Unmarshal(policy, &acl); err != nil || acl.Type != hcsshim.ACL {
+			kept = append(kept, policy)
+			continue
+		}
+
+		if isDefaultBlockACL(acl) {
+			continue
+		}
+
+		kept = append(kept, policy)
+		if acl.Action == hcsshim.Allow {
+			hasAllowACLs = true
+		}
+	}
+
+	return kept, hasAllowACLs
+}
+
+func containsAllowACL(acls []*hcsshim.ACLPolicy) bool {
+	for _, acl := range acls {
+		if acl.Action == hcsshim.Allow {
+			return true
+		}
+	}
+	return false
+}
+
+func isDefaultBlockACL(acl hcsshim.ACLPolicy) bool {
+	// deny rules always carry a priority, even when they block everything
+	return acl.Action == hcsshim.Block &&
+		acl.Priority == 0 &&
+		acl.Protocol == uint16(firewall.NET_FW_IP_PROTOCOL_ANY) &&
+		acl.LocalAddresses == "" && acl.RemoteAddresses == "" &&
+		acl.LocalPorts == "" && acl.RemotePorts == ""
+}
+
 func (e *EndpointManager) Delete() error {
 	endpoint, err := e.hcsClient.GetHNSEndpointByName(e.containerId)
 	if err != nil {
@@ -4,10 +4,12 @@ import (
 	"encoding/json"
 	"errors"
 	"io/ioutil"
+	"net"
 
 	"code.cloudfoundry.org/winc/network"
 	"code.cloudfoundry.org/winc/network/endpoint"
 	"code.cloudfoundry.org/winc/network/endpoint/fakes"
+	"code.cloudfoundry.org/winc/network/firewall"
 	"github.com/Microsoft/hcsshim"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -50,7 +52,7 @@ var _ = Describe("EndpointManager", func() {
 		})
 
 		It("creates an endpoint on the configured network, attaches it to the container", func() {
-			ep, err := endpointManager.Create()
+			ep, err := endpointManager.Create(nil, network.DNSSettings{})
 			Expect(err).NotTo(HaveOccurred())
 			Expect(ep.Id).To(Equal(endpointId))
 
@@ -74,7 +76,7 @@ var _ = Describe("EndpointManager", func() {
 			})
 
 			It("adds a QOS policy with the correct bandwidth", func() {
-				_, err := endpointManager.Create()
+				_, err := endpointManager.Create(nil, network.DNSSettings{})
 				Expect(err).NotTo(HaveOccurred())
 
 				endpointToCreate := hcsClient.CreateEndpointArgsForCall(0)
@@ -87,13 +89,241 @@ var _ = Describe("EndpointManager", func() {
 			})
 		})
 
+		Context("an endpoint for the container already exists", func() {
+			var existing *hcsshim.HNSEndpoint
+
+			BeforeEach(func() {
+				existing = &hcsshim.HNSEndpoint{
+					Id:               "existing-endpoint",
+					Name:             containerId,
+					VirtualNetwork:   networkId,
+					IPAddress:        net.ParseIP("172.30.1.20"),
+					DNSServerList:    "1.1.1.1,2.2.2.2",
+					SharedContainers: []string{containerId},
+				}
+				hcsClient.GetHNSEndpointByNameReturns(existing, nil)
+			})
+
+			It("adopts it without creating another endpoint", func() {
+				ep, err := endpointManager.Create(nil, network.DNSSettings{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(ep).To(Equal(*existing))
+
+				Expect(hcsClient.GetHNSEndpointByNameArgsForCall(0)).To(Equal(containerId))
+				Expect(hcsClient.CreateEndpointCallCount()).To(Equal(0))
+				Expect(hcsClient.HotAttachEndpointCallCount()).To(Equal(0))
+			})
+
+			Context("it is not attached to the container", func() {
+				BeforeEach(func() {
+					existing.SharedContainers = nil
+					hcsClient.GetHNSEndpointByIDReturns(existing, nil)
+				})
+
+				It("attaches it", func() {
+					_, err := endpointManager.Create(nil, network.DNSSettings{})
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(hcsClient.CreateEndpointCallCount()).To(Equal(0))
+					Expect(hcsClient.HotAttachEndpointCallCount()).To(Equal(1))
+					cId, eId, _ := hcsClient.HotAttachEndpointArgsForCall(0)
+					Expect(cId).To(Equal(containerId))
+					Expect(eId).To(Equal("existing-endpoint"))
+				})
+			})
+
+			Context("it is on another network", func() {
+				BeforeEach(func() {
+					existing.VirtualNetwork = "other-network-id"
+					existing.VirtualNetworkName = "other-network"
+				})
+
+				It("returns an error", func() {
+					_, err := endpointManager.Create(nil, network.DNSSettings{})
+					Expect(err).To(MatchError("endpoint containerid-1234 exists on network other-network"))
+				})
+			})
+
+			Context("it has a different ip than the requested one", func() {
+				It("returns an error", func() {
+					_, err := endpointManager.Create(net.ParseIP("172.30.1.21"), network.DNSSettings{})
+					Expect(err).To(MatchError("endpoint containerid-1234 exists with ip 172.30.1.20"))
+				})
+			})
+
+			Context("it has other dns servers than the requested ones", func() {
+				It("returns an error", func() {
+					_, err := endpointManager.Create(nil, network.DNSSettings{Servers: []string{"8.8.8.8"}})
+					Expect(err).To(MatchError(`endpoint containerid-1234 exists with dns servers "1.1.1.1,2.2.2.2" and search domains ""`))
+					Expect(hcsClient.HotAttachEndpointCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("it has other dns search domains than the requested ones", func() {
+				BeforeEach(func() {
+					existing.DNSSuffix = "old.example.com"
+				})
+
+				It("returns an error", func() {
+					_, err := endpointManager.Create(nil, network.DNSSettings{SearchDomains: []string{"example.com"}})
+					Expect(err).To(MatchError(`endpoint containerid-1234 exists with dns servers "1.1.1.1,2.2.2.2" and search domains "old.example.com"`))
+				})
+			})
+		})
+
+		Context("looking up an existing endpoint fails", func() {
+			BeforeEach(func() {
+				hcsClient.GetHNSEndpointByNameReturns(nil, errors.New("hns unavailable"))
+			})
+
+			It("returns the error without creating an endpoint", func() {
+				_, err := endpointManager.Create(nil, network.DNSSettings{})
+				Expect(err).To(MatchError("hns unavailable"))
+				Expect(hcsClient.CreateEndpointCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("no endpoint for the container exists", func() {
+			BeforeEach(func() {
+				hcsClient.GetHNSEndpointByNameReturns(nil, hcsshim.EndpointNotFoundError{EndpointName: containerId})
+			})
+
+			It("creates one", func() {
+				_, err := endpointManager.Create(nil, network.DNSSettings{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(hcsClient.CreateEndpointCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("the container has its own DNS settings", func() {
+			It("uses them instead of the configured servers", func() {
+				_, err := endpointManager.Create(nil, network.DNSSettings{
+					Servers:       []string{"9.9.9.9", "8.8.8.8"},
+					SearchDomains: []string{"apps.internal", "example.com"},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				endpointToCreate := hcsClient.CreateEndpointArgsForCall(0)
+				Expect(endpointToCreate.DNSServerList).To(Equal("9.9.9.9,8.8.8.8"))
+				Expect(endpointToCreate.DNSSuffix).To(Equal("apps.internal,example.com"))
+			})
+		})
+
+		Context("an ip is requested", func() {
+			var requestedIP net.IP
+
+			BeforeEach(func() {
+				config.SubnetRange = "172.30.0.0/22"
+				config.GatewayAddress = "172.30.0.1"
+				endpointManager = endpoint.NewEndpointManager(hcsClient, containerId, config)
+
+				requestedIP = net.ParseIP("172.30.1.20")
+				hcsClient.HNSListEndpointRequestReturns([]hcsshim.HNSEndpoint{
+					{Name: "other-container", IPAddress: net.ParseIP("172.30.1.21")},
+				}, nil)
+			})
+
+			It("creates the endpoint with that ip", func() {
+				_, err := endpointManager.Create(requestedIP, network.DNSSettings{})
+				Expect(err).NotTo(HaveOccurred())
+
+				endpointToCreate := hcsClient.CreateEndpointArgsForCall(0)
+				Expect(endpointToCreate.IPAddress.Equal(requestedIP)).To(BeTrue())
+			})
+
+			Context("the ip is outside the subnet", func() {
+				BeforeEach(func() {
+					requestedIP = net.ParseIP("172.30.4.1")
+				})
+
+				It("returns an error without creating the endpoint", func() {
+					_, err := endpointManager.Create(requestedIP, network.DNSSettings{})
+					Expect(err).To(MatchError(&network.RequestedIPError{IP: "172.30.4.1", Reason: "not in subnet 172.30.0.0/22"}))
+					Expect(hcsClient.CreateEndpointCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("the ip is the gateway", func() {
+				BeforeEach(func() {
+					requestedIP = net.ParseIP("172.30.0.1")
+				})
+
+				It("returns an error without creating the endpoint", func() {
+					_, err := endpointManager.Create(requestedIP, network.DNSSettings{})
+					Expect(err).To(MatchError("invalid requested ip 172.30.0.1: reserved by the network"))
+					Expect(hcsClient.CreateEndpointCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("the ip is the broadcast address", func() {
+				BeforeEach(func() {
+					requestedIP = net.ParseIP("172.30.3.255")
+				})
+
+				It("returns an error without creating the endpoint", func() {
+					_, err := endpointManager.Create(requestedIP, network.DNSSettings{})
+					Expect(err).To(MatchError("invalid requested ip 172.30.3.255: reserved by the network"))
+					Expect(hcsClient.CreateEndpointCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("the network assigns addresses with dhcp", func() {
+				BeforeEach(func() {
+					config.NetworkType = network.NetworkTypeTransparent
+					config.IPAssignment = network.IPAssignmentDHCP
+					endpointManager = endpoint.NewEndpointManager(hcsClient, containerId, config)
+				})
+
+				It("returns an error without creating the endpoint", func() {
+					_, err := endpointManager.Create(requestedIP, network.DNSSettings{})
+					Expect(err).To(MatchError("invalid requested ip 172.30.1.20: network some-network-name assigns addresses with dhcp"))
+					Expect(hcsClient.CreateEndpointCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("the ip is used by another endpoint", func() {
+				BeforeEach(func() {
+					requestedIP = net.ParseIP("172.30.1.21")
+				})
+
+				It("returns an error without creating the endpoint", func() {
+					_, err := endpointManager.Create(requestedIP, network.DNSSettings{})
+					Expect(err).To(MatchError("invalid requested ip 172.30.1.21: in use by endpoint other-container"))
+					Expect(hcsClient.CreateEndpointCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("listing the endpoints fails", func() {
+				BeforeEach(func() {
+					hcsClient.HNSListEndpointRequestReturns(nil, errors.New("hns unavailable"))
+				})
+
+				It("returns the error", func() {
+					_, err := endpointManager.Create(requestedIP, network.DNSSettings{})
+					Expect(err).To(MatchError("hns unavailable"))
+				})
+			})
+
+			Context("the network has no subnet", func() {
+				BeforeEach(func() {
+					config.SubnetRange = ""
+					endpointManager = endpoint.NewEndpointManager(hcsClient, containerId, config)
+				})
+
+				It("returns an error", func() {
+					_, err := endpointManager.Create(requestedIP, network.DNSSettings{})
+					Expect(err).To(MatchError("invalid requested ip 172.30.1.20: network some-network-name has no subnet to assign it from"))
+				})
+			})
+		})
+
 		Context("the network does not already exist", func() {
 			BeforeEach(func() {
 				hcsClient.GetHNSNetworkByNameReturns(nil, hcsshim.NetworkNotFoundError{NetworkName: networkName})
 			})
 
 			It("returns an error", func() {
-				_, err := endpointManager.Create()
+				_, err := endpointManager.Create(nil, network.DNSSettings{})
 				Expect(err).To(BeAssignableToTypeOf(hcsshim.NetworkNotFoundError{}))
 			})
 		})
@@ -107,7 +337,7 @@ var _ = Describe("EndpointManager", func() {
 				})
 
 				It("retries creating the endpoint", func() {
-					ep, err := endpointManager.Create()
+					ep, err := endpointManager.Create(nil, network.DNSSettings{})
 					Expect(err).NotTo(HaveOccurred())
 					Expect(ep.Id).To(Equal(endpointId))
 				})
@@ -119,7 +349,7 @@ var _ = Describe("EndpointManager", func() {
 				})
 
 				It("returns an error", func() {
-					_, err := endpointManager.Create()
+					_, err := endpointManager.Create(nil, network.DNSSettings{})
 					Expect(err).To(MatchError("HNS failed with error : Unspecified error"))
 					Expect(hcsClient.CreateEndpointCallCount()).To(Equal(3))
 				})
@@ -131,7 +361,7 @@ var _ = Describe("EndpointManager", func() {
 				})
 
 				It("does not retry", func() {
-					_, err := endpointManager.Create()
+					_, err := endpointManager.Create(nil, network.DNSSettings{})
 					Expect(err).To(MatchError("cannot create endpoint"))
 					Expect(hcsClient.CreateEndpointCallCount()).To(Equal(1))
 				})
@@ -144,7 +374,7 @@ var _ = Describe("EndpointManager", func() {
 			})
 
 			It("deletes the endpoint and returns an error", func() {
-				_, err := endpointManager.Create()
+				_, err := endpointManager.Create(nil, network.DNSSettings{})
 				Expect(err).To(MatchError("couldn't attach endpoint"))
 
 				Expect(hcsClient.DeleteEndpointCallCount()).To(Equal(1))
@@ -159,7 +389,7 @@ var _ = Describe("EndpointManager", func() {
 			})
 
 			It("deletes the endpoint and returns an error", func() {
-				_, err := endpointManager.Create()
+				_, err := endpointManager.Create(nil, network.DNSSettings{})
 				Expect(err).To(MatchError("couldn't load"))
 
 				Expect(hcsClient.DeleteEndpointCallCount()).To(Equal(1))
@@ -169,6 +399,30 @@ var _ = Describe("EndpointManager", func() {
 		})
 	})
 
+	Describe("Get", func() {
+		It("looks up the endpoint by the container id", func() {
+			hcsClient.GetHNSEndpointByNameReturns(&hcsshim.HNSEndpoint{Id: endpointId, Name: containerId}, nil)
+
+			ep, err := endpointManager.Get()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ep.Id).To(Equal(endpointId))
+
+			Expect(hcsClient.GetHNSEndpointByNameCallCount()).To(Equal(1))
+			Expect(hcsClient.GetHNSEndpointByNameArgsForCall(0)).To(Equal(containerId))
+		})
+
+		Context("the endpoint does not exist", func() {
+			BeforeEach(func() {
+				hcsClient.GetHNSEndpointByNameReturns(nil, hcsshim.EndpointNotFoundError{EndpointName: containerId})
+			})
+
+			It("returns the error", func() {
+				_, err := endpointManager.Get()
+				Expect(err).To(MatchError(hcsshim.EndpointNotFoundError{EndpointName: containerId}))
+			})
+		})
+	})
+
 	Describe("ApplyPolicies", func() {
 		var (
 			nat1            *hcsshim.NatPolicy
@@ -274,6 +528,113 @@ var _ = Describe("EndpointManager", func() {
 			})
 		})
 
+		Context("the endpoint already has the default block all ACLs", func() {
+			var blockIn, blockOut json.RawMessage
+
+			BeforeEach(func() {
+				var err error
+				blockIn, err = json.Marshal(hcsshim.ACLPolicy{Type: hcsshim.ACL, Direction: hcsshim.In, Action: hcsshim.Block, Protocol: 256})
+				Expect(err).NotTo(HaveOccurred())
+				blockOut, err = json.Marshal(hcsshim.ACLPolicy{Type: hcsshim.ACL, Direction: hcsshim.Out, Action: hcsshim.Block, Protocol: 256})
+				Expect(err).NotTo(HaveOccurred())
+
+				endpoint.Policies = append(endpoint.Policies, blockIn, blockOut)
+			})
+
+			It("replaces them with the given ACLs", func() {
+				_, err := endpointManager.ApplyPolicies(endpoint, nil, []*hcsshim.ACLPolicy{acl1})
+				Expect(err).NotTo(HaveOccurred())
+
+				endpointToUpdate := hcsClient.UpdateEndpointArgsForCall(0)
+				Expect(endpointToUpdate.Policies).To(HaveLen(2))
+				Expect(endpointToUpdate.Policies[0]).To(Equal(json.RawMessage("existing policy")))
+
+				acl := hcsshim.ACLPolicy{}
+				Expect(json.Unmarshal(endpointToUpdate.Policies[1], &acl)).To(Succeed())
+				Expect(acl).To(Equal(*acl1))
+			})
+
+			It("does not duplicate them when no ACLs are given", func() {
+				_, err := endpointManager.ApplyPolicies(endpoint, []*hcsshim.NatPolicy{nat1}, nil)
+				Expect(err).NotTo(HaveOccurred())
+
+				endpointToUpdate := hcsClient.UpdateEndpointArgsForCall(0)
+				Expect(endpointToUpdate.Policies).To(HaveLen(4))
+			})
+		})
+
+		Context("the endpoint already has ACLs for earlier rules", func() {
+			BeforeEach(func() {
+				existingACL, err := json.Marshal(acl2)
+				Expect(err).NotTo(HaveOccurred())
+
+				endpoint.Policies = append(endpoint.Policies, existingACL)
+			})
+
+			It("keeps them and does not add the default block all ACLs", func() {
+				_, err := endpointManager.ApplyPolicies(endpoint, []*hcsshim.NatPolicy{nat1}, nil)
+				Expect(err).NotTo(HaveOccurred())
+
+				endpointToUpdate := hcsClient.UpdateEndpointArgsForCall(0)
+				Expect(endpointToUpdate.Policies).To(HaveLen(3))
+
+				acls := []hcsshim.ACLPolicy{}
+				for _, pol := range endpointToUpdate.Policies[1:] {
+					acl := hcsshim.ACLPolicy{}
+					Expect(json.Unmarshal(pol, &acl)).To(Succeed())
+					if acl.Type == hcsshim.ACL {
+						acls = append(acls, acl)
+					}
+				}
+				Expect(acls).To(Equal([]hcsshim.ACLPolicy{*acl2}))
+			})
+		})
+
+		Context("only block ACLs are provided", func() {
+			var deny *hcsshim.ACLPolicy
+
+			BeforeEach(func() {
+				deny = &hcsshim.ACLPolicy{
+					Type:            hcsshim.ACL,
+					Direction:       hcsshim.Out,
+					Action:          hcsshim.Block,
+					Protocol:        uint16(firewall.NET_FW_IP_PROTOCOL_ANY),
+					RemoteAddresses: "169.254.169.254/32",
+					Priority:        100,
+				}
+			})
+
+			It("still adds the default block all ACLs", func() {
+				_, err := endpointManager.ApplyPolicies(endpoint, nil, []*hcsshim.ACLPolicy{deny})
+				Expect(err).NotTo(HaveOccurred())
+
+				endpointToUpdate := hcsClient.UpdateEndpointArgsForCall(0)
+				Expect(endpointToUpdate.Policies).To(HaveLen(4))
+			})
+
+			Context("the deny rule blocks everything", func() {
+				BeforeEach(func() {
+					deny.RemoteAddresses = ""
+
+					existingDeny, err := json.Marshal(deny)
+					Expect(err).NotTo(HaveOccurred())
+					endpoint.Policies = append(endpoint.Policies, existingDeny)
+				})
+
+				It("is not mistaken for a default block all ACL", func() {
+					_, err := endpointManager.ApplyPolicies(endpoint, nil, []*hcsshim.ACLPolicy{acl1})
+					Expect(err).NotTo(HaveOccurred())
+
+					endpointToUpdate := hcsClient.UpdateEndpointArgsForCall(0)
+					Expect(endpointToUpdate.Policies).To(HaveLen(3))
+
+					acl := hcsshim.ACLPolicy{}
+					Expect(json.Unmarshal(endpointToUpdate.Policies[1], &acl)).To(Succeed())
+					Expect(acl).To(Equal(*deny))
+				})
+			})
+		})
+
 		Context("no HNS Nat policies are provided", func() {
 			It("still updates the endpoint", func() {
 				ep, err := endpointManager.ApplyPolicies(endpoint, []*hcsshim.NatPolicy{}, []*hcsshim.ACLPolicy{})
@@ -296,6 +657,55 @@ var _ = Describe("EndpointManager", func() {
 		})
 	})
 
+	Describe("SetMaximumOutgoingBandwidth", func() {
+		var endpoint hcsshim.HNSEndpoint
+
+		BeforeEach(func() {
+			endpoint = hcsshim.HNSEndpoint{
+				Id: endpointId,
+				Policies: []json.RawMessage{
+					[]byte(`{"Type":"ACL","Action":"Allow","Direction":"Out"}`),
+					[]byte(`{"Type":"QOS","MaximumOutgoingBandwidthInBytes":1000}`),
+				},
+			}
+			hcsClient.UpdateEndpointReturns(&hcsshim.HNSEndpoint{Id: endpointId}, nil)
+		})
+
+		It("replaces the QoS policy of the endpoint", func() {
+			ep, err := endpointManager.SetMaximumOutgoingBandwidth(endpoint, 5000)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ep.Id).To(Equal(endpointId))
+
+			Expect(hcsClient.UpdateEndpointCallCount()).To(Equal(1))
+			updated := hcsClient.UpdateEndpointArgsForCall(0)
+			Expect(updated.Policies).To(HaveLen(2))
+			Expect(string(updated.Policies[0])).To(ContainSubstring("ACL"))
+
+			qos := hcsshim.QosPolicy{}
+			Expect(json.Unmarshal(updated.Policies[1], &qos)).To(Succeed())
+			Expect(qos).To(Equal(hcsshim.QosPolicy{Type: hcsshim.QOS, MaximumOutgoingBandwidthInBytes: 5000}))
+		})
+
+		It("removes the limit for zero", func() {
+			_, err := endpointManager.SetMaximumOutgoingBandwidth(endpoint, 0)
+			Expect(err).NotTo(HaveOccurred())
+
+			updated := hcsClient.UpdateEndpointArgsForCall(0)
+			Expect(updated.Policies).To(HaveLen(1))
+		})
+
+		Context("updating the endpoint fails", func() {
+			BeforeEach(func() {
+				hcsClient.UpdateEndpointReturns(nil, errors.New("update failed"))
+			})
+
+			It("returns the error", func() {
+				_, err := endpointManager.SetMaximumOutgoingBandwidth(endpoint, 5000)
+				Expect(err).To(MatchError("update failed"))
+			})
+		})
+	})
+
 	Describe("Delete", func() {
 		var endpoint *hcsshim.HNSEndpoint
 
@@ -9,19 +9,6 @@ import (
 )
 
 type HCSClient struct {
-	GetHNSNetworkByNameStub        func(string) (*hcsshim.HNSNetwork, error)
-	getHNSNetworkByNameMutex       sync.RWMutex
-	getHNSNetworkByNameArgsForCall []struct {
-		arg1 string
-	}
-	getHNSNetworkByNameReturns struct {
-		result1 *hcsshim.HNSNetwork
-		result2 error
-	}
-	getHNSNetworkByNameReturnsOnCall map[int]struct {
-		result1 *hcsshim.HNSNetwork
-		result2 error
-	}
 	CreateEndpointStub        func(*hcsshim.HNSEndpoint) (*hcsshim.HNSEndpoint, error)
 	createEndpointMutex       sync.RWMutex
 	createEndpointArgsForCall []struct {
@@ -35,16 +22,16 @@ type HCSClient struct {
 		result1 *hcsshim.HNSEndpoint
 		result2 error
 	}
-	UpdateEndpointStub        func(*hcsshim.HNSEndpoint) (*hcsshim.HNSEndpoint, error)
-	updateEndpointMutex       sync.RWMutex
-	updateEndpointArgsForCall []struct {
+	DeleteEndpointStub        func(*hcsshim.HNSEndpoint) (*hcsshim.HNSEndpoint, error)
+	deleteEndpointMutex       sync.RWMutex
+	deleteEndpointArgsForCall []struct {
 		arg1 *hcsshim.HNSEndpoint
 	}
-	updateEndpointReturns struct {
+	deleteEndpointReturns struct {
 		result1 *hcsshim.HNSEndpoint
 		result2 error
 	}
-	updateEndpointReturnsOnCall map[int]struct {
+	deleteEndpointReturnsOnCall map[int]struct {
 		result1 *hcsshim.HNSEndpoint
 		result2 error
 	}
@@ -74,25 +61,37 @@ type HCSClient struct {
 		result1 *hcsshim.HNSEndpoint
 		result2 error
 	}
-	DeleteEndpointStub        func(*hcsshim.HNSEndpoint) (*hcsshim.HNSEndpoint, error)
-	deleteEndpointMutex       sync.RWMutex
-	deleteEndpointArgsForCall []struct {
-		arg1 *hcsshim.HNSEndpoint
+	GetHNSNetworkByNameStub        func(string) (*hcsshim.HNSNetwork, error)
+	getHNSNetworkByNameMutex       sync.RWMutex
+	getHNSNetworkByNameArgsForCall []struct {
+		arg1 string
 	}
-	deleteEndpointReturns struct {
-		result1 *hcsshim.HNSEndpoint
+	getHNSNetworkByNameReturns struct {
+		result1 *hcsshim.HNSNetwork
 		result2 error
 	}
-	deleteEndpointReturnsOnCall map[int]struct {
-		result1 *hcsshim.HNSEndpoint
+	getHNSNetworkByNameReturnsOnCall map[int]struct {
+		result1 *hcsshim.HNSNetwork
+		result2 error
+	}
+	HNSListEndpointRequestStub        func() ([]hcsshim.HNSEndpoint, error)
+	hNSListEndpointRequestMutex       sync.RWMutex
+	hNSListEndpointRequestArgsForCall []struct {
+	}
+	hNSListEndpointRequestReturns struct {
+		result1 []hcsshim.HNSEndpoint
 		result2 error
 	}
-	HotAttachEndpointStub        func(containerID string, endpointID string, endpointReady func() (bool, error)) error
+	hNSListEndpointRequestReturnsOnCall map[int]struct {
+		result1 []hcsshim.HNSEndpoint
+		result2 error
+	}
+	HotAttachEndpointStub        func(string, string, func() (bool, error)) error
 	hotAttachEndpointMutex       sync.RWMutex
 	hotAttachEndpointArgsForCall []struct {
-		containerID   string
-		endpointID    string
-		endpointReady func() (bool, error)
+		arg1 string
+		arg2 string
+		arg3 func() (bool, error)
 	}
 	hotAttachEndpointReturns struct {
 		result1 error
@@ -100,11 +99,11 @@ type HCSClient struct {
 	hotAttachEndpointReturnsOnCall map[int]struct {
 		result1 error
 	}
-	HotDetachEndpointStub        func(containerID string, endpointID string) error
+	HotDetachEndpointStub        func(string, string) error
 	hotDetachEndpointMutex       sync.RWMutex
 	hotDetachEndpointArgsForCall []struct {
-		containerID string
-		endpointID  string
+		arg1 string
+		arg2 string
 	}
 	hotDetachEndpointReturns struct {
 		result1 error
@@ -112,59 +111,21 @@ type HCSClient struct {
 	hotDetachEndpointReturnsOnCall map[int]struct {
 		result1 error
 	}
-	invocations      map[string][][]interface{}
-	invocationsMutex sync.RWMutex
-}
-
-func (fake *HCSClient) GetHNSNetworkByName(arg1 string) (*hcsshim.HNSNetwork, error) {
-	fake.getHNSNetworkByNameMutex.Lock()
-	ret, specificReturn := fake.getHNSNetworkByNameReturnsOnCall[len(fake.getHNSNetworkByNameArgsForCall)]
-	fake.getHNSNetworkByNameArgsForCall = append(fake.getHNSNetworkByNameArgsForCall, struct {
-		arg1 string
-	}{arg1})
-	fake.recordInvocation("GetHNSNetworkByName", []interface{}{arg1})
-	fake.getHNSNetworkByNameMutex.Unlock()
-	if fake.GetHNSNetworkByNameStub != nil {
-		return fake.GetHNSNetworkByNameStub(arg1)
-	}
-	if specificReturn {
-		return ret.result1, ret.result2
+	UpdateEndpointStub        func(*hcsshim.HNSEndpoint) (*hcsshim.HNSEndpoint, error)
+	updateEndpointMutex       sync.RWMutex
+	updateEndpointArgsForCall []struct {
+		arg1 *hcsshim.HNSEndpoint
 	}
-	return fake.getHNSNetworkByNameReturns.result1, fake.getHNSNetworkByNameReturns.result2
-}
-
-func (fake *HCSClient) GetHNSNetworkByNameCallCount() int {
-	fake.getHNSNetworkByNameMutex.RLock()
-	defer fake.getHNSNetworkByNameMutex.RUnlock()
-	return len(fake.getHNSNetworkByNameArgsForCall)
-}
-
-func (fake *HCSClient) GetHNSNetworkByNameArgsForCall(i int) string {
-	fake.getHNSNetworkByNameMutex.RLock()
-	defer fake.getHNSNetworkByNameMutex.RUnlock()
-	return fake.getHNSNetworkByNameArgsForCall[i].arg1
-}
-
-func (fake *HCSClient) GetHNSNetworkByNameReturns(result1 *hcsshim.HNSNetwork, result2 error) {
-	fake.GetHNSNetworkByNameStub = nil
-	fake.getHNSNetworkByNameReturns = struct {
-		result1 *hcsshim.HNSNetwork
+	updateEndpointReturns struct {
+		result1 *hcsshim.HNSEndpoint
 		result2 error
-	}{result1, result2}
-}
-
-func (fake *HCSClient) GetHNSNetworkByNameReturnsOnCall(i int, result1 *hcsshim.HNSNetwork, result2 error) {
-	fake.GetHNSNetworkByNameStub = nil
-	if fake.getHNSNetworkByNameReturnsOnCall == nil {
-		fake.getHNSNetworkByNameReturnsOnCall = make(map[int]struct {
-			result1 *hcsshim.HNSNetwork
-			result2 error
-		})
 	}
-	fake.getHNSNetworkByNameReturnsOnCall[i] = struct {
-		result1 *hcsshim.HNSNetwork
+	updateEndpointReturnsOnCall map[int]struct {
+		result1 *hcsshim.HNSEndpoint
 		result2 error
-	}{result1, result2}
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
 }
 
 func (fake *HCSClient) CreateEndpoint(arg1 *hcsshim.HNSEndpoint) (*hcsshim.HNSEndpoint, error) {
@@ -173,15 +134,17 @@ func (fake *HCSClient) CreateEndpoint(arg1 *hcsshim.HNSEndpoint) (*hcsshim.HNSEn
 	fake.createEndpointArgsForCall = append(fake.createEndpointArgsForCall, struct {
 		arg1 *hcsshim.HNSEndpoint
 	}{arg1})
+	stub := fake.CreateEndpointStub
+	fakeReturns := fake.createEndpointReturns
 	fake.recordInvocation("CreateEndpoint", []interface{}{arg1})
 	fake.createEndpointMutex.Unlock()
-	if fake.CreateEndpointStub != nil {
-		return fake.CreateEndpointStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	return fake.createEndpointReturns.result1, fake.createEndpointReturns.result2
+	return fakeReturns.result1, fakeReturns.result2
 }
 
 func (fake *HCSClient) CreateEndpointCallCount() int {
@@ -190,13 +153,22 @@ func (fake *HCSClient) CreateEndpointCallCount() int {
 	return len(fake.createEndpointArgsForCall)
 }
 
+func (fake *HCSClient) CreateEndpointCalls(stub func(*hcsshim.HNSEndpoint) (*hcsshim.HNSEndpoint, error)) {
+	fake.createEndpointMutex.Lock()
+	defer fake.createEndpointMutex.Unlock()
+	fake.CreateEndpointStub = stub
+}
+
 func (fake *HCSClient) CreateEndpointArgsForCall(i int) *hcsshim.HNSEndpoint {
 	fake.createEndpointMutex.RLock()
 	defer fake.createEndpointMutex.RUnlock()
-	return fake.createEndpointArgsForCall[i].arg1
+	argsForCall := fake.createEndpointArgsForCall[i]
+	return argsForCall.arg1
 }
 
 func (fake *HCSClient) CreateEndpointReturns(result1 *hcsshim.HNSEndpoint, result2 error) {
+	fake.createEndpointMutex.Lock()
+	defer fake.createEndpointMutex.Unlock()
 	fake.CreateEndpointStub = nil
 	fake.createEndpointReturns = struct {
 		result1 *hcsshim.HNSEndpoint
@@ -205,6 +177,8 @@ func (fake *HCSClient) CreateEndpointReturns(result1 *hcsshim.HNSEndpoint, resul
 }
 
 func (fake *HCSClient) CreateEndpointReturnsOnCall(i int, result1 *hcsshim.HNSEndpoint, result2 error) {
+	fake.createEndpointMutex.Lock()
+	defer fake.createEndpointMutex.Unlock()
 	fake.CreateEndpointStub = nil
 	if fake.createEndpointReturnsOnCall == nil {
 		fake.createEndpointReturnsOnCall = make(map[int]struct {
@@ -218,52 +192,65 @@ func (fake *HCSClient) CreateEndpointReturnsOnCall(i int, result1 *hcsshim.HNSEn
 	}{result1, result2}
 }
 
-func (fake *HCSClient) UpdateEndpoint(arg1 *hcsshim.HNSEndpoint) (*hcsshim.HNSEndpoint, error) {
-	fake.updateEndpointMutex.Lock()
-	ret, specificReturn := fake.updateEndpointReturnsOnCall[len(fake.updateEndpointArgsForCall)]
-	fake.updateEndpointArgsForCall = append(fake.updateEndpointArgsForCall, struct {
+func (fake *HCSClient) DeleteEndpoint(arg1 *hcsshim.HNSEndpoint) (*hcsshim.HNSEndpoint, error) {
+	fake.deleteEndpointMutex.Lock()
+	ret, specificReturn := fake.deleteEndpointReturnsOnCall[len(fake.deleteEndpointArgsForCall)]
+	fake.deleteEndpointArgsForCall = append(fake.deleteEndpointArgsForCall, struct {
 		arg1 *hcsshim.HNSEndpoint
 	}{arg1})
-	fake.recordInvocation("UpdateEndpoint", []interface{}{arg1})
-	fake.updateEndpointMutex.Unlock()
-	if fake.UpdateEndpointStub != nil {
-		return fake.UpdateEndpointStub(arg1)
+	stub := fake.DeleteEndpointStub
+	fakeReturns := fake.deleteEndpointReturns
+	fake.recordInvocation("DeleteEndpoint", []interface{}{arg1})
+	fake.deleteEndpointMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	return fake.updateEndpointReturns.result1, fake.updateEndpointReturns.result2
+	return fakeReturns.result1, fakeReturns.result2
 }
 
-func (fake *HCSClient) UpdateEndpointCallCount() int {
-	fake.updateEndpointMutex.RLock()
-	defer fake.updateEndpointMutex.RUnlock()
-	return len(fake.updateEndpointArgsForCall)
+func (fake *HCSClient) DeleteEndpointCallCount() int {
+	fake.deleteEndpointMutex.RLock()
+	defer fake.deleteEndpointMutex.RUnlock()
+	return len(fake.deleteEndpointArgsForCall)
 }
 
-func (fake *HCSClient) UpdateEndpointArgsForCall(i int) *hcsshim.HNSEndpoint {
-	fake.updateEndpointMutex.RLock()
-	defer fake.updateEndpointMutex.RUnlock()
-	return fake.updateEndpointArgsForCall[i].arg1
+func (fake *HCSClient) DeleteEndpointCalls(stub func(*hcsshim.HNSEndpoint) (*hcsshim.HNSEndpoint, error)) {
+	fake.deleteEndpointMutex.Lock()
+	defer fake.deleteEndpointMutex.Unlock()
+	fake.DeleteEndpointStub = stub
 }
 
-func (fake *HCSClient) UpdateEndpointReturns(result1 *hcsshim.HNSEndpoint, result2 error) {
-	fake.UpdateEndpointStub = nil
-	fake.updateEndpointReturns = struct {
+func (fake *HCSClient) DeleteEndpointArgsForCall(i int) *hcsshim.HNSEndpoint {
+	fake.deleteEndpointMutex.RLock()
+	defer fake.deleteEndpointMutex.RUnlock()
+	argsForCall := fake.deleteEndpointArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *HCSClient) DeleteEndpointReturns(result1 *hcsshim.HNSEndpoint, result2 error) {
+	fake.deleteEndpointMutex.Lock()
+	defer fake.deleteEndpointMutex.Unlock()
+	fake.DeleteEndpointStub = nil
+	fake.deleteEndpointReturns = struct {
 		result1 *hcsshim.HNSEndpoint
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *HCSClient) UpdateEndpointReturnsOnCall(i int, result1 *hcsshim.HNSEndpoint, result2 error) {
-	fake.UpdateEndpointStub = nil
-	if fake.updateEndpointReturnsOnCall == nil {
-		fake.updateEndpointReturnsOnCall = make(map[int]struct {
+func (fake *HCSClient) DeleteEndpointReturnsOnCall(i int, result1 *hcsshim.HNSEndpoint, result2 error) {
+	fake.deleteEndpointMutex.Lock()
+	defer fake.deleteEndpointMutex.Unlock()
+	fake.DeleteEndpointStub = nil
+	if fake.deleteEndpointReturnsOnCall == nil {
+		fake.deleteEndpointReturnsOnCall = make(map[int]struct {
 			result1 *hcsshim.HNSEndpoint
 			result2 error
 		})
 	}
-	fake.updateEndpointReturnsOnCall[i] = struct {
+	fake.deleteEndpointReturnsOnCall[i] = struct {
 		result1 *hcsshim.HNSEndpoint
 		result2 error
 	}{result1, result2}
@@ -275,15 +262,17 @@ func (fake *HCSClient) GetHNSEndpointByID(arg1 string) (*hcsshim.HNSEndpoint, er
 	fake.getHNSEndpointByIDArgsForCall = append(fake.getHNSEndpointByIDArgsForCall, struct {
 		arg1 string
 	}{arg1})
+	stub := fake.GetHNSEndpointByIDStub
+	fakeReturns := fake.getHNSEndpointByIDReturns
 	fake.recordInvocation("GetHNSEndpointByID", []interface{}{arg1})
 	fake.getHNSEndpointByIDMutex.Unlock()
-	if fake.GetHNSEndpointByIDStub != nil {
-		return fake.GetHNSEndpointByIDStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	return fake.getHNSEndpointByIDReturns.result1, fake.getHNSEndpointByIDReturns.result2
+	return fakeReturns.result1, fakeReturns.result2
 }
 
 func (fake *HCSClient) GetHNSEndpointByIDCallCount() int {
@@ -292,13 +281,22 @@ func (fake *HCSClient) GetHNSEndpointByIDCallCount() int {
 	return len(fake.getHNSEndpointByIDArgsForCall)
 }
 
+func (fake *HCSClient) GetHNSEndpointByIDCalls(stub func(string) (*hcsshim.HNSEndpoint, error)) {
+	fake.getHNSEndpointByIDMutex.Lock()
+	defer fake.getHNSEndpointByIDMutex.Unlock()
+	fake.GetHNSEndpointByIDStub = stub
+}
+
 func (fake *HCSClient) GetHNSEndpointByIDArgsForCall(i int) string {
 	fake.getHNSEndpointByIDMutex.RLock()
 	defer fake.getHNSEndpointByIDMutex.RUnlock()
-	return fake.getHNSEndpointByIDArgsForCall[i].arg1
+	argsForCall := fake.getHNSEndpointByIDArgsForCall[i]
+	return argsForCall.arg1
 }
 
 func (fake *HCSClient) GetHNSEndpointByIDReturns(result1 *hcsshim.HNSEndpoint, result2 error) {
+	fake.getHNSEndpointByIDMutex.Lock()
+	defer fake.getHNSEndpointByIDMutex.Unlock()
 	fake.GetHNSEndpointByIDStub = nil
 	fake.getHNSEndpointByIDReturns = struct {
 		result1 *hcsshim.HNSEndpoint
@@ -307,6 +305,8 @@ func (fake *HCSClient) GetHNSEndpointByIDReturns(result1 *hcsshim.HNSEndpoint, r
 }
 
 func (fake *HCSClient) GetHNSEndpointByIDReturnsOnCall(i int, result1 *hcsshim.HNSEndpoint, result2 error) {
+	fake.getHNSEndpointByIDMutex.Lock()
+	defer fake.getHNSEndpointByIDMutex.Unlock()
 	fake.GetHNSEndpointByIDStub = nil
 	if fake.getHNSEndpointByIDReturnsOnCall == nil {
 		fake.getHNSEndpointByIDReturnsOnCall = make(map[int]struct {
@@ -326,15 +326,17 @@ func (fake *HCSClient) GetHNSEndpointByName(arg1 string) (*hcsshim.HNSEndpoint,
 	fake.getHNSEndpointByNameArgsForCall = append(fake.getHNSEndpointByNameArgsForCall, struct {
 		arg1 string
 	}{arg1})
+	stub := fake.GetHNSEndpointByNameStub
+	fakeReturns := fake.getHNSEndpointByNameReturns
 	fake.recordInvocation("GetHNSEndpointByName", []interface{}{arg1})
 	fake.getHNSEndpointByNameMutex.Unlock()
-	if fake.GetHNSEndpointByNameStub != nil {
-		return fake.GetHNSEndpointByNameStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	return fake.getHNSEndpointByNameReturns.result1, fake.getHNSEndpointByNameReturns.result2
+	return fakeReturns.result1, fakeReturns.result2
 }
 
 func (fake *HCSClient) GetHNSEndpointByNameCallCount() int {
@@ -343,13 +345,22 @@ func (fake *HCSClient) GetHNSEndpointByNameCallCount() int {
 	return len(fake.getHNSEndpointByNameArgsForCall)
 }
 
+func (fake *HCSClient) GetHNSEndpointByNameCalls(stub func(string) (*hcsshim.HNSEndpoint, error)) {
+	fake.getHNSEndpointByNameMutex.Lock()
+	defer fake.getHNSEndpointByNameMutex.Unlock()
+	fake.GetHNSEndpointByNameStub = stub
+}
+
 func (fake *HCSClient) GetHNSEndpointByNameArgsForCall(i int) string {
 	fake.getHNSEndpointByNameMutex.RLock()
 	defer fake.getHNSEndpointByNameMutex.RUnlock()
-	return fake.getHNSEndpointByNameArgsForCall[i].arg1
+	argsForCall := fake.getHNSEndpointByNameArgsForCall[i]
+	return argsForCall.arg1
 }
 
 func (fake *HCSClient) GetHNSEndpointByNameReturns(result1 *hcsshim.HNSEndpoint, result2 error) {
+	fake.getHNSEndpointByNameMutex.Lock()
+	defer fake.getHNSEndpointByNameMutex.Unlock()
 	fake.GetHNSEndpointByNameStub = nil
 	fake.getHNSEndpointByNameReturns = struct {
 		result1 *hcsshim.HNSEndpoint
@@ -358,6 +369,8 @@ func (fake *HCSClient) GetHNSEndpointByNameReturns(result1 *hcsshim.HNSEndpoint,
 }
 
 func (fake *HCSClient) GetHNSEndpointByNameReturnsOnCall(i int, result1 *hcsshim.HNSEndpoint, result2 error) {
+	fake.getHNSEndpointByNameMutex.Lock()
+	defer fake.getHNSEndpointByNameMutex.Unlock()
 	fake.GetHNSEndpointByNameStub = nil
 	if fake.getHNSEndpointByNameReturnsOnCall == nil {
 		fake.getHNSEndpointByNameReturnsOnCall = make(map[int]struct {
@@ -371,74 +384,145 @@ func (fake *HCSClient) GetHNSEndpointByNameReturnsOnCall(i int, result1 *hcsshim
 	}{result1, result2}
 }
 
-func (fake *HCSClient) DeleteEndpoint(arg1 *hcsshim.HNSEndpoint) (*hcsshim.HNSEndpoint, error) {
-	fake.deleteEndpointMutex.Lock()
-	ret, specificReturn := fake.deleteEndpointReturnsOnCall[len(fake.deleteEndpointArgsForCall)]
-	fake.deleteEndpointArgsForCall = append(fake.deleteEndpointArgsForCall, struct {
-		arg1 *hcsshim.HNSEndpoint
+func (fake *HCSClient) GetHNSNetworkByName(arg1 string) (*hcsshim.HNSNetwork, error) {
+	fake.getHNSNetworkByNameMutex.Lock()
+	ret, specificReturn := fake.getHNSNetworkByNameReturnsOnCall[len(fake.getHNSNetworkByNameArgsForCall)]
+	fake.getHNSNetworkByNameArgsForCall = append(fake.getHNSNetworkByNameArgsForCall, struct {
+		arg1 string
 	}{arg1})
-	fake.recordInvocation("DeleteEndpoint", []interface{}{arg1})
-	fake.deleteEndpointMutex.Unlock()
-	if fake.DeleteEndpointStub != nil {
-		return fake.DeleteEndpointStub(arg1)
+	stub := fake.GetHNSNetworkByNameStub
+	fakeReturns := fake.getHNSNetworkByNameReturns
+	fake.recordInvocation("GetHNSNetworkByName", []interface{}{arg1})
+	fake.getHNSNetworkByNameMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	return fake.deleteEndpointReturns.result1, fake.deleteEndpointReturns.result2
+	return fakeReturns.result1, fakeReturns.result2
 }
 
-func (fake *HCSClient) DeleteEndpointCallCount() int {
-	fake.deleteEndpointMutex.RLock()
-	defer fake.deleteEndpointMutex.RUnlock()
-	return len(fake.deleteEndpointArgsForCall)
+func (fake *HCSClient) GetHNSNetworkByNameCallCount() int {
+	fake.getHNSNetworkByNameMutex.RLock()
+	defer fake.getHNSNetworkByNameMutex.RUnlock()
+	return len(fake.getHNSNetworkByNameArgsForCall)
 }
 
-func (fake *HCSClient) DeleteEndpointArgsForCall(i int) *hcsshim.HNSEndpoint {
-	fake.deleteEndpointMutex.RLock()
-	defer fake.deleteEndpointMutex.RUnlock()
-	return fake.deleteEndpointArgsForCall[i].arg1
+func (fake *HCSClient) GetHNSNetworkByNameCalls(stub func(string) (*hcsshim.HNSNetwork, error)) {
+	fake.getHNSNetworkByNameMutex.Lock()
+	defer fake.getHNSNetworkByNameMutex.Unlock()
+	fake.GetHNSNetworkByNameStub = stub
 }
 
-func (fake *HCSClient) DeleteEndpointReturns(result1 *hcsshim.HNSEndpoint, result2 error) {
-	fake.DeleteEndpointStub = nil
-	fake.deleteEndpointReturns = struct {
-		result1 *hcsshim.HNSEndpoint
+func (fake *HCSClient) GetHNSNetworkByNameArgsForCall(i int) string {
+	fake.getHNSNetworkByNameMutex.RLock()
+	defer fake.getHNSNetworkByNameMutex.RUnlock()
+	argsForCall := fake.getHNSNetworkByNameArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *HCSClient) GetHNSNetworkByNameReturns(result1 *hcsshim.HNSNetwork, result2 error) {
+	fake.getHNSNetworkByNameMutex.Lock()
+	defer fake.getHNSNetworkByNameMutex.Unlock()
+	fake.GetHNSNetworkByNameStub = nil
+	fake.getHNSNetworkByNameReturns = struct {
+		result1 *hcsshim.HNSNetwork
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *HCSClient) DeleteEndpointReturnsOnCall(i int, result1 *hcsshim.HNSEndpoint, result2 error) {
-	fake.DeleteEndpointStub = nil
-	if fake.deleteEndpointReturnsOnCall == nil {
-		fake.deleteEndpointReturnsOnCall = make(map[int]struct {
-			result1 *hcsshim.HNSEndpoint
+func (fake *HCSClient) GetHNSNetworkByNameReturnsOnCall(i int, result1 *hcsshim.HNSNetwork, result2 error) {
+	fake.getHNSNetworkByNameMutex.Lock()
+	defer fake.getHNSNetworkByNameMutex.Unlock()
+	fake.GetHNSNetworkByNameStub = nil
+	if fake.getHNSNetworkByNameReturnsOnCall == nil {
+		fake.getHNSNetworkByNameReturnsOnCall = make(map[int]struct {
+			result1 *hcsshim.HNSNetwork
 			result2 error
 		})
 	}
-	fake.deleteEndpointReturnsOnCall[i] = struct {
-		result1 *hcsshim.HNSEndpoint
+	fake.getHNSNetworkByNameReturnsOnCall[i] = struct {
+		result1 *hcsshim.HNSNetwork
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *HCSClient) HNSListEndpointRequest() ([]hcsshim.HNSEndpoint, error) {
+	fake.hNSListEndpointRequestMutex.Lock()
+	ret, specificReturn := fake.hNSListEndpointRequestReturnsOnCall[len(fake.hNSListEndpointRequestArgsForCall)]
+	fake.hNSListEndpointRequestArgsForCall = append(fake.hNSListEndpointRequestArgsForCall, struct {
+	}{})
+	stub := fake.HNSListEndpointRequestStub
+	fakeReturns := fake.hNSListEndpointRequestReturns
+	fake.recordInvocation("HNSListEndpointRequest", []interface{}{})
+	fake.hNSListEndpointRequestMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *HCSClient) HNSListEndpointRequestCallCount() int {
+	fake.hNSListEndpointRequestMutex.RLock()
+	defer fake.hNSListEndpointRequestMutex.RUnlock()
+	return len(fake.hNSListEndpointRequestArgsForCall)
+}
+
+func (fake *HCSClient) HNSListEndpointRequestCalls(stub func() ([]hcsshim.HNSEndpoint, error)) {
+	fake.hNSListEndpointRequestMutex.Lock()
+	defer fake.hNSListEndpointRequestMutex.Unlock()
+	fake.HNSListEndpointRequestStub = stub
+}
+
+func (fake *HCSClient) HNSListEndpointRequestReturns(result1 []hcsshim.HNSEndpoint, result2 error) {
+	fake.hNSListEndpointRequestMutex.Lock()
+	defer fake.hNSListEndpointRequestMutex.Unlock()
+	fake.HNSListEndpointRequestStub = nil
+	fake.hNSListEndpointRequestReturns = struct {
+		result1 []hcsshim.HNSEndpoint
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *HCSClient) HNSListEndpointRequestReturnsOnCall(i int, result1 []hcsshim.HNSEndpoint, result2 error) {
+	fake.hNSListEndpointRequestMutex.Lock()
+	defer fake.hNSListEndpointRequestMutex.Unlock()
+	fake.HNSListEndpointRequestStub = nil
+	if fake.hNSListEndpointRequestReturnsOnCall == nil {
+		fake.hNSListEndpointRequestReturnsOnCall = make(map[int]struct {
+			result1 []hcsshim.HNSEndpoint
+			result2 error
+		})
+	}
+	fake.hNSListEndpointRequestReturnsOnCall[i] = struct {
+		result1 []hcsshim.HNSEndpoint
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *HCSClient) HotAttachEndpoint(containerID string, endpointID string, endpointReady func() (bool, error)) error {
+func (fake *HCSClient) HotAttachEndpoint(arg1 string, arg2 string, arg3 func() (bool, error)) error {
 	fake.hotAttachEndpointMutex.Lock()
 	ret, specificReturn := fake.hotAttachEndpointReturnsOnCall[len(fake.hotAttachEndpointArgsForCall)]
 	fake.hotAttachEndpointArgsForCall = append(fake.hotAttachEndpointArgsForCall, struct {
-		containerID   string
-		endpointID    string
-		endpointReady func() (bool, error)
-	}{containerID, endpointID, endpointReady})
-	fake.recordInvocation("HotAttachEndpoint", []interface{}{containerID, endpointID, endpointReady})
+		arg1 string
+		arg2 string
+		arg3 func() (bool, error)
+	}{arg1, arg2, arg3})
+	stub := fake.HotAttachEndpointStub
+	fakeReturns := fake.hotAttachEndpointReturns
+	fake.recordInvocation("HotAttachEndpoint", []interface{}{arg1, arg2, arg3})
 	fake.hotAttachEndpointMutex.Unlock()
-	if fake.HotAttachEndpointStub != nil {
-		return fake.HotAttachEndpointStub(containerID, endpointID, endpointReady)
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	return fake.hotAttachEndpointReturns.result1
+	return fakeReturns.result1
 }
 
 func (fake *HCSClient) HotAttachEndpointCallCount() int {
@@ -447,13 +531,22 @@ func (fake *HCSClient) HotAttachEndpointCallCount() int {
 	return len(fake.hotAttachEndpointArgsForCall)
 }
 
+func (fake *HCSClient) HotAttachEndpointCalls(stub func(string, string, func() (bool, error)) error) {
+	fake.hotAttachEndpointMutex.Lock()
+	defer fake.hotAttachEndpointMutex.Unlock()
+	fake.HotAttachEndpointStub = stub
+}
+
 func (fake *HCSClient) HotAttachEndpointArgsForCall(i int) (string, string, func() (bool, error)) {
 	fake.hotAttachEndpointMutex.RLock()
 	defer fake.hotAttachEndpointMutex.RUnlock()
-	return fake.hotAttachEndpointArgsForCall[i].containerID, fake.hotAttachEndpointArgsForCall[i].endpointID, fake.hotAttachEndpointArgsForCall[i].endpointReady
+	argsForCall := fake.hotAttachEndpointArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *HCSClient) HotAttachEndpointReturns(result1 error) {
+	fake.hotAttachEndpointMutex.Lock()
+	defer fake.hotAttachEndpointMutex.Unlock()
 	fake.HotAttachEndpointStub = nil
 	fake.hotAttachEndpointReturns = struct {
 		result1 error
@@ -461,6 +554,8 @@ func (fake *HCSClient) HotAttachEndpointReturns(result1 error) {
 }
 
 func (fake *HCSClient) HotAttachEndpointReturnsOnCall(i int, result1 error) {
+	fake.hotAttachEndpointMutex.Lock()
+	defer fake.hotAttachEndpointMutex.Unlock()
 	fake.HotAttachEndpointStub = nil
 	if fake.hotAttachEndpointReturnsOnCall == nil {
 		fake.hotAttachEndpointReturnsOnCall = make(map[int]struct {
@@ -472,22 +567,24 @@ func (fake *HCSClient) HotAttachEndpointReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *HCSClient) HotDetachEndpoint(containerID string, endpointID string) error {
+func (fake *HCSClient) HotDetachEndpoint(arg1 string, arg2 string) error {
 	fake.hotDetachEndpointMutex.Lock()
 	ret, specificReturn := fake.hotDetachEndpointReturnsOnCall[len(fake.hotDetachEndpointArgsForCall)]
 	fake.hotDetachEndpointArgsForCall = append(fake.hotDetachEndpointArgsForCall, struct {
-		containerID string
-		endpointID  string
-	}{containerID, endpointID})
-	fake.recordInvocation("HotDetachEndpoint", []interface{}{containerID, endpointID})
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.HotDetachEndpointStub
+	fakeReturns := fake.hotDetachEndpointReturns
+	fake.recordInvocation("HotDetachEndpoint", []interface{}{arg1, arg2})
 	fake.hotDetachEndpointMutex.Unlock()
-	if fake.HotDetachEndpointStub != nil {
-		return fake.HotDetachEndpointStub(containerID, endpointID)
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	return fake.hotDetachEndpointReturns.result1
+	return fakeReturns.result1
 }
 
 func (fake *HCSClient) HotDetachEndpointCallCount() int {
@@ -496,13 +593,22 @@ func (fake *HCSClient) HotDetachEndpointCallCount() int {
 	return len(fake.hotDetachEndpointArgsForCall)
 }
 
+func (fake *HCSClient) HotDetachEndpointCalls(stub func(string, string) error) {
+	fake.hotDetachEndpointMutex.Lock()
+	defer fake.hotDetachEndpointMutex.Unlock()
+	fake.HotDetachEndpointStub = stub
+}
+
 func (fake *HCSClient) HotDetachEndpointArgsForCall(i int) (string, string) {
 	fake.hotDetachEndpointMutex.RLock()
 	defer fake.hotDetachEndpointMutex.RUnlock()
-	return fake.hotDetachEndpointArgsForCall[i].containerID, fake.hotDetachEndpointArgsForCall[i].endpointID
+	argsForCall := fake.hotDetachEndpointArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *HCSClient) HotDetachEndpointReturns(result1 error) {
+	fake.hotDetachEndpointMutex.Lock()
+	defer fake.hotDetachEndpointMutex.Unlock()
 	fake.HotDetachEndpointStub = nil
 	fake.hotDetachEndpointReturns = struct {
 		result1 error
@@ -510,6 +616,8 @@ func (fake *HCSClient) HotDetachEndpointReturns(result1 error) {
 }
 
 func (fake *HCSClient) HotDetachEndpointReturnsOnCall(i int, result1 error) {
+	fake.hotDetachEndpointMutex.Lock()
+	defer fake.hotDetachEndpointMutex.Unlock()
 	fake.HotDetachEndpointStub = nil
 	if fake.hotDetachEndpointReturnsOnCall == nil {
 		fake.hotDetachEndpointReturnsOnCall = make(map[int]struct {
@@ -521,26 +629,96 @@ func (fake *HCSClient) HotDetachEndpointReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *HCSClient) UpdateEndpoint(arg1 *hcsshim.HNSEndpoint) (*hcsshim.HNSEndpoint, error) {
+	fake.updateEndpointMutex.Lock()
+	ret, specificReturn := fake.updateEndpointReturnsOnCall[len(fake.updateEndpointArgsForCall)]
+	fake.updateEndpointArgsForCall = append(fake.updateEndpointArgsForCall, struct {
+		arg1 *hcsshim.HNSEndpoint
+	}{arg1})
+	stub := fake.UpdateEndpointStub
+	fakeReturns := fake.updateEndpointReturns
+	fake.recordInvocation("UpdateEndpoint", []interface{}{arg1})
+	fake.updateEndpointMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *HCSClient) UpdateEndpointCallCount() int {
+	fake.updateEndpointMutex.RLock()
+	defer fake.updateEndpointMutex.RUnlock()
+	return len(fake.updateEndpointArgsForCall)
+}
+
+func (fake *HCSClient) UpdateEndpointCalls(stub func(*hcsshim.HNSEndpoint) (*hcsshim.HNSEndpoint, error)) {
+	fake.updateEndpointMutex.Lock()
+	defer fake.updateEndpointMutex.Unlock()
+	fake.UpdateEndpointStub = stub
+}
+
+func (fake *HCSClient) UpdateEndpointArgsForCall(i int) *hcsshim.HNSEndpoint {
+	fake.updateEndpointMutex.RLock()
+	defer fake.updateEndpointMutex.RUnlock()
+	argsForCall := fake.updateEndpointArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *HCSClient) UpdateEndpointReturns(result1 *hcsshim.HNSEndpoint, result2 error) {
+	fake.updateEndpointMutex.Lock()
+	defer fake.updateEndpointMutex.Unlock()
+	fake.UpdateEndpointStub = nil
+	fake.updateEndpointReturns = struct {
+		result1 *hcsshim.HNSEndpoint
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *HCSClient) UpdateEndpointReturnsOnCall(i int, result1 *hcsshim.HNSEndpoint, result2 error) {
+	fake.updateEndpointMutex.Lock()
+	defer fake.updateEndpointMutex.Unlock()
+	fake.UpdateEndpointStub = nil
+	if fake.updateEndpointReturnsOnCall == nil {
+		fake.updateEndpointReturnsOnCall = make(map[int]struct {
+			result1 *hcsshim.HNSEndpoint
+			result2 error
+		})
+	}
+	fake.updateEndpointReturnsOnCall[i] = struct {
+		result1 *hcsshim.HNSEndpoint
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *HCSClient) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
-	fake.getHNSNetworkByNameMutex.RLock()
-	defer fake.getHNSNetworkByNameMutex.RUnlock()
 	fake.createEndpointMutex.RLock()
 	defer fake.createEndpointMutex.RUnlock()
-	fake.updateEndpointMutex.RLock()
-	defer fake.updateEndpointMutex.RUnlock()
+	fake.deleteEndpointMutex.RLock()
+	defer fake.deleteEndpointMutex.RUnlock()
 	fake.getHNSEndpointByIDMutex.RLock()
 	defer fake.getHNSEndpointByIDMutex.RUnlock()
 	fake.getHNSEndpointByNameMutex.RLock()
 	defer fake.getHNSEndpointByNameMutex.RUnlock()
-	fake.deleteEndpointMutex.RLock()
-	defer fake.deleteEndpointMutex.RUnlock()
+	fake.getHNSNetworkByNameMutex.RLock()
+	defer fake.getHNSNetworkByNameMutex.RUnlock()
+	fake.hNSListEndpointRequestMutex.RLock()
+	defer fake.hNSListEndpointRequestMutex.RUnlock()
 	fake.hotAttachEndpointMutex.RLock()
 	defer fake.hotAttachEndpointMutex.RUnlock()
 	fake.hotDetachEndpointMutex.RLock()
 	defer fake.hotDetachEndpointMutex.RUnlock()
-	return fake.invocations
+	fake.updateEndpointMutex.RLock()
+	defer fake.updateEndpointMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
 }
 
 func (fake *HCSClient) recordInvocation(key string, args []interface{}) {
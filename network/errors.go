@@ -22,3 +22,70 @@ type SameNATNetworkNameError struct {
 func (e *SameNATNetworkNameError) Error() string {
 	return fmt.Sprintf("nat network %s exists with subnets %+v", e.Name, e.Subnets)
 }
+
+type SameNetworkNameError struct {
+	Name    string
+	Type    string
+	Subnets []hcsshim.Subnet
+}
+
+func (e *SameNetworkNameError) Error() string {
+	return fmt.Sprintf("%s network %s exists with subnets %+v", e.Type, e.Name, e.Subnets)
+}
+
+type NetworkConfigError struct {
+	Field   string
+	Message string
+}
+
+func (e *NetworkConfigError) Error() string {
+	return fmt.Sprintf("invalid network config: %s: %s", e.Field, e.Message)
+}
+
+type UnknownNetworkError struct {
+	Name string
+}
+
+func (e *UnknownNetworkError) Error() string {
+	return fmt.Sprintf("network %s is not configured", e.Name)
+}
+
+type RequestedIPError struct {
+	IP     string
+	Reason string
+}
+
+func (e *RequestedIPError) Error() string {
+	return fmt.Sprintf("invalid requested ip %s: %s", e.IP, e.Reason)
+}
+
+type BandwidthLimitError struct {
+	Rate    uint64
+	Maximum uint64
+}
+
+func (e *BandwidthLimitError) Error() string {
+	return fmt.Sprintf("bandwidth rate %d exceeds the maximum of %d bytes per second", e.Rate, e.Maximum)
+}
+
+type RuleValidationError struct {
+	Rule    string
+	Message string
+}
+
+func (e RuleValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Rule, e.Message)
+}
+
+type UpInputsValidationError struct {
+	Errors []RuleValidationError
+}
+
+func (e *UpInputsValidationError) Error() string {
+	errorStr := "network rules are invalid:"
+	for _, err := range e.Errors {
+		errorStr += "\n\t" + err.Error()
+	}
+
+	return errorStr
+}
@@ -0,0 +1,114 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	"sync"
+
+	"code.cloudfoundry.org/winc/network"
+	"code.cloudfoundry.org/winc/network/netrules"
+)
+
+type Auditor struct {
+	NetOutStub        func(string, netrules.NetOut) error
+	netOutMutex       sync.RWMutex
+	netOutArgsForCall []struct {
+		arg1 string
+		arg2 netrules.NetOut
+	}
+	netOutReturns struct {
+		result1 error
+	}
+	netOutReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *Auditor) NetOut(arg1 string, arg2 netrules.NetOut) error {
+	fake.netOutMutex.Lock()
+	ret, specificReturn := fake.netOutReturnsOnCall[len(fake.netOutArgsForCall)]
+	fake.netOutArgsForCall = append(fake.netOutArgsForCall, struct {
+		arg1 string
+		arg2 netrules.NetOut
+	}{arg1, arg2})
+	stub := fake.NetOutStub
+	fakeReturns := fake.netOutReturns
+	fake.recordInvocation("NetOut", []interface{}{arg1, arg2})
+	fake.netOutMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *Auditor) NetOutCallCount() int {
+	fake.netOutMutex.RLock()
+	defer fake.netOutMutex.RUnlock()
+	return len(fake.netOutArgsForCall)
+}
+
+func (fake *Auditor) NetOutCalls(stub func(string, netrules.NetOut) error) {
+	fake.netOutMutex.Lock()
+	defer fake.netOutMutex.Unlock()
+	fake.NetOutStub = stub
+}
+
+func (fake *Auditor) NetOutArgsForCall(i int) (string, netrules.NetOut) {
+	fake.netOutMutex.RLock()
+	defer fake.netOutMutex.RUnlock()
+	argsForCall := fake.netOutArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *Auditor) NetOutReturns(result1 error) {
+	fake.netOutMutex.Lock()
+	defer fake.netOutMutex.Unlock()
+	fake.NetOutStub = nil
+	fake.netOutReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Auditor) NetOutReturnsOnCall(i int, result1 error) {
+	fake.netOutMutex.Lock()
+	defer fake.netOutMutex.Unlock()
+	fake.NetOutStub = nil
+	if fake.netOutReturnsOnCall == nil {
+		fake.netOutReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.netOutReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Auditor) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.netOutMutex.RLock()
+	defer fake.netOutMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *Auditor) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ network.Auditor = new(Auditor)
@@ -2,6 +2,7 @@
 package fakes
 
 import (
+	"net"
 	"sync"
 
 	"code.cloudfoundry.org/winc/network"
@@ -9,10 +10,28 @@ import (
 )
 
 type EndpointManager struct {
-	CreateStub        func() (hcsshim.HNSEndpoint, error)
+	ApplyPoliciesStub        func(hcsshim.HNSEndpoint, []*hcsshim.NatPolicy, []*hcsshim.ACLPolicy) (hcsshim.HNSEndpoint, error)
+	applyPoliciesMutex       sync.RWMutex
+	applyPoliciesArgsForCall []struct {
+		arg1 hcsshim.HNSEndpoint
+		arg2 []*hcsshim.NatPolicy
+		arg3 []*hcsshim.ACLPolicy
+	}
+	applyPoliciesReturns struct {
+		result1 hcsshim.HNSEndpoint
+		result2 error
+	}
+	applyPoliciesReturnsOnCall map[int]struct {
+		result1 hcsshim.HNSEndpoint
+		result2 error
+	}
+	CreateStub        func(net.IP, network.DNSSettings) (hcsshim.HNSEndpoint, error)
 	createMutex       sync.RWMutex
-	createArgsForCall []struct{}
-	createReturns     struct {
+	createArgsForCall []struct {
+		arg1 net.IP
+		arg2 network.DNSSettings
+	}
+	createReturns struct {
 		result1 hcsshim.HNSEndpoint
 		result2 error
 	}
@@ -22,25 +41,37 @@ type EndpointManager struct {
 	}
 	DeleteStub        func() error
 	deleteMutex       sync.RWMutex
-	deleteArgsForCall []struct{}
-	deleteReturns     struct {
+	deleteArgsForCall []struct {
+	}
+	deleteReturns struct {
 		result1 error
 	}
 	deleteReturnsOnCall map[int]struct {
 		result1 error
 	}
-	ApplyPoliciesStub        func(hcsshim.HNSEndpoint, []*hcsshim.NatPolicy, []*hcsshim.ACLPolicy) (hcsshim.HNSEndpoint, error)
-	applyPoliciesMutex       sync.RWMutex
-	applyPoliciesArgsForCall []struct {
+	GetStub        func() (hcsshim.HNSEndpoint, error)
+	getMutex       sync.RWMutex
+	getArgsForCall []struct {
+	}
+	getReturns struct {
+		result1 hcsshim.HNSEndpoint
+		result2 error
+	}
+	getReturnsOnCall map[int]struct {
+		result1 hcsshim.HNSEndpoint
+		result2 error
+	}
+	SetMaximumOutgoingBandwidthStub        func(hcsshim.HNSEndpoint, uint64) (hcsshim.HNSEndpoint, error)
+	setMaximumOutgoingBandwidthMutex       sync.RWMutex
+	setMaximumOutgoingBandwidthArgsForCall []struct {
 		arg1 hcsshim.HNSEndpoint
-		arg2 []*hcsshim.NatPolicy
-		arg3 []*hcsshim.ACLPolicy
+		arg2 uint64
 	}
-	applyPoliciesReturns struct {
+	setMaximumOutgoingBandwidthReturns struct {
 		result1 hcsshim.HNSEndpoint
 		result2 error
 	}
-	applyPoliciesReturnsOnCall map[int]struct {
+	setMaximumOutgoingBandwidthReturnsOnCall map[int]struct {
 		result1 hcsshim.HNSEndpoint
 		result2 error
 	}
@@ -48,19 +79,100 @@ type EndpointManager struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *EndpointManager) Create() (hcsshim.HNSEndpoint, error) {
+func (fake *EndpointManager) ApplyPolicies(arg1 hcsshim.HNSEndpoint, arg2 []*hcsshim.NatPolicy, arg3 []*hcsshim.ACLPolicy) (hcsshim.HNSEndpoint, error) {
+	var arg2Copy []*hcsshim.NatPolicy
+	if arg2 != nil {
+		arg2Copy = make([]*hcsshim.NatPolicy, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	var arg3Copy []*hcsshim.ACLPolicy
+	if arg3 != nil {
+		arg3Copy = make([]*hcsshim.ACLPolicy, len(arg3))
+		copy(arg3Copy, arg3)
+	}
+	fake.applyPoliciesMutex.Lock()
+	ret, specificReturn := fake.applyPoliciesReturnsOnCall[len(fake.applyPoliciesArgsForCall)]
+	fake.applyPoliciesArgsForCall = append(fake.applyPoliciesArgsForCall, struct {
+		arg1 hcsshim.HNSEndpoint
+		arg2 []*hcsshim.NatPolicy
+		arg3 []*hcsshim.ACLPolicy
+	}{arg1, arg2Copy, arg3Copy})
+	stub := fake.ApplyPoliciesStub
+	fakeReturns := fake.applyPoliciesReturns
+	fake.recordInvocation("ApplyPolicies", []interface{}{arg1, arg2Copy, arg3Copy})
+	fake.applyPoliciesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *EndpointManager) ApplyPoliciesCallCount() int {
+	fake.applyPoliciesMutex.RLock()
+	defer fake.applyPoliciesMutex.RUnlock()
+	return len(fake.applyPoliciesArgsForCall)
+}
+
+func (fake *EndpointManager) ApplyPoliciesCalls(stub func(hcsshim.HNSEndpoint, []*hcsshim.NatPolicy, []*hcsshim.ACLPolicy) (hcsshim.HNSEndpoint, error)) {
+	fake.applyPoliciesMutex.Lock()
+	defer fake.applyPoliciesMutex.Unlock()
+	fake.ApplyPoliciesStub = stub
+}
+
+func (fake *EndpointManager) ApplyPoliciesArgsForCall(i int) (hcsshim.HNSEndpoint, []*hcsshim.NatPolicy, []*hcsshim.ACLPolicy) {
+	fake.applyPoliciesMutex.RLock()
+	defer fake.applyPoliciesMutex.RUnlock()
+	argsForCall := fake.applyPoliciesArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *EndpointManager) ApplyPoliciesReturns(result1 hcsshim.HNSEndpoint, result2 error) {
+	fake.applyPoliciesMutex.Lock()
+	defer fake.applyPoliciesMutex.Unlock()
+	fake.ApplyPoliciesStub = nil
+	fake.applyPoliciesReturns = struct {
+		result1 hcsshim.HNSEndpoint
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *EndpointManager) ApplyPoliciesReturnsOnCall(i int, result1 hcsshim.HNSEndpoint, result2 error) {
+	fake.applyPoliciesMutex.Lock()
+	defer fake.applyPoliciesMutex.Unlock()
+	fake.ApplyPoliciesStub = nil
+	if fake.applyPoliciesReturnsOnCall == nil {
+		fake.applyPoliciesReturnsOnCall = make(map[int]struct {
+			result1 hcsshim.HNSEndpoint
+			result2 error
+		})
+	}
+	fake.applyPoliciesReturnsOnCall[i] = struct {
+		result1 hcsshim.HNSEndpoint
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *EndpointManager) Create(arg1 net.IP, arg2 network.DNSSettings) (hcsshim.HNSEndpoint, error) {
 	fake.createMutex.Lock()
 	ret, specificReturn := fake.createReturnsOnCall[len(fake.createArgsForCall)]
-	fake.createArgsForCall = append(fake.createArgsForCall, struct{}{})
-	fake.recordInvocation("Create", []interface{}{})
+	fake.createArgsForCall = append(fake.createArgsForCall, struct {
+		arg1 net.IP
+		arg2 network.DNSSettings
+	}{arg1, arg2})
+	stub := fake.CreateStub
+	fakeReturns := fake.createReturns
+	fake.recordInvocation("Create", []interface{}{arg1, arg2})
 	fake.createMutex.Unlock()
-	if fake.CreateStub != nil {
-		return fake.CreateStub()
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	return fake.createReturns.result1, fake.createReturns.result2
+	return fakeReturns.result1, fakeReturns.result2
 }
 
 func (fake *EndpointManager) CreateCallCount() int {
@@ -69,7 +181,22 @@ func (fake *EndpointManager) CreateCallCount() int {
 	return len(fake.createArgsForCall)
 }
 
+func (fake *EndpointManager) CreateCalls(stub func(net.IP, network.DNSSettings) (hcsshim.HNSEndpoint, error)) {
+	fake.createMutex.Lock()
+	defer fake.createMutex.Unlock()
+	fake.CreateStub = stub
+}
+
+func (fake *EndpointManager) CreateArgsForCall(i int) (net.IP, network.DNSSettings) {
+	fake.createMutex.RLock()
+	defer fake.createMutex.RUnlock()
+	argsForCall := fake.createArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
 func (fake *EndpointManager) CreateReturns(result1 hcsshim.HNSEndpoint, result2 error) {
+	fake.createMutex.Lock()
+	defer fake.createMutex.Unlock()
 	fake.CreateStub = nil
 	fake.createReturns = struct {
 		result1 hcsshim.HNSEndpoint
@@ -78,6 +205,8 @@ func (fake *EndpointManager) CreateReturns(result1 hcsshim.HNSEndpoint, result2
 }
 
 func (fake *EndpointManager) CreateReturnsOnCall(i int, result1 hcsshim.HNSEndpoint, result2 error) {
+	fake.createMutex.Lock()
+	defer fake.createMutex.Unlock()
 	fake.CreateStub = nil
 	if fake.createReturnsOnCall == nil {
 		fake.createReturnsOnCall = make(map[int]struct {
@@ -94,16 +223,19 @@ func (fake *EndpointManager) CreateReturnsOnCall(i int, result1 hcsshim.HNSEndpo
 func (fake *EndpointManager) Delete() error {
 	fake.deleteMutex.Lock()
 	ret, specificReturn := fake.deleteReturnsOnCall[len(fake.deleteArgsForCall)]
-	fake.deleteArgsForCall = append(fake.deleteArgsForCall, struct{}{})
+	fake.deleteArgsForCall = append(fake.deleteArgsForCall, struct {
+	}{})
+	stub := fake.DeleteStub
+	fakeReturns := fake.deleteReturns
 	fake.recordInvocation("Delete", []interface{}{})
 	fake.deleteMutex.Unlock()
-	if fake.DeleteStub != nil {
-		return fake.DeleteStub()
+	if stub != nil {
+		return stub()
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	return fake.deleteReturns.result1
+	return fakeReturns.result1
 }
 
 func (fake *EndpointManager) DeleteCallCount() int {
@@ -112,7 +244,15 @@ func (fake *EndpointManager) DeleteCallCount() int {
 	return len(fake.deleteArgsForCall)
 }
 
+func (fake *EndpointManager) DeleteCalls(stub func() error) {
+	fake.deleteMutex.Lock()
+	defer fake.deleteMutex.Unlock()
+	fake.DeleteStub = stub
+}
+
 func (fake *EndpointManager) DeleteReturns(result1 error) {
+	fake.deleteMutex.Lock()
+	defer fake.deleteMutex.Unlock()
 	fake.DeleteStub = nil
 	fake.deleteReturns = struct {
 		result1 error
@@ -120,6 +260,8 @@ func (fake *EndpointManager) DeleteReturns(result1 error) {
 }
 
 func (fake *EndpointManager) DeleteReturnsOnCall(i int, result1 error) {
+	fake.deleteMutex.Lock()
+	defer fake.deleteMutex.Unlock()
 	fake.DeleteStub = nil
 	if fake.deleteReturnsOnCall == nil {
 		fake.deleteReturnsOnCall = make(map[int]struct {
@@ -131,64 +273,122 @@ func (fake *EndpointManager) DeleteReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *EndpointManager) ApplyPolicies(arg1 hcsshim.HNSEndpoint, arg2 []*hcsshim.NatPolicy, arg3 []*hcsshim.ACLPolicy) (hcsshim.HNSEndpoint, error) {
-	var arg2Copy []*hcsshim.NatPolicy
-	if arg2 != nil {
-		arg2Copy = make([]*hcsshim.NatPolicy, len(arg2))
-		copy(arg2Copy, arg2)
+func (fake *EndpointManager) Get() (hcsshim.HNSEndpoint, error) {
+	fake.getMutex.Lock()
+	ret, specificReturn := fake.getReturnsOnCall[len(fake.getArgsForCall)]
+	fake.getArgsForCall = append(fake.getArgsForCall, struct {
+	}{})
+	stub := fake.GetStub
+	fakeReturns := fake.getReturns
+	fake.recordInvocation("Get", []interface{}{})
+	fake.getMutex.Unlock()
+	if stub != nil {
+		return stub()
 	}
-	var arg3Copy []*hcsshim.ACLPolicy
-	if arg3 != nil {
-		arg3Copy = make([]*hcsshim.ACLPolicy, len(arg3))
-		copy(arg3Copy, arg3)
+	if specificReturn {
+		return ret.result1, ret.result2
 	}
-	fake.applyPoliciesMutex.Lock()
-	ret, specificReturn := fake.applyPoliciesReturnsOnCall[len(fake.applyPoliciesArgsForCall)]
-	fake.applyPoliciesArgsForCall = append(fake.applyPoliciesArgsForCall, struct {
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *EndpointManager) GetCallCount() int {
+	fake.getMutex.RLock()
+	defer fake.getMutex.RUnlock()
+	return len(fake.getArgsForCall)
+}
+
+func (fake *EndpointManager) GetCalls(stub func() (hcsshim.HNSEndpoint, error)) {
+	fake.getMutex.Lock()
+	defer fake.getMutex.Unlock()
+	fake.GetStub = stub
+}
+
+func (fake *EndpointManager) GetReturns(result1 hcsshim.HNSEndpoint, result2 error) {
+	fake.getMutex.Lock()
+	defer fake.getMutex.Unlock()
+	fake.GetStub = nil
+	fake.getReturns = struct {
+		result1 hcsshim.HNSEndpoint
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *EndpointManager) GetReturnsOnCall(i int, result1 hcsshim.HNSEndpoint, result2 error) {
+	fake.getMutex.Lock()
+	defer fake.getMutex.Unlock()
+	fake.GetStub = nil
+	if fake.getReturnsOnCall == nil {
+		fake.getReturnsOnCall = make(map[int]struct {
+			result1 hcsshim.HNSEndpoint
+			result2 error
+		})
+	}
+	fake.getReturnsOnCall[i] = struct {
+		result1 hcsshim.HNSEndpoint
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *EndpointManager) SetMaximumOutgoingBandwidth(arg1 hcsshim.HNSEndpoint, arg2 uint64) (hcsshim.HNSEndpoint, error) {
+	fake.setMaximumOutgoingBandwidthMutex.Lock()
+	ret, specificReturn := fake.setMaximumOutgoingBandwidthReturnsOnCall[len(fake.setMaximumOutgoingBandwidthArgsForCall)]
+	fake.setMaximumOutgoingBandwidthArgsForCall = append(fake.setMaximumOutgoingBandwidthArgsForCall, struct {
 		arg1 hcsshim.HNSEndpoint
-		arg2 []*hcsshim.NatPolicy
-		arg3 []*hcsshim.ACLPolicy
-	}{arg1, arg2Copy, arg3Copy})
-	fake.recordInvocation("ApplyPolicies", []interface{}{arg1, arg2Copy, arg3Copy})
-	fake.applyPoliciesMutex.Unlock()
-	if fake.ApplyPoliciesStub != nil {
-		return fake.ApplyPoliciesStub(arg1, arg2, arg3)
+		arg2 uint64
+	}{arg1, arg2})
+	stub := fake.SetMaximumOutgoingBandwidthStub
+	fakeReturns := fake.setMaximumOutgoingBandwidthReturns
+	fake.recordInvocation("SetMaximumOutgoingBandwidth", []interface{}{arg1, arg2})
+	fake.setMaximumOutgoingBandwidthMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	return fake.applyPoliciesReturns.result1, fake.applyPoliciesReturns.result2
+	return fakeReturns.result1, fakeReturns.result2
 }
 
-func (fake *EndpointManager) ApplyPoliciesCallCount() int {
-	fake.applyPoliciesMutex.RLock()
-	defer fake.applyPoliciesMutex.RUnlock()
-	return len(fake.applyPoliciesArgsForCall)
+func (fake *EndpointManager) SetMaximumOutgoingBandwidthCallCount() int {
+	fake.setMaximumOutgoingBandwidthMutex.RLock()
+	defer fake.setMaximumOutgoingBandwidthMutex.RUnlock()
+	return len(fake.setMaximumOutgoingBandwidthArgsForCall)
 }
 
-func (fake *EndpointManager) ApplyPoliciesArgsForCall(i int) (hcsshim.HNSEndpoint, []*hcsshim.NatPolicy, []*hcsshim.ACLPolicy) {
-	fake.applyPoliciesMutex.RLock()
-	defer fake.applyPoliciesMutex.RUnlock()
-	return fake.applyPoliciesArgsForCall[i].arg1, fake.applyPoliciesArgsForCall[i].arg2, fake.applyPoliciesArgsForCall[i].arg3
+func (fake *EndpointManager) SetMaximumOutgoingBandwidthCalls(stub func(hcsshim.HNSEndpoint, uint64) (hcsshim.HNSEndpoint, error)) {
+	fake.setMaximumOutgoingBandwidthMutex.Lock()
+	defer fake.setMaximumOutgoingBandwidthMutex.Unlock()
+	fake.SetMaximumOutgoingBandwidthStub = stub
 }
 
-func (fake *EndpointManager) ApplyPoliciesReturns(result1 hcsshim.HNSEndpoint, result2 error) {
-	fake.ApplyPoliciesStub = nil
-	fake.applyPoliciesReturns = struct {
+func (fake *EndpointManager) SetMaximumOutgoingBandwidthArgsForCall(i int) (hcsshim.HNSEndpoint, uint64) {
+	fake.setMaximumOutgoingBandwidthMutex.RLock()
+	defer fake.setMaximumOutgoingBandwidthMutex.RUnlock()
+	argsForCall := fake.setMaximumOutgoingBandwidthArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *EndpointManager) SetMaximumOutgoingBandwidthReturns(result1 hcsshim.HNSEndpoint, result2 error) {
+	fake.setMaximumOutgoingBandwidthMutex.Lock()
+	defer fake.setMaximumOutgoingBandwidthMutex.Unlock()
+	fake.SetMaximumOutgoingBandwidthStub = nil
+	fake.setMaximumOutgoingBandwidthReturns = struct {
 		result1 hcsshim.HNSEndpoint
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *EndpointManager) ApplyPoliciesReturnsOnCall(i int, result1 hcsshim.HNSEndpoint, result2 error) {
-	fake.ApplyPoliciesStub = nil
-	if fake.applyPoliciesReturnsOnCall == nil {
-		fake.applyPoliciesReturnsOnCall = make(map[int]struct {
+func (fake *EndpointManager) SetMaximumOutgoingBandwidthReturnsOnCall(i int, result1 hcsshim.HNSEndpoint, result2 error) {
+	fake.setMaximumOutgoingBandwidthMutex.Lock()
+	defer fake.setMaximumOutgoingBandwidthMutex.Unlock()
+	fake.SetMaximumOutgoingBandwidthStub = nil
+	if fake.setMaximumOutgoingBandwidthReturnsOnCall == nil {
+		fake.setMaximumOutgoingBandwidthReturnsOnCall = make(map[int]struct {
 			result1 hcsshim.HNSEndpoint
 			result2 error
 		})
 	}
-	fake.applyPoliciesReturnsOnCall[i] = struct {
+	fake.setMaximumOutgoingBandwidthReturnsOnCall[i] = struct {
 		result1 hcsshim.HNSEndpoint
 		result2 error
 	}{result1, result2}
@@ -197,13 +397,21 @@ func (fake *EndpointManager) ApplyPoliciesReturnsOnCall(i int, result1 hcsshim.H
 func (fake *EndpointManager) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.applyPoliciesMutex.RLock()
+	defer fake.applyPoliciesMutex.RUnlock()
 	fake.createMutex.RLock()
 	defer fake.createMutex.RUnlock()
 	fake.deleteMutex.RLock()
 	defer fake.deleteMutex.RUnlock()
-	fake.applyPoliciesMutex.RLock()
-	defer fake.applyPoliciesMutex.RUnlock()
-	return fake.invocations
+	fake.getMutex.RLock()
+	defer fake.getMutex.RUnlock()
+	fake.setMaximumOutgoingBandwidthMutex.RLock()
+	defer fake.setMaximumOutgoingBandwidthMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
 }
 
 func (fake *EndpointManager) recordInvocation(key string, args []interface{}) {
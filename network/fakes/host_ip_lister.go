@@ -0,0 +1,121 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	"sync"
+
+	"code.cloudfoundry.org/winc/network"
+)
+
+type HostIPLister struct {
+	HostIPsStub        func([]string) ([]string, error)
+	hostIPsMutex       sync.RWMutex
+	hostIPsArgsForCall []struct {
+		arg1 []string
+	}
+	hostIPsReturns struct {
+		result1 []string
+		result2 error
+	}
+	hostIPsReturnsOnCall map[int]struct {
+		result1 []string
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *HostIPLister) HostIPs(arg1 []string) ([]string, error) {
+	var arg1Copy []string
+	if arg1 != nil {
+		arg1Copy = make([]string, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.hostIPsMutex.Lock()
+	ret, specificReturn := fake.hostIPsReturnsOnCall[len(fake.hostIPsArgsForCall)]
+	fake.hostIPsArgsForCall = append(fake.hostIPsArgsForCall, struct {
+		arg1 []string
+	}{arg1Copy})
+	stub := fake.HostIPsStub
+	fakeReturns := fake.hostIPsReturns
+	fake.recordInvocation("HostIPs", []interface{}{arg1Copy})
+	fake.hostIPsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *HostIPLister) HostIPsCallCount() int {
+	fake.hostIPsMutex.RLock()
+	defer fake.hostIPsMutex.RUnlock()
+	return len(fake.hostIPsArgsForCall)
+}
+
+func (fake *HostIPLister) HostIPsCalls(stub func([]string) ([]string, error)) {
+	fake.hostIPsMutex.Lock()
+	defer fake.hostIPsMutex.Unlock()
+	fake.HostIPsStub = stub
+}
+
+func (fake *HostIPLister) HostIPsArgsForCall(i int) []string {
+	fake.hostIPsMutex.RLock()
+	defer fake.hostIPsMutex.RUnlock()
+	argsForCall := fake.hostIPsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *HostIPLister) HostIPsReturns(result1 []string, result2 error) {
+	fake.hostIPsMutex.Lock()
+	defer fake.hostIPsMutex.Unlock()
+	fake.HostIPsStub = nil
+	fake.hostIPsReturns = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *HostIPLister) HostIPsReturnsOnCall(i int, result1 []string, result2 error) {
+	fake.hostIPsMutex.Lock()
+	defer fake.hostIPsMutex.Unlock()
+	fake.HostIPsStub = nil
+	if fake.hostIPsReturnsOnCall == nil {
+		fake.hostIPsReturnsOnCall = make(map[int]struct {
+			result1 []string
+			result2 error
+		})
+	}
+	fake.hostIPsReturnsOnCall[i] = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *HostIPLister) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.hostIPsMutex.RLock()
+	defer fake.hostIPsMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *HostIPLister) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ network.HostIPLister = new(HostIPLister)
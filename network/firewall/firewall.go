@@ -35,10 +35,11 @@ const (
 type Protocol int
 
 const (
-	NET_FW_IP_PROTOCOL_ICMP Protocol = 1
-	NET_FW_IP_PROTOCOL_TCP  Protocol = 6
-	NET_FW_IP_PROTOCOL_UDP  Protocol = 17
-	NET_FW_IP_PROTOCOL_ANY  Protocol = 256
+	NET_FW_IP_PROTOCOL_ICMP   Protocol = 1
+	NET_FW_IP_PROTOCOL_TCP    Protocol = 6
+	NET_FW_IP_PROTOCOL_UDP    Protocol = 17
+	NET_FW_IP_PROTOCOL_ICMPV6 Protocol = 58
+	NET_FW_IP_PROTOCOL_ANY    Protocol = 256
 )
 
 type Rule struct {
@@ -163,6 +163,52 @@ func (n *NetInterface) ByIP(ipStr string) (AdapterInfo, error) {
 	return AdapterInfo{}, &InterfaceForIPNotFoundError{ip: ipStr}
 }
 
+// HostIPs returns the addresses of the host's interfaces that are up, IPv4
+// addresses first, leaving out loopback and link-local addresses and those of
+// the excluded interfaces
+func (n *NetInterface) HostIPs(exclude []string) ([]string, error) {
+	excluded := map[string]bool{}
+	for _, name := range exclude {
+		excluded[name] = true
+	}
+
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+
+	var v4, v6 []string
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 || excluded[iface.Name] {
+			continue
+		}
+
+		addrs, err := iface.Addrs()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, addr := range addrs {
+			ip, _, err := net.ParseCIDR(addr.String())
+			if err != nil {
+				return nil, err
+			}
+
+			if ip.IsLinkLocalUnicast() {
+				continue
+			}
+
+			if ip.To4() != nil {
+				v4 = append(v4, ip.String())
+			} else {
+				v6 = append(v6, ip.String())
+			}
+		}
+	}
+
+	return append(v4, v6...), nil
+}
+
 func (n *NetInterface) SetMTU(name string, mtu uint32, family uint32) error {
 	adapterInfo, err := getAdapterInfoByName(name, family)
 	if err != nil {
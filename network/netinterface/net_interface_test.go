@@ -90,6 +90,30 @@ var _ = Describe("NetInterface", func() {
 		})
 	})
 
+	Describe("HostIPs", func() {
+		It("includes the host IP", func() {
+			hostIPStr, err := localip.LocalIP()
+			Expect(err).To(Succeed())
+
+			ips, err := netIface.HostIPs(nil)
+			Expect(err).To(Succeed())
+			Expect(ips).To(ContainElement(hostIPStr))
+			Expect(ips).NotTo(ContainElement("127.0.0.1"))
+		})
+
+		It("leaves out the excluded interface", func() {
+			hostIPStr, err := localip.LocalIP()
+			Expect(err).To(Succeed())
+
+			iface, err := netIface.ByIP(hostIPStr)
+			Expect(err).To(Succeed())
+
+			ips, err := netIface.HostIPs([]string{"vEthernet (other)", iface.Name})
+			Expect(err).To(Succeed())
+			Expect(ips).NotTo(ContainElement(hostIPStr))
+		})
+	})
+
 	Describe("GetMTU", func() {
 		var (
 			interfaceMTU uint32
@@ -2,11 +2,11 @@ package netrules
 
 import (
 	"fmt"
-	"strconv"
 	"strings"
 
 	"code.cloudfoundry.org/winc/network/firewall"
 	"github.com/Microsoft/hcsshim"
+	"github.com/sirupsen/logrus"
 )
 
 //go:generate counterfeiter -o fakes/netsh_runner.go --fake-name NetShRunner . NetShRunner
@@ -17,7 +17,9 @@ type NetShRunner interface {
 //go:generate counterfeiter -o fakes/port_allocator.go --fake-name PortAllocator . PortAllocator
 type PortAllocator interface {
 	AllocatePort(handle string, port int) (int, error)
+	AllocatePortRange(handle string, port, count int) (int, error)
 	ReleaseAllPorts(handle string) error
+	ReleasePorts(handle string, ports []int) error
 }
 
 type Applier struct {
@@ -34,46 +36,105 @@ func NewApplier(netSh NetShRunner, containerId string, portAllocator PortAllocat
 	}
 }
 
-func (a *Applier) In(rule NetIn, containerIP string) (*hcsshim.NatPolicy, *hcsshim.ACLPolicy, error) {
-	externalPort := rule.HostPort
+func (a *Applier) In(rule NetIn, containerIP string) ([]*hcsshim.NatPolicy, []*hcsshim.ACLPolicy, error) {
+	protocols, err := rule.Protocol.Protocols()
+	if err != nil {
+		return nil, nil, err
+	}
 
-	if externalPort == 0 {
-		allocatedPort, err := a.portAllocator.AllocatePort(a.containerId, 0)
-		if err != nil {
-			return nil, nil, err
+	count, err := rule.PortCount()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	externalPort, err := a.externalPort(rule, count)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	nats := []*hcsshim.NatPolicy{}
+	for _, protocol := range protocols {
+		// HNS NAT policies map a single port each
+		for i := 0; i < count; i++ {
+			nats = append(nats, &hcsshim.NatPolicy{
+				Type:         hcsshim.Nat,
+				Protocol:     protocol.NatProtocol(),
+				ExternalPort: uint16(externalPort + uint32(i)),
+				InternalPort: uint16(rule.ContainerPort + uint32(i)),
+			})
 		}
-		externalPort = uint32(allocatedPort)
 	}
 
-	return &hcsshim.NatPolicy{
-			Type:         hcsshim.Nat,
-			Protocol:     "TCP",
-			ExternalPort: uint16(externalPort),
-			InternalPort: uint16(rule.ContainerPort),
-		}, &hcsshim.ACLPolicy{
+	return nats, inACLs(rule, containerIP, protocols, count), nil
+}
+
+// Expose allows inbound traffic to the container ports of rule without
+// mapping host ports, for containers with addresses routable from outside
+// the host
+func (a *Applier) Expose(rule NetIn, containerIP string) ([]*hcsshim.ACLPolicy, error) {
+	protocols, err := rule.Protocol.Protocols()
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := rule.PortCount()
+	if err != nil {
+		return nil, err
+	}
+
+	return inACLs(rule, containerIP, protocols, count), nil
+}
+
+func inACLs(rule NetIn, containerIP string, protocols []Protocol, count int) []*hcsshim.ACLPolicy {
+	acls := []*hcsshim.ACLPolicy{}
+	for _, protocol := range protocols {
+		acls = append(acls, &hcsshim.ACLPolicy{
 			Type:           hcsshim.ACL,
 			Action:         hcsshim.Allow,
 			Direction:      hcsshim.In,
-			Protocol:       uint16(firewall.NET_FW_IP_PROTOCOL_TCP),
+			Protocol:       uint16(protocol.FirewallProtocol()),
 			LocalAddresses: containerIP,
-			LocalPorts:     strconv.FormatUint(uint64(rule.ContainerPort), 10),
-		}, nil
+			LocalPorts:     rule.ContainerPorts(count),
+		})
+	}
+	return acls
+}
+
+func (a *Applier) externalPort(rule NetIn, count int) (uint32, error) {
+	// explicit host ports are reserved too, so that they are checked against
+	// the ports held by other containers
+	var allocatedPort int
+	var err error
+	if count == 1 {
+		allocatedPort, err = a.portAllocator.AllocatePort(a.containerId, int(rule.HostPort))
+	} else {
+		allocatedPort, err = a.portAllocator.AllocatePortRange(a.containerId, int(rule.HostPort), count)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return uint32(allocatedPort), nil
 }
 
 func (a *Applier) Out(rule NetOut, containerIP string) (*hcsshim.ACLPolicy, error) {
 	rAddrs := []string{}
 
 	for _, ipr := range rule.Networks {
-		rAddrs = append(rAddrs, IPRangeToCIDRs(ipr)...)
+		cidrs, err := IPRangeToCIDRs(ipr)
+		if err != nil {
+			return nil, err
+		}
+		rAddrs = append(rAddrs, cidrs...)
 	}
 
-	// if any IP CIDRS are 0.0.0.0/0, all remote destinations are allowed.
+	// if any IP CIDRS are 0.0.0.0/0 or ::/0, all remote destinations are allowed.
 	// However, passing 0.0.0.0/0 directly in our ACLPolicy doesn't actually
 	// have that effect.
 	// So just don't specfiy anything in our ACLPolicy -- this allows acces
 	// to all remote destinations
 	for _, addr := range rAddrs {
-		if addr == "0.0.0.0/0" {
+		if addr == "0.0.0.0/0" || addr == "::/0" {
 			rAddrs = []string{}
 			break
 		}
@@ -85,6 +146,11 @@ func (a *Applier) Out(rule NetOut, containerIP string) (*hcsshim.ACLPolicy, erro
 		Direction:       hcsshim.Out,
 		LocalAddresses:  containerIP,
 		RemoteAddresses: strings.Join(rAddrs, ","),
+		Priority:        rule.EffectivePriority(),
+	}
+
+	if rule.IsDeny() {
+		acl.Action = hcsshim.Block
 	}
 
 	switch rule.Protocol {
@@ -95,7 +161,12 @@ func (a *Applier) Out(rule NetOut, containerIP string) (*hcsshim.ACLPolicy, erro
 		acl.RemotePorts = FirewallRulePortRange(rule.Ports)
 		acl.Protocol = uint16(firewall.NET_FW_IP_PROTOCOL_UDP)
 	case ProtocolICMP:
-		acl.Protocol = uint16(firewall.NET_FW_IP_PROTOCOL_ICMP)
+		// HNS ACL policies have no way of matching ICMP types, so the rule
+		// allows every ICMP message
+		if rule.ICMPs != nil {
+			logrus.Warnf("ignoring icmps %s: HNS ACL policies cannot match icmp types", rule.ICMPs)
+		}
+		acl.Protocol = uint16(rule.ICMPProtocol())
 	case ProtocolAll:
 		acl.Protocol = uint16(firewall.NET_FW_IP_PROTOCOL_ANY)
 	default:
@@ -112,3 +183,8 @@ func (a *Applier) OpenPort(port uint32) error {
 func (a *Applier) Cleanup() error {
 	return a.portAllocator.ReleaseAllPorts(a.containerId)
 }
+
+// ReleasePorts returns host ports allocated for rules that could not be applied
+func (a *Applier) ReleasePorts(ports []int) error {
+	return a.portAllocator.ReleasePorts(a.containerId, ports)
+}
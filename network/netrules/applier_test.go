@@ -1,6 +1,7 @@
 package netrules_test
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"net"
@@ -11,6 +12,7 @@ import (
 	"github.com/Microsoft/hcsshim"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/sirupsen/logrus"
 )
 
 var _ = Describe("Applier", func() {
@@ -26,6 +28,8 @@ var _ = Describe("Applier", func() {
 	BeforeEach(func() {
 		netSh = &fakes.NetShRunner{}
 		portAllocator = &fakes.PortAllocator{}
+		portAllocator.AllocatePortStub = func(_ string, port int) (int, error) { return port, nil }
+		portAllocator.AllocatePortRangeStub = func(_ string, port, _ int) (int, error) { return port, nil }
 
 		applier = netrules.NewApplier(netSh, containerId, portAllocator)
 	})
@@ -69,7 +73,7 @@ var _ = Describe("Applier", func() {
 		})
 
 		It("returns the correct nat and acl policies", func() {
-			nat, acl, err := applier.In(netInRule, containerIP)
+			nats, acls, err := applier.In(netInRule, containerIP)
 			Expect(err).NotTo(HaveOccurred())
 
 			expectedNat := hcsshim.NatPolicy{
@@ -78,7 +82,7 @@ var _ = Describe("Applier", func() {
 				InternalPort: 1000,
 				ExternalPort: 2000,
 			}
-			Expect(*nat).To(Equal(expectedNat))
+			Expect(nats).To(Equal([]*hcsshim.NatPolicy{&expectedNat}))
 
 			expectedAcl := hcsshim.ACLPolicy{
 				Type:           hcsshim.ACL,
@@ -88,7 +92,154 @@ var _ = Describe("Applier", func() {
 				LocalAddresses: "5.4.3.2",
 				LocalPorts:     "1000",
 			}
-			Expect(*acl).To(Equal(expectedAcl))
+			Expect(acls).To(Equal([]*hcsshim.ACLPolicy{&expectedAcl}))
+		})
+
+		It("reserves the host port with the port allocator", func() {
+			_, _, err := applier.In(netInRule, containerIP)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(portAllocator.AllocatePortCallCount()).To(Equal(1))
+			id, p := portAllocator.AllocatePortArgsForCall(0)
+			Expect(id).To(Equal(containerId))
+			Expect(p).To(Equal(2000))
+		})
+
+		Context("the host port is held by another container", func() {
+			BeforeEach(func() {
+				portAllocator.AllocatePortReturns(-1, errors.New("port 2000 is allocated to other-handle"))
+			})
+
+			It("returns the error", func() {
+				_, _, err := applier.In(netInRule, containerIP)
+				Expect(err).To(MatchError("port 2000 is allocated to other-handle"))
+			})
+		})
+
+		Context("the rule maps udp", func() {
+			BeforeEach(func() {
+				netInRule.Protocol = netrules.NetInProtocolUDP
+			})
+
+			It("returns udp nat and acl policies", func() {
+				nats, acls, err := applier.In(netInRule, containerIP)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(nats).To(HaveLen(1))
+				Expect(nats[0].Protocol).To(Equal("UDP"))
+				Expect(acls).To(HaveLen(1))
+				Expect(acls[0].Protocol).To(Equal(uint16(17)))
+			})
+		})
+
+		Context("the rule maps both tcp and udp", func() {
+			BeforeEach(func() {
+				netInRule = netrules.NetIn{
+					ContainerPort: 1000,
+					HostPort:      0,
+					Protocol:      netrules.NetInProtocolBoth,
+				}
+				portAllocator.AllocatePortReturns(1234, nil)
+			})
+
+			It("maps the same host port for each protocol", func() {
+				nats, acls, err := applier.In(netInRule, containerIP)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(nats).To(Equal([]*hcsshim.NatPolicy{
+					{Type: hcsshim.Nat, Protocol: "TCP", InternalPort: 1000, ExternalPort: 1234},
+					{Type: hcsshim.Nat, Protocol: "UDP", InternalPort: 1000, ExternalPort: 1234},
+				}))
+				Expect(acls).To(Equal([]*hcsshim.ACLPolicy{
+					{Type: hcsshim.ACL, Action: hcsshim.Allow, Direction: hcsshim.In, Protocol: 6, LocalAddresses: "5.4.3.2", LocalPorts: "1000"},
+					{Type: hcsshim.ACL, Action: hcsshim.Allow, Direction: hcsshim.In, Protocol: 17, LocalAddresses: "5.4.3.2", LocalPorts: "1000"},
+				}))
+
+				Expect(portAllocator.AllocatePortCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("the rule maps a range of container ports", func() {
+			BeforeEach(func() {
+				netInRule = netrules.NetIn{
+					ContainerPort:    1000,
+					ContainerPortEnd: 1002,
+					HostPort:         0,
+				}
+				portAllocator.AllocatePortRangeReturns(3000, nil)
+			})
+
+			It("allocates a contiguous host range and maps each port", func() {
+				nats, acls, err := applier.In(netInRule, containerIP)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(portAllocator.AllocatePortCallCount()).To(Equal(0))
+				Expect(portAllocator.AllocatePortRangeCallCount()).To(Equal(1))
+				id, p, count := portAllocator.AllocatePortRangeArgsForCall(0)
+				Expect(id).To(Equal(containerId))
+				Expect(p).To(Equal(0))
+				Expect(count).To(Equal(3))
+
+				Expect(nats).To(Equal([]*hcsshim.NatPolicy{
+					{Type: hcsshim.Nat, Protocol: "TCP", InternalPort: 1000, ExternalPort: 3000},
+					{Type: hcsshim.Nat, Protocol: "TCP", InternalPort: 1001, ExternalPort: 3001},
+					{Type: hcsshim.Nat, Protocol: "TCP", InternalPort: 1002, ExternalPort: 3002},
+				}))
+				Expect(acls).To(Equal([]*hcsshim.ACLPolicy{
+					{Type: hcsshim.ACL, Action: hcsshim.Allow, Direction: hcsshim.In, Protocol: 6, LocalAddresses: "5.4.3.2", LocalPorts: "1000-1002"},
+				}))
+			})
+
+			Context("a host port is specified", func() {
+				BeforeEach(func() {
+					netInRule.HostPort = 2000
+					portAllocator.AllocatePortRangeReturns(2000, nil)
+				})
+
+				It("reserves and maps the range starting at the host port", func() {
+					nats, _, err := applier.In(netInRule, containerIP)
+					Expect(err).NotTo(HaveOccurred())
+
+					_, p, count := portAllocator.AllocatePortRangeArgsForCall(0)
+					Expect(p).To(Equal(2000))
+					Expect(count).To(Equal(3))
+					Expect(nats[2].ExternalPort).To(Equal(uint16(2002)))
+				})
+			})
+
+			Context("the range is reversed", func() {
+				BeforeEach(func() {
+					netInRule.ContainerPortEnd = 999
+				})
+
+				It("returns an error", func() {
+					_, _, err := applier.In(netInRule, containerIP)
+					Expect(err).To(MatchError("invalid container port range: 1000-999"))
+				})
+			})
+
+			Context("allocating the range fails", func() {
+				BeforeEach(func() {
+					portAllocator.AllocatePortRangeReturns(0, errors.New("some-error"))
+				})
+
+				It("returns an error", func() {
+					_, _, err := applier.In(netInRule, containerIP)
+					Expect(err).To(MatchError("some-error"))
+				})
+			})
+		})
+
+		Context("the protocol is invalid", func() {
+			BeforeEach(func() {
+				netInRule.Protocol = "sctp"
+			})
+
+			It("returns an error", func() {
+				_, _, err := applier.In(netInRule, containerIP)
+				Expect(err).To(MatchError("invalid protocol: sctp"))
+				Expect(portAllocator.AllocatePortCallCount()).To(Equal(0))
+			})
 		})
 
 		Context("the host port is zero", func() {
@@ -101,7 +252,7 @@ var _ = Describe("Applier", func() {
 			})
 
 			It("uses the port allocator to find an open host port", func() {
-				nat, acl, err := applier.In(netInRule, containerIP)
+				nats, acls, err := applier.In(netInRule, containerIP)
 				Expect(err).NotTo(HaveOccurred())
 
 				expectedNat := hcsshim.NatPolicy{
@@ -110,7 +261,7 @@ var _ = Describe("Applier", func() {
 					InternalPort: 1000,
 					ExternalPort: 1234,
 				}
-				Expect(*nat).To(Equal(expectedNat))
+				Expect(nats).To(Equal([]*hcsshim.NatPolicy{&expectedNat}))
 
 				expectedAcl := hcsshim.ACLPolicy{
 					Type:           hcsshim.ACL,
@@ -120,7 +271,7 @@ var _ = Describe("Applier", func() {
 					LocalAddresses: "5.4.3.2",
 					LocalPorts:     "1000",
 				}
-				Expect(*acl).To(Equal(expectedAcl))
+				Expect(acls).To(Equal([]*hcsshim.ACLPolicy{&expectedAcl}))
 
 				Expect(portAllocator.AllocatePortCallCount()).To(Equal(1))
 				id, p := portAllocator.AllocatePortArgsForCall(0)
@@ -141,6 +292,32 @@ var _ = Describe("Applier", func() {
 		})
 	})
 
+	Describe("Expose", func() {
+		It("returns acl policies for the container ports without allocating host ports", func() {
+			acls, err := applier.Expose(netrules.NetIn{ContainerPort: 1000, ContainerPortEnd: 1002, Protocol: netrules.NetInProtocolBoth}, containerIP)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(acls).To(HaveLen(2))
+			Expect(*acls[0]).To(Equal(hcsshim.ACLPolicy{
+				Type:           hcsshim.ACL,
+				Action:         hcsshim.Allow,
+				Direction:      hcsshim.In,
+				Protocol:       6,
+				LocalAddresses: "5.4.3.2",
+				LocalPorts:     "1000-1002",
+			}))
+			Expect(acls[1].Protocol).To(Equal(uint16(17)))
+
+			Expect(portAllocator.AllocatePortCallCount()).To(Equal(0))
+			Expect(portAllocator.AllocatePortRangeCallCount()).To(Equal(0))
+		})
+
+		It("rejects invalid protocols", func() {
+			_, err := applier.Expose(netrules.NetIn{ContainerPort: 1000, Protocol: "sctp"}, containerIP)
+			Expect(err).To(MatchError("invalid protocol: sctp"))
+		})
+	})
+
 	Describe("Out", func() {
 		var netOutRule netrules.NetOut
 
@@ -180,6 +357,7 @@ var _ = Describe("Applier", func() {
 					LocalAddresses:  "5.4.3.2",
 					RemoteAddresses: "8.8.8.8/32,10.0.0.0/7,12.0.0.0/8,13.0.0.0/32",
 					RemotePorts:     "80-80,8080-8090",
+					Priority:        netrules.DefaultAllowPriority,
 				}
 				Expect(*acl).To(Equal(expectedAcl))
 			})
@@ -202,6 +380,7 @@ var _ = Describe("Applier", func() {
 					LocalAddresses:  "5.4.3.2",
 					RemoteAddresses: "8.8.8.8/32,10.0.0.0/7,12.0.0.0/8,13.0.0.0/32",
 					RemotePorts:     "80-80,8080-8090",
+					Priority:        netrules.DefaultAllowPriority,
 				}
 				Expect(*acl).To(Equal(expectedAcl))
 			})
@@ -223,11 +402,43 @@ var _ = Describe("Applier", func() {
 					Protocol:        uint16(firewall.NET_FW_IP_PROTOCOL_ICMP),
 					LocalAddresses:  "5.4.3.2",
 					RemoteAddresses: "8.8.8.8/32,10.0.0.0/7,12.0.0.0/8,13.0.0.0/32",
+					Priority:        netrules.DefaultAllowPriority,
 				}
 				Expect(*acl).To(Equal(expectedAcl))
 			})
 		})
 
+		Context("an ICMP rule for IPv6 destinations is specified", func() {
+			BeforeEach(func() {
+				netOutRule.Protocol = netrules.ProtocolICMP
+				netOutRule.Networks = []netrules.IPRange{{Start: net.ParseIP("2001:db8::1"), End: net.ParseIP("2001:db8::1")}}
+			})
+
+			It("uses the ICMPv6 protocol", func() {
+				acl, err := applier.Out(netOutRule, containerIP)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(acl.Protocol).To(Equal(uint16(58)))
+				Expect(acl.RemoteAddresses).To(Equal("2001:db8::1/128"))
+			})
+		})
+
+		Context("an ICMP rule with a type is specified", func() {
+			BeforeEach(func() {
+				netOutRule.Protocol = netrules.ProtocolICMP
+				netOutRule.ICMPs = &netrules.ICMPControl{Type: 8}
+			})
+
+			It("ignores the type and logs a warning", func() {
+				buffer := new(bytes.Buffer)
+				logrus.SetOutput(buffer)
+
+				acl, err := applier.Out(netOutRule, containerIP)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(acl.Protocol).To(Equal(uint16(firewall.NET_FW_IP_PROTOCOL_ICMP)))
+				Expect(buffer.String()).To(ContainSubstring("ignoring icmps 8:*: HNS ACL policies cannot match icmp types"))
+			})
+		})
+
 		Context("an ANY rule is specified", func() {
 			BeforeEach(func() {
 				netOutRule.Protocol = netrules.ProtocolAll
@@ -244,11 +455,48 @@ var _ = Describe("Applier", func() {
 					Protocol:        uint16(firewall.NET_FW_IP_PROTOCOL_ANY),
 					LocalAddresses:  "5.4.3.2",
 					RemoteAddresses: "8.8.8.8/32,10.0.0.0/7,12.0.0.0/8,13.0.0.0/32",
+					Priority:        netrules.DefaultAllowPriority,
 				}
 				Expect(*acl).To(Equal(expectedAcl))
 			})
 		})
 
+		Context("a deny rule is specified", func() {
+			BeforeEach(func() {
+				netOutRule.Protocol = netrules.ProtocolTCP
+				netOutRule.Action = netrules.NetOutActionDeny
+			})
+
+			It("returns a block ACL with the default deny priority", func() {
+				acl, err := applier.Out(netOutRule, containerIP)
+				Expect(err).NotTo(HaveOccurred())
+
+				expectedAcl := hcsshim.ACLPolicy{
+					Type:            hcsshim.ACL,
+					Action:          hcsshim.Block,
+					Direction:       hcsshim.Out,
+					Protocol:        uint16(firewall.NET_FW_IP_PROTOCOL_TCP),
+					LocalAddresses:  "5.4.3.2",
+					RemoteAddresses: "8.8.8.8/32,10.0.0.0/7,12.0.0.0/8,13.0.0.0/32",
+					RemotePorts:     "80-80,8080-8090",
+					Priority:        netrules.DefaultDenyPriority,
+				}
+				Expect(*acl).To(Equal(expectedAcl))
+			})
+
+			Context("a priority is given", func() {
+				BeforeEach(func() {
+					netOutRule.Priority = 150
+				})
+
+				It("uses it", func() {
+					acl, err := applier.Out(netOutRule, containerIP)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(acl.Priority).To(Equal(uint16(150)))
+				})
+			})
+		})
+
 		Context("netout contains an ip range that resolves to 0.0.0.0/0", func() {
 			BeforeEach(func() {
 				netOutRule.Networks = []netrules.IPRange{
@@ -272,11 +520,67 @@ var _ = Describe("Applier", func() {
 					Protocol:        uint16(firewall.NET_FW_IP_PROTOCOL_ANY),
 					LocalAddresses:  "5.4.3.2",
 					RemoteAddresses: "",
+					Priority:        netrules.DefaultAllowPriority,
 				}
 				Expect(*acl).To(Equal(expectedAcl))
 			})
 		})
 
+		Context("netout contains IPv6 ranges", func() {
+			BeforeEach(func() {
+				netOutRule.Networks = []netrules.IPRange{
+					ipRangeFromIP(net.ParseIP("2001:db8::1")),
+					netrules.IPRange{
+						Start: net.ParseIP("2001:db8:1::"),
+						End:   net.ParseIP("2001:db8:1::ff"),
+					},
+				}
+				netOutRule.Protocol = netrules.ProtocolAll
+			})
+
+			It("returns an HNS ACL with the IPv6 CIDR blocks", func() {
+				acl, err := applier.Out(netOutRule, containerIP)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(acl.RemoteAddresses).To(Equal("2001:db8::1/128,2001:db8:1::/120"))
+			})
+		})
+
+		Context("netout contains an ip range that resolves to ::/0", func() {
+			BeforeEach(func() {
+				netOutRule.Networks = []netrules.IPRange{
+					ipRangeFromIP(net.ParseIP("2001:db8::1")),
+					netrules.IPRange{
+						Start: net.ParseIP("::"),
+						End:   net.ParseIP("ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff"),
+					},
+				}
+				netOutRule.Protocol = netrules.ProtocolAll
+			})
+
+			It("returns an HNS ACL with empty remote addresses", func() {
+				acl, err := applier.Out(netOutRule, containerIP)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(acl.RemoteAddresses).To(Equal(""))
+			})
+		})
+
+		Context("netout contains a range mixing IPv4 and IPv6 addresses", func() {
+			BeforeEach(func() {
+				netOutRule.Networks = []netrules.IPRange{
+					netrules.IPRange{
+						Start: net.ParseIP("10.0.0.1"),
+						End:   net.ParseIP("2001:db8::1"),
+					},
+				}
+				netOutRule.Protocol = netrules.ProtocolAll
+			})
+
+			It("returns an error", func() {
+				_, err := applier.Out(netOutRule, containerIP)
+				Expect(err).To(MatchError(ContainSubstring("start and end must both be IPv4 or both be IPv6 addresses")))
+			})
+		})
+
 		Context("an invalid protocol is specified", func() {
 			BeforeEach(func() {
 				netOutRule.Protocol = 7
@@ -289,6 +593,17 @@ var _ = Describe("Applier", func() {
 		})
 	})
 
+	Describe("ReleasePorts", func() {
+		It("releases the ports held by the container", func() {
+			Expect(applier.ReleasePorts([]int{40000, 40001})).To(Succeed())
+
+			Expect(portAllocator.ReleasePortsCallCount()).To(Equal(1))
+			id, ports := portAllocator.ReleasePortsArgsForCall(0)
+			Expect(id).To(Equal(containerId))
+			Expect(ports).To(Equal([]int{40000, 40001}))
+		})
+	})
+
 	Describe("Cleanup", func() {
 		It("de-allocates all the ports", func() {
 			Expect(applier.Cleanup()).To(Succeed())
@@ -8,11 +8,11 @@ import (
 )
 
 type PortAllocator struct {
-	AllocatePortStub        func(handle string, port int) (int, error)
+	AllocatePortStub        func(string, int) (int, error)
 	allocatePortMutex       sync.RWMutex
 	allocatePortArgsForCall []struct {
-		handle string
-		port   int
+		arg1 string
+		arg2 int
 	}
 	allocatePortReturns struct {
 		result1 int
@@ -22,10 +22,25 @@ type PortAllocator struct {
 		result1 int
 		result2 error
 	}
-	ReleaseAllPortsStub        func(handle string) error
+	AllocatePortRangeStub        func(string, int, int) (int, error)
+	allocatePortRangeMutex       sync.RWMutex
+	allocatePortRangeArgsForCall []struct {
+		arg1 string
+		arg2 int
+		arg3 int
+	}
+	allocatePortRangeReturns struct {
+		result1 int
+		result2 error
+	}
+	allocatePortRangeReturnsOnCall map[int]struct {
+		result1 int
+		result2 error
+	}
+	ReleaseAllPortsStub        func(string) error
 	releaseAllPortsMutex       sync.RWMutex
 	releaseAllPortsArgsForCall []struct {
-		handle string
+		arg1 string
 	}
 	releaseAllPortsReturns struct {
 		result1 error
@@ -33,26 +48,40 @@ type PortAllocator struct {
 	releaseAllPortsReturnsOnCall map[int]struct {
 		result1 error
 	}
+	ReleasePortsStub        func(string, []int) error
+	releasePortsMutex       sync.RWMutex
+	releasePortsArgsForCall []struct {
+		arg1 string
+		arg2 []int
+	}
+	releasePortsReturns struct {
+		result1 error
+	}
+	releasePortsReturnsOnCall map[int]struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *PortAllocator) AllocatePort(handle string, port int) (int, error) {
+func (fake *PortAllocator) AllocatePort(arg1 string, arg2 int) (int, error) {
 	fake.allocatePortMutex.Lock()
 	ret, specificReturn := fake.allocatePortReturnsOnCall[len(fake.allocatePortArgsForCall)]
 	fake.allocatePortArgsForCall = append(fake.allocatePortArgsForCall, struct {
-		handle string
-		port   int
-	}{handle, port})
-	fake.recordInvocation("AllocatePort", []interface{}{handle, port})
+		arg1 string
+		arg2 int
+	}{arg1, arg2})
+	stub := fake.AllocatePortStub
+	fakeReturns := fake.allocatePortReturns
+	fake.recordInvocation("AllocatePort", []interface{}{arg1, arg2})
 	fake.allocatePortMutex.Unlock()
-	if fake.AllocatePortStub != nil {
-		return fake.AllocatePortStub(handle, port)
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	return fake.allocatePortReturns.result1, fake.allocatePortReturns.result2
+	return fakeReturns.result1, fakeReturns.result2
 }
 
 func (fake *PortAllocator) AllocatePortCallCount() int {
@@ -61,13 +90,22 @@ func (fake *PortAllocator) AllocatePortCallCount() int {
 	return len(fake.allocatePortArgsForCall)
 }
 
+func (fake *PortAllocator) AllocatePortCalls(stub func(string, int) (int, error)) {
+	fake.allocatePortMutex.Lock()
+	defer fake.allocatePortMutex.Unlock()
+	fake.AllocatePortStub = stub
+}
+
 func (fake *PortAllocator) AllocatePortArgsForCall(i int) (string, int) {
 	fake.allocatePortMutex.RLock()
 	defer fake.allocatePortMutex.RUnlock()
-	return fake.allocatePortArgsForCall[i].handle, fake.allocatePortArgsForCall[i].port
+	argsForCall := fake.allocatePortArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *PortAllocator) AllocatePortReturns(result1 int, result2 error) {
+	fake.allocatePortMutex.Lock()
+	defer fake.allocatePortMutex.Unlock()
 	fake.AllocatePortStub = nil
 	fake.allocatePortReturns = struct {
 		result1 int
@@ -76,6 +114,8 @@ func (fake *PortAllocator) AllocatePortReturns(result1 int, result2 error) {
 }
 
 func (fake *PortAllocator) AllocatePortReturnsOnCall(i int, result1 int, result2 error) {
+	fake.allocatePortMutex.Lock()
+	defer fake.allocatePortMutex.Unlock()
 	fake.AllocatePortStub = nil
 	if fake.allocatePortReturnsOnCall == nil {
 		fake.allocatePortReturnsOnCall = make(map[int]struct {
@@ -89,21 +129,89 @@ func (fake *PortAllocator) AllocatePortReturnsOnCall(i int, result1 int, result2
 	}{result1, result2}
 }
 
-func (fake *PortAllocator) ReleaseAllPorts(handle string) error {
+func (fake *PortAllocator) AllocatePortRange(arg1 string, arg2 int, arg3 int) (int, error) {
+	fake.allocatePortRangeMutex.Lock()
+	ret, specificReturn := fake.allocatePortRangeReturnsOnCall[len(fake.allocatePortRangeArgsForCall)]
+	fake.allocatePortRangeArgsForCall = append(fake.allocatePortRangeArgsForCall, struct {
+		arg1 string
+		arg2 int
+		arg3 int
+	}{arg1, arg2, arg3})
+	stub := fake.AllocatePortRangeStub
+	fakeReturns := fake.allocatePortRangeReturns
+	fake.recordInvocation("AllocatePortRange", []interface{}{arg1, arg2, arg3})
+	fake.allocatePortRangeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *PortAllocator) AllocatePortRangeCallCount() int {
+	fake.allocatePortRangeMutex.RLock()
+	defer fake.allocatePortRangeMutex.RUnlock()
+	return len(fake.allocatePortRangeArgsForCall)
+}
+
+func (fake *PortAllocator) AllocatePortRangeCalls(stub func(string, int, int) (int, error)) {
+	fake.allocatePortRangeMutex.Lock()
+	defer fake.allocatePortRangeMutex.Unlock()
+	fake.AllocatePortRangeStub = stub
+}
+
+func (fake *PortAllocator) AllocatePortRangeArgsForCall(i int) (string, int, int) {
+	fake.allocatePortRangeMutex.RLock()
+	defer fake.allocatePortRangeMutex.RUnlock()
+	argsForCall := fake.allocatePortRangeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *PortAllocator) AllocatePortRangeReturns(result1 int, result2 error) {
+	fake.allocatePortRangeMutex.Lock()
+	defer fake.allocatePortRangeMutex.Unlock()
+	fake.AllocatePortRangeStub = nil
+	fake.allocatePortRangeReturns = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PortAllocator) AllocatePortRangeReturnsOnCall(i int, result1 int, result2 error) {
+	fake.allocatePortRangeMutex.Lock()
+	defer fake.allocatePortRangeMutex.Unlock()
+	fake.AllocatePortRangeStub = nil
+	if fake.allocatePortRangeReturnsOnCall == nil {
+		fake.allocatePortRangeReturnsOnCall = make(map[int]struct {
+			result1 int
+			result2 error
+		})
+	}
+	fake.allocatePortRangeReturnsOnCall[i] = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PortAllocator) ReleaseAllPorts(arg1 string) error {
 	fake.releaseAllPortsMutex.Lock()
 	ret, specificReturn := fake.releaseAllPortsReturnsOnCall[len(fake.releaseAllPortsArgsForCall)]
 	fake.releaseAllPortsArgsForCall = append(fake.releaseAllPortsArgsForCall, struct {
-		handle string
-	}{handle})
-	fake.recordInvocation("ReleaseAllPorts", []interface{}{handle})
+		arg1 string
+	}{arg1})
+	stub := fake.ReleaseAllPortsStub
+	fakeReturns := fake.releaseAllPortsReturns
+	fake.recordInvocation("ReleaseAllPorts", []interface{}{arg1})
 	fake.releaseAllPortsMutex.Unlock()
-	if fake.ReleaseAllPortsStub != nil {
-		return fake.ReleaseAllPortsStub(handle)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	return fake.releaseAllPortsReturns.result1
+	return fakeReturns.result1
 }
 
 func (fake *PortAllocator) ReleaseAllPortsCallCount() int {
@@ -112,13 +220,22 @@ func (fake *PortAllocator) ReleaseAllPortsCallCount() int {
 	return len(fake.releaseAllPortsArgsForCall)
 }
 
+func (fake *PortAllocator) ReleaseAllPortsCalls(stub func(string) error) {
+	fake.releaseAllPortsMutex.Lock()
+	defer fake.releaseAllPortsMutex.Unlock()
+	fake.ReleaseAllPortsStub = stub
+}
+
 func (fake *PortAllocator) ReleaseAllPortsArgsForCall(i int) string {
 	fake.releaseAllPortsMutex.RLock()
 	defer fake.releaseAllPortsMutex.RUnlock()
-	return fake.releaseAllPortsArgsForCall[i].handle
+	argsForCall := fake.releaseAllPortsArgsForCall[i]
+	return argsForCall.arg1
 }
 
 func (fake *PortAllocator) ReleaseAllPortsReturns(result1 error) {
+	fake.releaseAllPortsMutex.Lock()
+	defer fake.releaseAllPortsMutex.Unlock()
 	fake.ReleaseAllPortsStub = nil
 	fake.releaseAllPortsReturns = struct {
 		result1 error
@@ -126,6 +243,8 @@ func (fake *PortAllocator) ReleaseAllPortsReturns(result1 error) {
 }
 
 func (fake *PortAllocator) ReleaseAllPortsReturnsOnCall(i int, result1 error) {
+	fake.releaseAllPortsMutex.Lock()
+	defer fake.releaseAllPortsMutex.Unlock()
 	fake.ReleaseAllPortsStub = nil
 	if fake.releaseAllPortsReturnsOnCall == nil {
 		fake.releaseAllPortsReturnsOnCall = make(map[int]struct {
@@ -137,14 +256,89 @@ func (fake *PortAllocator) ReleaseAllPortsReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *PortAllocator) ReleasePorts(arg1 string, arg2 []int) error {
+	var arg2Copy []int
+	if arg2 != nil {
+		arg2Copy = make([]int, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.releasePortsMutex.Lock()
+	ret, specificReturn := fake.releasePortsReturnsOnCall[len(fake.releasePortsArgsForCall)]
+	fake.releasePortsArgsForCall = append(fake.releasePortsArgsForCall, struct {
+		arg1 string
+		arg2 []int
+	}{arg1, arg2Copy})
+	stub := fake.ReleasePortsStub
+	fakeReturns := fake.releasePortsReturns
+	fake.recordInvocation("ReleasePorts", []interface{}{arg1, arg2Copy})
+	fake.releasePortsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *PortAllocator) ReleasePortsCallCount() int {
+	fake.releasePortsMutex.RLock()
+	defer fake.releasePortsMutex.RUnlock()
+	return len(fake.releasePortsArgsForCall)
+}
+
+func (fake *PortAllocator) ReleasePortsCalls(stub func(string, []int) error) {
+	fake.releasePortsMutex.Lock()
+	defer fake.releasePortsMutex.Unlock()
+	fake.ReleasePortsStub = stub
+}
+
+func (fake *PortAllocator) ReleasePortsArgsForCall(i int) (string, []int) {
+	fake.releasePortsMutex.RLock()
+	defer fake.releasePortsMutex.RUnlock()
+	argsForCall := fake.releasePortsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *PortAllocator) ReleasePortsReturns(result1 error) {
+	fake.releasePortsMutex.Lock()
+	defer fake.releasePortsMutex.Unlock()
+	fake.ReleasePortsStub = nil
+	fake.releasePortsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *PortAllocator) ReleasePortsReturnsOnCall(i int, result1 error) {
+	fake.releasePortsMutex.Lock()
+	defer fake.releasePortsMutex.Unlock()
+	fake.ReleasePortsStub = nil
+	if fake.releasePortsReturnsOnCall == nil {
+		fake.releasePortsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.releasePortsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *PortAllocator) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.allocatePortMutex.RLock()
 	defer fake.allocatePortMutex.RUnlock()
+	fake.allocatePortRangeMutex.RLock()
+	defer fake.allocatePortRangeMutex.RUnlock()
 	fake.releaseAllPortsMutex.RLock()
 	defer fake.releaseAllPortsMutex.RUnlock()
-	return fake.invocations
+	fake.releasePortsMutex.RLock()
+	defer fake.releasePortsMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
 }
 
 func (fake *PortAllocator) recordInvocation(key string, args []interface{}) {
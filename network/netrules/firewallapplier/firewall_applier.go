@@ -2,11 +2,11 @@ package firewallapplier
 
 import (
 	"fmt"
-	"strconv"
 
 	"code.cloudfoundry.org/winc/network/firewall"
 	"code.cloudfoundry.org/winc/network/netrules"
 	"github.com/Microsoft/hcsshim"
+	"github.com/sirupsen/logrus"
 )
 
 //go:generate counterfeiter -o fakes/netsh_runner.go --fake-name NetShRunner . NetShRunner
@@ -17,7 +17,9 @@ type NetShRunner interface {
 //go:generate counterfeiter -o fakes/port_allocator.go --fake-name PortAllocator . PortAllocator
 type PortAllocator interface {
 	AllocatePort(handle string, port int) (int, error)
+	AllocatePortRange(handle string, port, count int) (int, error)
 	ReleaseAllPorts(handle string) error
+	ReleasePorts(handle string, ports []int) error
 }
 
 //go:generate counterfeiter -o fakes/firewall.go --fake-name Firewall . Firewall
@@ -43,50 +45,134 @@ func NewApplier(netSh NetShRunner, containerId string, portAllocator PortAllocat
 	}
 }
 
-func (a *Applier) In(rule netrules.NetIn, containerIP string) (*hcsshim.NatPolicy, *hcsshim.ACLPolicy, error) {
-	externalPort := rule.HostPort
-
-	if externalPort == 0 {
-		allocatedPort, err := a.portAllocator.AllocatePort(a.containerId, 0)
-		if err != nil {
-			return nil, nil, err
-		}
-		externalPort = uint32(allocatedPort)
+func (a *Applier) In(rule netrules.NetIn, containerIP string) ([]*hcsshim.NatPolicy, []*hcsshim.ACLPolicy, error) {
+	protocols, err := rule.Protocol.Protocols()
+	if err != nil {
+		return nil, nil, err
 	}
 
-	fr := firewall.Rule{
-		Name:           a.containerId,
-		Action:         firewall.NET_FW_ACTION_ALLOW,
-		Direction:      firewall.NET_FW_RULE_DIR_IN,
-		Protocol:       firewall.NET_FW_IP_PROTOCOL_TCP,
-		LocalAddresses: containerIP,
-		LocalPorts:     strconv.FormatUint(uint64(rule.ContainerPort), 10),
+	count, err := rule.PortCount()
+	if err != nil {
+		return nil, nil, err
 	}
 
-	if err := a.firewall.CreateRule(fr); err != nil {
+	externalPort, err := a.externalPort(rule, count)
+	if err != nil {
 		return nil, nil, err
 	}
 
-	if err := a.OpenPort(rule.ContainerPort); err != nil {
+	nats, err := a.in(rule, containerIP, protocols, externalPort, count)
+	if err != nil {
+		// hand back the ports allocated or reserved for this rule
+		allocated := []int{}
+		for i := 0; i < count; i++ {
+			allocated = append(allocated, int(externalPort)+i)
+		}
+		if releaseErr := a.ReleasePorts(allocated); releaseErr != nil {
+			return nil, nil, fmt.Errorf("%s, %s", err.Error(), releaseErr.Error())
+		}
+	}
+	if err != nil {
 		return nil, nil, err
 	}
 
-	return &hcsshim.NatPolicy{
-		Type:         hcsshim.Nat,
-		Protocol:     "TCP",
-		InternalPort: uint16(rule.ContainerPort),
-		ExternalPort: uint16(externalPort),
-	}, nil, nil
+	return nats, nil, nil
+}
+
+// Expose creates firewall rules allowing inbound traffic to the container
+// ports of rule without mapping host ports, for containers with addresses
+// routable from outside the host
+func (a *Applier) Expose(rule netrules.NetIn, containerIP string) ([]*hcsshim.ACLPolicy, error) {
+	protocols, err := rule.Protocol.Protocols()
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := rule.PortCount()
+	if err != nil {
+		return nil, err
+	}
+
+	// the NAT policies built for the container ports are not needed
+	if _, err := a.in(rule, containerIP, protocols, rule.ContainerPort, count); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (a *Applier) in(rule netrules.NetIn, containerIP string, protocols []netrules.Protocol, externalPort uint32, count int) ([]*hcsshim.NatPolicy, error) {
+	nats := []*hcsshim.NatPolicy{}
+	for _, protocol := range protocols {
+		fr := firewall.Rule{
+			Name:           a.containerId,
+			Action:         firewall.NET_FW_ACTION_ALLOW,
+			Direction:      firewall.NET_FW_RULE_DIR_IN,
+			Protocol:       protocol.FirewallProtocol(),
+			LocalAddresses: containerIP,
+			LocalPorts:     rule.ContainerPorts(count),
+		}
+
+		if err := a.firewall.CreateRule(fr); err != nil {
+			return nil, err
+		}
+
+		// URL reservations only apply to http over tcp, which is not served
+		// from port ranges
+		if protocol == netrules.ProtocolTCP && count == 1 {
+			if err := a.OpenPort(rule.ContainerPort); err != nil {
+				return nil, err
+			}
+		}
+
+		for i := 0; i < count; i++ {
+			nats = append(nats, &hcsshim.NatPolicy{
+				Type:         hcsshim.Nat,
+				Protocol:     protocol.NatProtocol(),
+				InternalPort: uint16(rule.ContainerPort + uint32(i)),
+				ExternalPort: uint16(externalPort + uint32(i)),
+			})
+		}
+	}
+
+	return nats, nil
+}
 
+func (a *Applier) externalPort(rule netrules.NetIn, count int) (uint32, error) {
+	// explicit host ports are reserved too, so that they are checked against
+	// the ports held by other containers
+	var allocatedPort int
+	var err error
+	if count == 1 {
+		allocatedPort, err = a.portAllocator.AllocatePort(a.containerId, int(rule.HostPort))
+	} else {
+		allocatedPort, err = a.portAllocator.AllocatePortRange(a.containerId, int(rule.HostPort), count)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return uint32(allocatedPort), nil
 }
 
 func (a *Applier) Out(rule netrules.NetOut, containerIP string) (*hcsshim.ACLPolicy, error) {
+	remoteAddresses, err := netrules.FirewallRuleIPRange(rule.Networks)
+	if err != nil {
+		return nil, err
+	}
+
 	fr := firewall.Rule{
 		Name:            a.containerId,
 		Action:          firewall.NET_FW_ACTION_ALLOW,
 		Direction:       firewall.NET_FW_RULE_DIR_OUT,
 		LocalAddresses:  containerIP,
-		RemoteAddresses: netrules.FirewallRuleIPRange(rule.Networks),
+		RemoteAddresses: remoteAddresses,
+	}
+
+	// windows firewall always evaluates block rules before allow rules, so
+	// there is no priority to set
+	if rule.IsDeny() {
+		fr.Action = firewall.NET_FW_ACTION_BLOCK
 	}
 
 	switch rule.Protocol {
@@ -97,7 +183,10 @@ func (a *Applier) Out(rule netrules.NetOut, containerIP string) (*hcsshim.ACLPol
 		fr.RemotePorts = netrules.FirewallRulePortRange(rule.Ports)
 		fr.Protocol = firewall.NET_FW_IP_PROTOCOL_UDP
 	case netrules.ProtocolICMP:
-		fr.Protocol = firewall.NET_FW_IP_PROTOCOL_ICMP
+		fr.Protocol = rule.ICMPProtocol()
+		if rule.ICMPs != nil {
+			logrus.Warnf("ignoring icmps %s: firewall rules are created for every icmp type", rule.ICMPs)
+		}
 	case netrules.ProtocolAll:
 		fr.Protocol = firewall.NET_FW_IP_PROTOCOL_ANY
 	default:
@@ -131,3 +220,8 @@ func (a *Applier) OpenPort(port uint32) error {
 	args := []string{"http", "add", "urlacl", fmt.Sprintf("url=http://*:%d/", port), "user=Users"}
 	return a.netSh.RunContainer(args)
 }
+
+// ReleasePorts returns host ports allocated for rules that could not be applied
+func (a *Applier) ReleasePorts(ports []int) error {
+	return a.portAllocator.ReleasePorts(a.containerId, ports)
+}
@@ -1,6 +1,7 @@
 package firewallapplier_test
 
 import (
+	"bytes"
 	"errors"
 	"net"
 
@@ -11,6 +12,7 @@ import (
 	"github.com/Microsoft/hcsshim"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/sirupsen/logrus"
 )
 
 var _ = Describe("Firewall Applier", func() {
@@ -27,6 +29,8 @@ var _ = Describe("Firewall Applier", func() {
 	BeforeEach(func() {
 		netSh = &fakes.NetShRunner{}
 		portAllocator = &fakes.PortAllocator{}
+		portAllocator.AllocatePortStub = func(_ string, port int) (int, error) { return port, nil }
+		portAllocator.AllocatePortRangeStub = func(_ string, port, _ int) (int, error) { return port, nil }
 		fw = &fakes.Firewall{}
 
 		applier = firewallapplier.NewApplier(netSh, containerId, portAllocator, fw)
@@ -60,7 +64,7 @@ var _ = Describe("Firewall Applier", func() {
 		})
 
 		It("returns the correct Nat Policy", func() {
-			nat, _, err := applier.In(netInRule, containerIP)
+			nats, _, err := applier.In(netInRule, containerIP)
 			Expect(err).NotTo(HaveOccurred())
 
 			expectedNat := hcsshim.NatPolicy{
@@ -70,7 +74,17 @@ var _ = Describe("Firewall Applier", func() {
 				ExternalPort: 2000,
 			}
 
-			Expect(*nat).To(Equal(expectedNat))
+			Expect(nats).To(Equal([]*hcsshim.NatPolicy{&expectedNat}))
+		})
+
+		It("reserves the host port with the port allocator", func() {
+			_, _, err := applier.In(netInRule, containerIP)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(portAllocator.AllocatePortCallCount()).To(Equal(1))
+			id, p := portAllocator.AllocatePortArgsForCall(0)
+			Expect(id).To(Equal(containerId))
+			Expect(p).To(Equal(2000))
 		})
 
 		It("opens the port inside the container", func() {
@@ -82,6 +96,78 @@ var _ = Describe("Firewall Applier", func() {
 			Expect(netSh.RunContainerArgsForCall(0)).To(Equal(expectedArgs))
 		})
 
+		Context("the rule maps both tcp and udp", func() {
+			BeforeEach(func() {
+				netInRule.Protocol = netrules.NetInProtocolBoth
+			})
+
+			It("creates a firewall rule and nat policy for each protocol", func() {
+				nats, _, err := applier.In(netInRule, containerIP)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fw.CreateRuleCallCount()).To(Equal(2))
+				Expect(fw.CreateRuleArgsForCall(0).Protocol).To(Equal(firewall.NET_FW_IP_PROTOCOL_TCP))
+				Expect(fw.CreateRuleArgsForCall(1).Protocol).To(Equal(firewall.NET_FW_IP_PROTOCOL_UDP))
+
+				Expect(nats).To(Equal([]*hcsshim.NatPolicy{
+					{Type: hcsshim.Nat, Protocol: "TCP", InternalPort: 1000, ExternalPort: 2000},
+					{Type: hcsshim.Nat, Protocol: "UDP", InternalPort: 1000, ExternalPort: 2000},
+				}))
+			})
+
+			It("only opens the port for http over tcp", func() {
+				_, _, err := applier.In(netInRule, containerIP)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(netSh.RunContainerCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("the rule maps a range of container ports", func() {
+			BeforeEach(func() {
+				netInRule = netrules.NetIn{
+					ContainerPort:    1000,
+					ContainerPortEnd: 1002,
+					HostPort:         0,
+				}
+				portAllocator.AllocatePortRangeReturns(3000, nil)
+			})
+
+			It("creates a single firewall rule for the range and a nat policy per port", func() {
+				nats, _, err := applier.In(netInRule, containerIP)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fw.CreateRuleCallCount()).To(Equal(1))
+				Expect(fw.CreateRuleArgsForCall(0).LocalPorts).To(Equal("1000-1002"))
+
+				_, _, count := portAllocator.AllocatePortRangeArgsForCall(0)
+				Expect(count).To(Equal(3))
+
+				Expect(nats).To(Equal([]*hcsshim.NatPolicy{
+					{Type: hcsshim.Nat, Protocol: "TCP", InternalPort: 1000, ExternalPort: 3000},
+					{Type: hcsshim.Nat, Protocol: "TCP", InternalPort: 1001, ExternalPort: 3001},
+					{Type: hcsshim.Nat, Protocol: "TCP", InternalPort: 1002, ExternalPort: 3002},
+				}))
+			})
+
+			It("does not add url reservations", func() {
+				_, _, err := applier.In(netInRule, containerIP)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(netSh.RunContainerCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("the protocol is invalid", func() {
+			BeforeEach(func() {
+				netInRule.Protocol = "sctp"
+			})
+
+			It("returns an error", func() {
+				_, _, err := applier.In(netInRule, containerIP)
+				Expect(err).To(MatchError("invalid protocol: sctp"))
+				Expect(fw.CreateRuleCallCount()).To(Equal(0))
+			})
+		})
+
 		Context("opening the port fails", func() {
 			BeforeEach(func() {
 				netSh.RunContainerReturns(errors.New("couldn't exec netsh"))
@@ -104,7 +190,7 @@ var _ = Describe("Firewall Applier", func() {
 
 			It("uses the port allocator to find an open host port", func() {
 
-				nat, _, err := applier.In(netInRule, containerIP)
+				nats, _, err := applier.In(netInRule, containerIP)
 				Expect(err).NotTo(HaveOccurred())
 
 				expectedNat := hcsshim.NatPolicy{
@@ -114,7 +200,7 @@ var _ = Describe("Firewall Applier", func() {
 					ExternalPort: 1234,
 				}
 
-				Expect(*nat).To(Equal(expectedNat))
+				Expect(nats).To(Equal([]*hcsshim.NatPolicy{&expectedNat}))
 
 				Expect(portAllocator.AllocatePortCallCount()).To(Equal(1))
 				id, p := portAllocator.AllocatePortArgsForCall(0)
@@ -132,6 +218,58 @@ var _ = Describe("Firewall Applier", func() {
 					Expect(err).To(MatchError("some-error"))
 				})
 			})
+
+			Context("when creating the firewall rule fails", func() {
+				BeforeEach(func() {
+					fw.CreateRuleReturns(errors.New("cannot create rule"))
+				})
+
+				It("releases the allocated port and returns an error", func() {
+					_, _, err := applier.In(netInRule, containerIP)
+					Expect(err).To(MatchError("cannot create rule"))
+
+					Expect(portAllocator.ReleasePortsCallCount()).To(Equal(1))
+					id, ports := portAllocator.ReleasePortsArgsForCall(0)
+					Expect(id).To(Equal(containerId))
+					Expect(ports).To(Equal([]int{1234}))
+				})
+			})
+		})
+
+		Context("when creating the firewall rule fails for a fixed host port", func() {
+			BeforeEach(func() {
+				fw.CreateRuleReturns(errors.New("cannot create rule"))
+			})
+
+			It("releases the reserved port and returns an error", func() {
+				_, _, err := applier.In(netInRule, containerIP)
+				Expect(err).To(MatchError("cannot create rule"))
+
+				Expect(portAllocator.ReleasePortsCallCount()).To(Equal(1))
+				id, ports := portAllocator.ReleasePortsArgsForCall(0)
+				Expect(id).To(Equal(containerId))
+				Expect(ports).To(Equal([]int{2000}))
+			})
+		})
+	})
+
+	Describe("Expose", func() {
+		It("creates a firewall rule for the container port without allocating a host port", func() {
+			acls, err := applier.Expose(netrules.NetIn{ContainerPort: 1000}, containerIP)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(acls).To(BeEmpty())
+
+			Expect(fw.CreateRuleCallCount()).To(Equal(1))
+			Expect(fw.CreateRuleArgsForCall(0)).To(Equal(firewall.Rule{
+				Name:           "containerabc",
+				Direction:      firewall.NET_FW_RULE_DIR_IN,
+				Action:         firewall.NET_FW_ACTION_ALLOW,
+				LocalAddresses: "5.4.3.2",
+				LocalPorts:     "1000",
+				Protocol:       firewall.NET_FW_IP_PROTOCOL_TCP,
+			}))
+
+			Expect(portAllocator.AllocatePortCallCount()).To(Equal(0))
 		})
 	})
 
@@ -255,6 +393,77 @@ var _ = Describe("Firewall Applier", func() {
 			})
 		})
 
+		Context("a deny rule is specified", func() {
+			BeforeEach(func() {
+				protocol = netrules.ProtocolAll
+			})
+
+			It("creates a block rule on the host", func() {
+				netOutRule.Action = netrules.NetOutActionDeny
+				_, err := applier.Out(netOutRule, containerIP)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fw.CreateRuleCallCount()).To(Equal(1))
+				Expect(fw.CreateRuleArgsForCall(0).Action).To(Equal(firewall.NET_FW_ACTION_BLOCK))
+			})
+		})
+
+		Context("an ICMP rule with a type and code is specified", func() {
+			BeforeEach(func() {
+				protocol = netrules.ProtocolICMP
+			})
+
+			It("creates a firewall rule for every ICMP message and logs a warning", func() {
+				buffer := new(bytes.Buffer)
+				logrus.SetOutput(buffer)
+
+				code := netrules.ICMPCode(0)
+				netOutRule.ICMPs = &netrules.ICMPControl{Type: 8, Code: &code}
+
+				_, err := applier.Out(netOutRule, containerIP)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fw.CreateRuleArgsForCall(0).Protocol).To(Equal(firewall.NET_FW_IP_PROTOCOL_ICMP))
+				Expect(buffer.String()).To(ContainSubstring("ignoring icmps 8:0"))
+			})
+		})
+
+		Context("an ICMP rule for IPv6 destinations is specified", func() {
+			BeforeEach(func() {
+				protocol = netrules.ProtocolICMP
+			})
+
+			It("creates an ICMPv6 firewall rule", func() {
+				netOutRule.Networks = []netrules.IPRange{{Start: net.ParseIP("2001:db8::1"), End: net.ParseIP("2001:db8::1")}}
+
+				_, err := applier.Out(netOutRule, containerIP)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(fw.CreateRuleArgsForCall(0).Protocol).To(Equal(firewall.NET_FW_IP_PROTOCOL_ICMPV6))
+			})
+		})
+
+		Context("IPv6 ranges are specified", func() {
+			BeforeEach(func() {
+				protocol = netrules.ProtocolAll
+			})
+
+			It("creates a firewall rule with the IPv6 ranges", func() {
+				netOutRule.Networks = []netrules.IPRange{{Start: net.ParseIP("2001:db8::1"), End: net.ParseIP("2001:db8::ff")}}
+
+				_, err := applier.Out(netOutRule, containerIP)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(fw.CreateRuleArgsForCall(0).RemoteAddresses).To(Equal("2001:db8::1-2001:db8::ff"))
+			})
+
+			It("rejects ranges mixing IPv4 and IPv6 addresses", func() {
+				netOutRule.Networks = []netrules.IPRange{{Start: net.ParseIP("10.0.0.1"), End: net.ParseIP("2001:db8::ff")}}
+
+				_, err := applier.Out(netOutRule, containerIP)
+				Expect(err).To(MatchError(ContainSubstring("start and end must both be IPv4 or both be IPv6 addresses")))
+				Expect(fw.CreateRuleCallCount()).To(Equal(0))
+			})
+		})
+
 		Context("an invalid protocol is specified", func() {
 			BeforeEach(func() {
 				protocol = 7
@@ -269,6 +478,17 @@ var _ = Describe("Firewall Applier", func() {
 		})
 	})
 
+	Describe("ReleasePorts", func() {
+		It("releases the ports held by the container", func() {
+			Expect(applier.ReleasePorts([]int{40000, 40001})).To(Succeed())
+
+			Expect(portAllocator.ReleasePortsCallCount()).To(Equal(1))
+			id, ports := portAllocator.ReleasePortsArgsForCall(0)
+			Expect(id).To(Equal(containerId))
+			Expect(ports).To(Equal([]int{40000, 40001}))
+		})
+	})
+
 	Describe("Cleanup", func() {
 		It("removes the firewall rules applied to the container and de-allocates all the ports", func() {
 			Expect(applier.Cleanup()).To(Succeed())
@@ -66,7 +66,47 @@ var _ = Describe("IPRangeToCIDRs", func() {
 				"5.6.7.0/29",
 				"5.6.7.8/32"},
 		),
+
+		Entry("::-::", ipRange("::-::"), []string{"::/128"}),
+		Entry("::-ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff", ipRange("::-ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff"), []string{"::/0"}),
+		Entry("ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff-ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff",
+			ipRange("ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff-ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff"),
+			[]string{"ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff/128"}),
+		Entry("2001:db8::-2001:db8:0:ffff:ffff:ffff:ffff:ffff", ipRange("2001:db8::-2001:db8:0:ffff:ffff:ffff:ffff:ffff"), []string{"2001:db8::/48"}),
+
+		Entry("2001:db8::a-2001:db8::1f", ipRange("2001:db8::a-2001:db8::1f"),
+			[]string{
+				"2001:db8::a/127",
+				"2001:db8::c/126",
+				"2001:db8::10/124"},
+		),
+
+		Entry("2001:db8::ffff:ffff:ffff:ffff-2001:db8:0:1::1", ipRange("2001:db8::ffff:ffff:ffff:ffff-2001:db8:0:1::1"),
+			[]string{
+				"2001:db8::ffff:ffff:ffff:ffff/128",
+				"2001:db8:0:1::/127"},
+		),
 	)
+
+	Context("the range is reversed", func() {
+		It("returns no CIDR blocks", func() {
+			Expect(netrules.IPRangeToCIDRs(ipRange("10.0.0.2-10.0.0.1"))).To(BeEmpty())
+		})
+	})
+
+	Context("the range mixes IPv4 and IPv6 addresses", func() {
+		It("returns an error", func() {
+			_, err := netrules.IPRangeToCIDRs(ipRange("10.0.0.1-2001:db8::1"))
+			Expect(err).To(MatchError("invalid ip range 10.0.0.1-2001:db8::1: start and end must both be IPv4 or both be IPv6 addresses"))
+		})
+	})
+
+	Context("the range is missing an address", func() {
+		It("returns an error", func() {
+			_, err := netrules.IPRangeToCIDRs(netrules.IPRange{Start: net.ParseIP("10.0.0.1")})
+			Expect(err).To(MatchError("invalid ip range: 10.0.0.1-<nil>"))
+		})
+	})
 })
 
 func ipRange(r string) netrules.IPRange {
@@ -2,18 +2,81 @@ package netrules
 
 import (
 	"fmt"
+	"math/big"
 	"net"
+	"strconv"
 	"strings"
+
+	"code.cloudfoundry.org/winc/network/firewall"
 )
 
 type PortMapping struct {
 	HostPort      uint32
 	ContainerPort uint32
+	Protocol      string
 }
 
 type NetIn struct {
 	HostPort      uint32 `json:"host_port"`
 	ContainerPort uint32 `json:"container_port"`
+
+	// the last port of a range of container ports starting at ContainerPort,
+	// mapped to a contiguous range of host ports starting at HostPort; default
+	// a single port
+	ContainerPortEnd uint32 `json:"container_port_end,omitempty"`
+
+	// the protocols to be mapped; tcp, udp or both; default tcp
+	Protocol NetInProtocol `json:"protocol,omitempty"`
+}
+
+// PortCount returns the number of contiguous ports mapped by the rule
+func (r NetIn) PortCount() (int, error) {
+	if r.ContainerPortEnd == 0 || r.ContainerPortEnd == r.ContainerPort {
+		return 1, nil
+	}
+
+	if r.ContainerPortEnd < r.ContainerPort {
+		return 0, fmt.Errorf("invalid container port range: %d-%d", r.ContainerPort, r.ContainerPortEnd)
+	}
+
+	count := int(r.ContainerPortEnd-r.ContainerPort) + 1
+	if r.HostPort != 0 && int(r.HostPort)+count-1 > 65535 {
+		return 0, fmt.Errorf("invalid host port range: %d-%d", r.HostPort, int(r.HostPort)+count-1)
+	}
+
+	return count, nil
+}
+
+// ContainerPorts returns the container ports mapped by the rule in the form
+// used by firewall rules and ACL policies
+func (r NetIn) ContainerPorts(count int) string {
+	if count == 1 {
+		return strconv.FormatUint(uint64(r.ContainerPort), 10)
+	}
+
+	return PortRange{Start: uint16(r.ContainerPort), End: uint16(r.ContainerPort) + uint16(count-1)}.String()
+}
+
+type NetInProtocol string
+
+const (
+	NetInProtocolTCP  NetInProtocol = "tcp"
+	NetInProtocolUDP  NetInProtocol = "udp"
+	NetInProtocolBoth NetInProtocol = "both"
+)
+
+// Protocols returns the transport protocols a NetIn rule maps
+func (p NetInProtocol) Protocols() ([]Protocol, error) {
+	switch NetInProtocol(strings.ToLower(string(p))) {
+	case "", NetInProtocolTCP:
+		return []Protocol{ProtocolTCP}, nil
+	case NetInProtocolUDP:
+		return []Protocol{ProtocolUDP}, nil
+	case NetInProtocolBoth:
+		return []Protocol{ProtocolTCP, ProtocolUDP}, nil
+	default:
+		return nil, fmt.Errorf("invalid protocol: %s", p)
+	}
 }
 
 type NetOut struct {
@@ -25,6 +88,93 @@ type NetOut struct {
 
 	// a list of ranges of ports to whitelist; Start to End inclusive; ignored if Protocol is ICMP; default all
 	Ports []PortRange `json:"ports,omitempty"`
+
+	// the ICMP type and code to whitelist; accepted from Garden but ignored,
+	// as neither HNS ACL policies nor winc's firewall rules match ICMP types
+	ICMPs *ICMPControl `json:"icmps,omitempty"`
+
+	// whether connections allowed by the rule should be audited
+	Log bool `json:"log,omitempty"`
+
+	// whether matching traffic is allowed or denied; default allow
+	Action NetOutAction `json:"action,omitempty"`
+
+	// the order in which the rule is evaluated, lowest first; defaults to
+	// DefaultDenyPriority for deny rules and DefaultAllowPriority otherwise
+	Priority uint16 `json:"priority,omitempty"`
+}
+
+// IsDeny reports whether the rule blocks the traffic it matches
+func (r NetOut) IsDeny() bool {
+	return r.Action == NetOutActionDeny
+}
+
+// EffectivePriority returns the priority the rule is applied with
+func (r NetOut) EffectivePriority() uint16 {
+	if r.Priority != 0 {
+		return r.Priority
+	}
+
+	if r.IsDeny() {
+		return DefaultDenyPriority
+	}
+	return DefaultAllowPriority
+}
+
+// IsIPv6 reports whether the rule matches IPv6 destinations
+func (r NetOut) IsIPv6() bool {
+	for _, ipr := range r.Networks {
+		if ipr.Start != nil && ipr.Start.To4() == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// ICMPProtocol returns the IP protocol an ICMP rule is applied with, which
+// is ICMPv6 for rules matching IPv6 destinations
+func (r NetOut) ICMPProtocol() firewall.Protocol {
+	if r.IsIPv6() {
+		return firewall.NET_FW_IP_PROTOCOL_ICMPV6
+	}
+	return firewall.NET_FW_IP_PROTOCOL_ICMP
+}
+
+type NetOutAction string
+
+const (
+	NetOutActionAllow NetOutAction = "allow"
+	NetOutActionDeny  NetOutAction = "deny"
+)
+
+const (
+	// OperatorDenyPriority is the priority of the deny rules configured by
+	// the operator, which are evaluated before any container rule
+	OperatorDenyPriority uint16 = 100
+
+	DefaultDenyPriority  uint16 = 200
+	DefaultAllowPriority uint16 = 1000
+
+	// MaxPriority is the largest priority accepted by HNS ACL policies
+	MaxPriority uint16 = 65500
+)
+
+type ICMPType uint8
+type ICMPCode uint8
+
+type ICMPControl struct {
+	Type ICMPType `json:"type"`
+	// nil allows every code of the type
+	Code *ICMPCode `json:"code,omitempty"`
+}
+
+// String returns the control in the "type:code" form, e.g. "8:0" or "8:*"
+func (c ICMPControl) String() string {
+	if c.Code == nil {
+		return fmt.Sprintf("%d:*", c.Type)
+	}
+
+	return fmt.Sprintf("%d:%d", c.Type, *c.Code)
 }
 
 type Protocol uint8
@@ -36,6 +186,48 @@ const (
 	ProtocolICMP
 )
 
+func (p Protocol) String() string {
+	switch p {
+	case ProtocolAll:
+		return "all"
+	case ProtocolTCP:
+		return "tcp"
+	case ProtocolUDP:
+		return "udp"
+	case ProtocolICMP:
+		return "icmp"
+	default:
+		return strconv.Itoa(int(p))
+	}
+}
+
+// NatProtocol returns the protocol name used in HNS NAT policies
+func (p Protocol) NatProtocol() string {
+	switch p {
+	case ProtocolTCP:
+		return "TCP"
+	case ProtocolUDP:
+		return "UDP"
+	default:
+		return ""
+	}
+}
+
+// FirewallProtocol returns the protocol number used in HNS ACL policies and
+// windows firewall rules
+func (p Protocol) FirewallProtocol() firewall.Protocol {
+	switch p {
+	case ProtocolTCP:
+		return firewall.NET_FW_IP_PROTOCOL_TCP
+	case ProtocolUDP:
+		return firewall.NET_FW_IP_PROTOCOL_UDP
+	case ProtocolICMP:
+		return firewall.NET_FW_IP_PROTOCOL_ICMP
+	default:
+		return firewall.NET_FW_IP_PROTOCOL_ANY
+	}
+}
+
 type IPRange struct {
 	Start net.IP `json:"start,omitempty"`
 	End   net.IP `json:"end,omitempty"`
@@ -45,6 +237,25 @@ func (ir IPRange) String() string {
 	return fmt.Sprintf("%s-%s", ir.Start.String(), ir.End.String())
 }
 
+// Validate checks that both ends of the range are addresses of the same
+// family
+func (ir IPRange) Validate() error {
+	if ir.Start == nil || ir.End == nil {
+		return fmt.Errorf("invalid ip range: %s", ir)
+	}
+
+	start4, end4 := ir.Start.To4(), ir.End.To4()
+	if (start4 == nil) != (end4 == nil) {
+		return fmt.Errorf("invalid ip range %s: start and end must both be IPv4 or both be IPv6 addresses", ir)
+	}
+
+	if start4 == nil && (len(ir.Start) != net.IPv6len || len(ir.End) != net.IPv6len) {
+		return fmt.Errorf("invalid ip range: %s", ir)
+	}
+
+	return nil
+}
+
 type PortRange struct {
 	Start uint16 `json:"start,omitempty"`
 	End   uint16 `json:"end,omitempty"`
@@ -55,12 +266,15 @@ func (pr PortRange) String() string {
 }
 
 // FirewallRuleIPRange create a valid ip range for windows firewall
-func FirewallRuleIPRange(networks []IPRange) string {
+func FirewallRuleIPRange(networks []IPRange) (string, error) {
 	var output []string
 	for _, v := range networks {
+		if err := v.Validate(); err != nil {
+			return "", err
+		}
 		output = append(output, v.String())
 	}
-	return strings.Join(output, ",")
+	return strings.Join(output, ","), nil
 }
 
 // FirewallRulePortRange create a valid port range for windows firewall
@@ -72,55 +286,64 @@ func FirewallRulePortRange(ports []PortRange) string {
 	return strings.Join(output, ",")
 }
 
-func IPRangeToCIDRs(iprange IPRange) []string {
-	start := ipToUint(iprange.Start)
-	end := ipToUint(iprange.End)
+// IPRangeToCIDRs decomposes an IPv4 or IPv6 range into the smallest list of
+// CIDR blocks covering it
+func IPRangeToCIDRs(iprange IPRange) ([]string, error) {
+	if err := iprange.Validate(); err != nil {
+		return nil, err
+	}
+
+	startIP, endIP := iprange.Start.To4(), iprange.End.To4()
+	if startIP == nil {
+		startIP, endIP = iprange.Start, iprange.End
+	}
+
+	bits := 8 * len(startIP)
+	start := ipToInt(startIP)
+	end := ipToInt(endIP)
+	max := last(new(big.Int), bits, 0)
 	r := []string{}
 
-	for start <= end {
-		maskLen := uint32(32)
+	for start.Cmp(end) <= 0 {
+		maskLen := bits
 		for maskLen > 0 {
-			if start != first(start, maskLen-1) || end < last(start, maskLen-1) {
+			if start.Cmp(first(start, bits, maskLen-1)) != 0 || end.Cmp(last(start, bits, maskLen-1)) < 0 {
 				break
 			}
 			maskLen--
 		}
 
-		r = append(r, cidrFromIntMask(start, maskLen))
-		start = last(start, maskLen)
-		if start == 0xffffffff {
+		r = append(r, fmt.Sprintf("%s/%d", intToIP(start, bits).String(), maskLen))
+		start = last(start, bits, maskLen)
+		if start.Cmp(max) == 0 {
 			break
 		}
 
-		start++
+		start.Add(start, big.NewInt(1))
 	}
 
-	return r
+	return r, nil
 }
 
-func ipToUint(ip net.IP) uint32 {
-	ip = ip.To4()
-	return uint32(ip[0])<<24 + uint32(ip[1])<<16 + uint32(ip[2])<<8 + uint32(ip[3])
+func ipToInt(ip net.IP) *big.Int {
+	return new(big.Int).SetBytes(ip)
 }
 
-func cidrFromIntMask(start uint32, maskLen uint32) string {
-	ip := net.IPv4(
-		byte((start&0xff000000)>>24),
-		byte((start&0x00ff0000)>>16),
-		byte((start&0x0000ff00)>>8),
-		byte(start&0x000000ff),
-	)
-	return fmt.Sprintf("%s/%d", ip.String(), maskLen)
+func intToIP(i *big.Int, bits int) net.IP {
+	return net.IP(i.FillBytes(make([]byte, bits/8)))
 }
 
-func first(start uint32, maskLen uint32) uint32 {
-	return start & bitMask(maskLen)
+// first returns the first address of the block of length maskLen containing ip
+func first(ip *big.Int, bits, maskLen int) *big.Int {
+	return new(big.Int).AndNot(ip, hostMask(bits, maskLen))
 }
 
-func last(start uint32, maskLen uint32) uint32 {
-	return (start & bitMask(maskLen)) | (^bitMask(maskLen))
+// last returns the last address of the block of length maskLen containing ip
+func last(ip *big.Int, bits, maskLen int) *big.Int {
+	return new(big.Int).Or(first(ip, bits, maskLen), hostMask(bits, maskLen))
 }
 
-func bitMask(len uint32) uint32 {
-	return 0xffffffff << (32 - len)
+func hostMask(bits, maskLen int) *big.Int {
+	mask := new(big.Int).Lsh(big.NewInt(1), uint(bits-maskLen))
+	return mask.Sub(mask, big.NewInt(1))
 }
@@ -2,8 +2,10 @@ package network
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -16,10 +18,12 @@ import (
 
 //go:generate counterfeiter -o fakes/net_rule_applier.go --fake-name NetRuleApplier . NetRuleApplier
 type NetRuleApplier interface {
-	In(netrules.NetIn, string) (*hcsshim.NatPolicy, *hcsshim.ACLPolicy, error)
+	In(netrules.NetIn, string) ([]*hcsshim.NatPolicy, []*hcsshim.ACLPolicy, error)
+	Expose(netrules.NetIn, string) ([]*hcsshim.ACLPolicy, error)
 	Out(netrules.NetOut, string) (*hcsshim.ACLPolicy, error)
 	Cleanup() error
 	OpenPort(port uint32) error
+	ReleasePorts([]int) error
 }
 
 //go:generate counterfeiter -o fakes/mtu.go --fake-name Mtu . Mtu
@@ -30,9 +34,21 @@ type Mtu interface {
 
 //go:generate counterfeiter -o fakes/endpoint_manager.go --fake-name EndpointManager . EndpointManager
 type EndpointManager interface {
-	Create() (hcsshim.HNSEndpoint, error)
+	Create(requestedIP net.IP, dns DNSSettings) (hcsshim.HNSEndpoint, error)
+	Get() (hcsshim.HNSEndpoint, error)
 	Delete() error
 	ApplyPolicies(hcsshim.HNSEndpoint, []*hcsshim.NatPolicy, []*hcsshim.ACLPolicy) (hcsshim.HNSEndpoint, error)
+	SetMaximumOutgoingBandwidth(hcsshim.HNSEndpoint, uint64) (hcsshim.HNSEndpoint, error)
+}
+
+//go:generate counterfeiter -o fakes/auditor.go --fake-name Auditor . Auditor
+type Auditor interface {
+	NetOut(string, netrules.NetOut) error
+}
+
+//go:generate counterfeiter -o fakes/host_ip_lister.go --fake-name HostIPLister . HostIPLister
+type HostIPLister interface {
+	HostIPs(exclude []string) ([]string, error)
 }
 
 //go:generate counterfeiter -o fakes/hcs_client.go --fake-name HCSClient . HCSClient
@@ -42,9 +58,34 @@ type HCSClient interface {
 	DeleteNetwork(*hcsshim.HNSNetwork) (*hcsshim.HNSNetwork, error)
 }
 
+const (
+	NetworkTypeNAT         = "nat"
+	NetworkTypeTransparent = "transparent"
+	NetworkTypeL2Bridge    = "l2bridge"
+
+	IPAssignmentStatic = "static"
+	IPAssignmentDHCP   = "dhcp"
+)
+
 type Config struct {
-	MTU                           int      `json:"mtu"`
-	NetworkName                   string   `json:"network_name"`
+	MTU         int    `json:"mtu"`
+	NetworkName string `json:"network_name"`
+
+	// nat, transparent or l2bridge; default nat. Containers on transparent
+	// and l2bridge networks get addresses on the host's L2 segment
+	NetworkType string `json:"network_type"`
+
+	// the host adapter transparent and l2bridge networks are bound to
+	NetworkAdapterName string `json:"network_adapter_name"`
+
+	// the VLAN transparent and l2bridge networks are tagged with; default none
+	VLANID uint `json:"vlan_id"`
+
+	// static assigns container addresses from the subnet range, dhcp leaves
+	// them to the segment's DHCP server; dhcp is only valid for transparent
+	// networks; default static
+	IPAssignment string `json:"ip_assignment"`
+
 	SubnetRange                   string   `json:"subnet_range"`
 	GatewayAddress                string   `json:"gateway_address"`
 	DNSServers                    []string `json:"dns_servers"`
@@ -52,6 +93,25 @@ type Config struct {
 	DNSSuffix                     []string `json:"search_domains"`
 	AllowOutboundTrafficByDefault bool     `json:"allow_outbound_traffic_by_default"`
 	WaitTimeoutInSeconds          int      `json:"wait_timeout_in_seconds"`
+	PortRangeStart                int      `json:"port_range_start"`
+	PortRangeSize                 int      `json:"port_range_size"`
+	PortStateFile                 string   `json:"port_state_file"`
+	PortReuseQuarantineInSeconds  int      `json:"port_reuse_quarantine_in_seconds"`
+	AuditLogFile                  string   `json:"audit_log_file"`
+
+	// further networks containers may select in their up inputs
+	Networks []NetworkDefinition `json:"networks"`
+
+	// outbound traffic allowed for every container in addition to its own rules
+	NetOutDefaultRules []netrules.NetOut `json:"netout_default_rules"`
+
+	// outbound traffic denied to every container, whatever its own rules allow
+	NetOutDenyRules []netrules.NetOut `json:"netout_deny_rules"`
+}
+
+// IsNAT reports whether containers are given addresses behind the host's NAT
+func (c Config) IsNAT() bool {
+	return c.NetworkType == "" || c.NetworkType == NetworkTypeNAT
 }
 
 type UpInputs struct {
@@ -59,6 +119,121 @@ type UpInputs struct {
 	Properties map[string]interface{}
 	NetOut     []netrules.NetOut `json:"netout_rules"`
 	NetIn      []netrules.NetIn  `json:"netin"`
+
+	// the configured network to attach the container to; default the network
+	// at the top level of the config
+	Network string `json:"network,omitempty"`
+
+	// the address the container should be given; default any free address
+	// in the network's subnet
+	IP string `json:"ip,omitempty"`
+
+	// name servers and search domains for the container, used instead of
+	// the configured dns_servers and search_domains
+	DNSServers    []string `json:"dns_servers,omitempty"`
+	SearchDomains []string `json:"search_domains,omitempty"`
+	// HNS endpoints have no resolver options, so any given are rejected
+	DNSOptions []string `json:"dns_options,omitempty"`
+}
+
+// DNSSettings are the name servers and search domains set on a container's
+// endpoint
+type DNSSettings struct {
+	Servers       []string
+	SearchDomains []string
+}
+
+// DNS returns the container's DNS settings
+func (u *UpInputs) DNS() DNSSettings {
+	return DNSSettings{
+		Servers:       u.DNSServers,
+		SearchDomains: u.SearchDomains,
+	}
+}
+
+// EndpointDNSServers returns the name servers set on the endpoint of a
+// container with dns: its own servers if it has any, or else the configured
+// ones
+func (c Config) EndpointDNSServers(dns DNSSettings) []string {
+	if len(dns.Servers) > 0 {
+		return dns.Servers
+	}
+	return c.DNSServers
+}
+
+// BandwidthLimits matches the bandwidth limits of a Garden container spec.
+// HNS QoS policies only limit the outgoing rate, so no other limit may be
+// set.
+type BandwidthLimits struct {
+	RateInBytesPerSecond      uint64 `json:"rate,omitempty"`
+	BurstRateInBytesPerSecond uint64 `json:"burst,omitempty"`
+}
+
+// ParseBandwidthLimits decodes limits from their JSON encoding, rejecting
+// limits HNS cannot apply
+func ParseBandwidthLimits(content []byte) (BandwidthLimits, error) {
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(content, &fields); err != nil {
+		return BandwidthLimits{}, err
+	}
+
+	for name := range fields {
+		if name != "rate" && name != "burst" {
+			return BandwidthLimits{}, fmt.Errorf("unsupported limit %s: only the outgoing rate can be limited", name)
+		}
+	}
+
+	limits := BandwidthLimits{}
+	if err := json.Unmarshal(content, &limits); err != nil {
+		return BandwidthLimits{}, err
+	}
+
+	return limits, nil
+}
+
+// Validate checks that the limits can be applied to an endpoint on a network
+// whose containers are limited to maximum bytes per second. A zero maximum
+// means there is no limit.
+func (l BandwidthLimits) Validate(maximum uint64) error {
+	if l.BurstRateInBytesPerSecond != 0 {
+		return errors.New("burst rates are not supported by HNS QoS policies")
+	}
+
+	if maximum != 0 && l.RateInBytesPerSecond > maximum {
+		return &BandwidthLimitError{Rate: l.RateInBytesPerSecond, Maximum: maximum}
+	}
+
+	return nil
+}
+
+// BandwidthLimits returns the limits in the bandwidth_limits property, or
+// nil if there are none. Like every Garden property the limits may be given
+// as a JSON encoded string.
+func (u *UpInputs) BandwidthLimits() (*BandwidthLimits, error) {
+	property, ok := u.Properties["bandwidth_limits"]
+	if !ok {
+		return nil, nil
+	}
+
+	var content []byte
+	if encoded, ok := property.(string); ok {
+		content = []byte(encoded)
+	} else {
+		// the property has been decoded as a generic map along with the
+		// inputs
+		var err error
+		content, err = json.Marshal(property)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	limits, err := ParseBandwidthLimits(content)
+	if err != nil {
+		return nil, err
+	}
+
+	return &limits, nil
 }
 
 func (u *UpInputs) IsEmpty() bool {
@@ -69,11 +244,30 @@ func (u *UpInputs) IsEmpty() bool {
 
 type UpOutputs struct {
 	Properties struct {
-		ContainerIP      string `json:"garden.network.container-ip"`
+		ContainerIP      string `json:"garden.network.container-ip,omitempty"`
 		DeprecatedHostIP string `json:"garden.network.host-ip"`
 		MappedPorts      string `json:"garden.network.mapped-ports"`
 	} `json:"properties"`
-	DNSServers []string `json:"dns_servers,omitempty"`
+	DNSServers  []string     `json:"dns_servers,omitempty"`
+	MappedPorts []MappedPort `json:"mapped_ports"`
+}
+
+// MappedPort describes a host port mapped to the container, along with the
+// host addresses it can be reached on
+type MappedPort struct {
+	HostIPs       []string `json:"host_ips"`
+	HostPort      uint32   `json:"host_port"`
+	ContainerPort uint32   `json:"container_port"`
+	Protocol      string   `json:"protocol"`
+}
+
+// unknownHostIP is reported as the host IP when the host's addresses cannot
+// be determined
+const unknownHostIP = "255.255.255.255"
+
+type NetInOutputs struct {
+	HostPort      uint32 `json:"host_port"`
+	ContainerPort uint32 `json:"container_port"`
 }
 
 type NetworkManager struct {
@@ -83,9 +277,11 @@ type NetworkManager struct {
 	containerId     string
 	config          Config
 	mtu             Mtu
+	auditor         Auditor
+	hostIPLister    HostIPLister
 }
 
-func NewNetworkManager(client HCSClient, applier NetRuleApplier, endpointManager EndpointManager, containerId string, config Config, mtu Mtu) *NetworkManager {
+func NewNetworkManager(client HCSClient, applier NetRuleApplier, endpointManager EndpointManager, containerId string, config Config, mtu Mtu, auditor Auditor, hostIPLister HostIPLister) *NetworkManager {
 	return &NetworkManager{
 		hcsClient:       client,
 		applier:         applier,
@@ -93,6 +289,8 @@ func NewNetworkManager(client HCSClient, applier NetRuleApplier, endpointManager
 		containerId:     containerId,
 		config:          config,
 		mtu:             mtu,
+		auditor:         auditor,
+		hostIPLister:    hostIPLister,
 	}
 }
 
@@ -104,43 +302,122 @@ func (n *NetworkManager) CreateHostNATNetwork() error {
 		}
 	}
 
-	subnets := []hcsshim.Subnet{{AddressPrefix: n.config.SubnetRange, GatewayAddress: n.config.GatewayAddress}}
+	network, err := n.hnsNetwork()
+	if err != nil {
+		return err
+	}
 
 	if existingNetwork != nil {
-		if len(existingNetwork.Subnets) == 1 && subnetsMatch(existingNetwork.Subnets[0], subnets[0]) {
+		if networksMatch(existingNetwork, network) {
 			return nil
 		}
 
-		return &SameNATNetworkNameError{Name: n.config.NetworkName, Subnets: existingNetwork.Subnets}
+		if network.Type == NetworkTypeNAT {
+			return &SameNATNetworkNameError{Name: n.config.NetworkName, Subnets: existingNetwork.Subnets}
+		}
+		return &SameNetworkNameError{Name: n.config.NetworkName, Type: existingNetwork.Type, Subnets: existingNetwork.Subnets}
+	}
+
+	// only NAT networks get a host interface of their own
+	networkReady := func() (bool, error) {
+		return true, nil
+	}
+	if n.config.IsNAT() {
+		networkReady = func() (bool, error) {
+			return netinterface.InterfaceExists(interfaceAlias(network.Name))
+		}
+	}
+
+	_, err = n.hcsClient.CreateNetwork(network, networkReady)
+	if err != nil {
+		return err
+	}
+
+	if !n.config.IsNAT() {
+		return nil
 	}
 
+	return n.mtu.SetNat(n.config.MTU)
+}
+
+// hnsNetwork returns the definition of the configured network
+func (n *NetworkManager) hnsNetwork() (*hcsshim.HNSNetwork, error) {
 	for _, suffix := range n.config.DNSSuffix {
 		// DNSSuffix passed to hcsshim is invalid if it contains a comma or a space
 		if strings.ContainsAny(suffix, ", ") {
-			return fmt.Errorf("Invalid DNSSuffix. First invalid DNSSuffix: %s", suffix)
+			return nil, fmt.Errorf("Invalid DNSSuffix. First invalid DNSSuffix: %s", suffix)
 		}
 	}
-	// This must be a comma separated value with no spaces
-	dnsSuffix := strings.Join(n.config.DNSSuffix, ",")
 
 	network := &hcsshim.HNSNetwork{
-		Name:      n.config.NetworkName,
-		Type:      "nat",
-		Subnets:   subnets,
-		DNSSuffix: dnsSuffix,
+		Name:    n.config.NetworkName,
+		Type:    NetworkTypeNAT,
+		Subnets: []hcsshim.Subnet{{AddressPrefix: n.config.SubnetRange, GatewayAddress: n.config.GatewayAddress}},
+		// This must be a comma separated value with no spaces
+		DNSSuffix: strings.Join(n.config.DNSSuffix, ","),
 	}
 
-	networkReady := func() (bool, error) {
-		interfaceAlias := fmt.Sprintf("vEthernet (%s)", network.Name)
-		return netinterface.InterfaceExists(interfaceAlias)
+	if n.config.IsNAT() {
+		return network, nil
 	}
 
-	_, err = n.hcsClient.CreateNetwork(network, networkReady)
-	if err != nil {
-		return err
+	network.Type = n.config.NetworkType
+	network.NetworkAdapterName = n.config.NetworkAdapterName
+
+	// addresses are handed out by the segment's DHCP server
+	if n.config.IPAssignment == IPAssignmentDHCP {
+		network.Subnets = nil
 	}
 
-	return n.mtu.SetNat(n.config.MTU)
+	if n.config.VLANID != 0 {
+		policy, err := json.Marshal(hcsshim.VlanPolicy{Type: hcsshim.VLAN, VLAN: n.config.VLANID})
+		if err != nil {
+			return nil, err
+		}
+		network.Policies = []json.RawMessage{policy}
+	}
+
+	return network, nil
+}
+
+// networksMatch reports whether an existing network has the definition the
+// network would be created with
+func networksMatch(existing, desired *hcsshim.HNSNetwork) bool {
+	if !strings.EqualFold(existing.Type, desired.Type) {
+		return false
+	}
+
+	// HNS may fill in the adapter when none was asked for
+	if desired.NetworkAdapterName != "" && !strings.EqualFold(existing.NetworkAdapterName, desired.NetworkAdapterName) {
+		return false
+	}
+
+	if len(existing.Subnets) != len(desired.Subnets) {
+		return false
+	}
+	for i := range desired.Subnets {
+		if !subnetsMatch(existing.Subnets[i], desired.Subnets[i]) {
+			return false
+		}
+	}
+
+	return existing.DNSSuffix == desired.DNSSuffix &&
+		vlanID(existing.Policies) == vlanID(desired.Policies)
+}
+
+func vlanID(policies []json.RawMessage) uint {
+	for _, policy := range policies {
+		vlan := hcsshim.VlanPolicy{}
+		if err := json.Unmarshal(policy, &vlan); err == nil && vlan.Type == hcsshim.VLAN {
+			return vlan.VLAN
+		}
+	}
+	return 0
+}
+
+// interfaceAlias returns the name of the host's interface to a network
+func interfaceAlias(networkName string) string {
+	return fmt.Sprintf("vEthernet (%s)", networkName)
 }
 
 func subnetsMatch(a, b hcsshim.Subnet) bool {
@@ -171,6 +448,10 @@ func (n *NetworkManager) Up(inputs UpInputs) (UpOutputs, error) {
 		inputs.NetOut = []netrules.NetOut{{Protocol: netrules.ProtocolAll}}
 	}
 
+	if err := inputs.Validate(n.config.MaximumOutgoingBandwidth); err != nil {
+		return UpOutputs{}, err
+	}
+
 	outputs, err := n.up(inputs)
 	if err != nil {
 		n.applier.Cleanup()
@@ -183,28 +464,45 @@ func (n *NetworkManager) Up(inputs UpInputs) (UpOutputs, error) {
 func (n *NetworkManager) up(inputs UpInputs) (UpOutputs, error) {
 	outputs := UpOutputs{}
 
-	createdEndpoint, err := n.endpointManager.Create()
+	createdEndpoint, err := n.endpointManager.Create(net.ParseIP(inputs.IP), inputs.DNS())
 	if err != nil {
 		return outputs, err
 	}
 	logrus.Debugf("created endpoint %s", createdEndpoint.Name)
+	containerIP := endpointIP(createdEndpoint)
+
+	// an endpoint adopted from an earlier attempt keeps the host ports it
+	// was given, and has its rules replaced by the requested ones
+	previousPorts := hostPorts(createdEndpoint.Policies)
+	createdEndpoint.Policies = withoutRulePolicies(createdEndpoint.Policies)
 
 	hnsAcls := []*hcsshim.ACLPolicy{}
 	hnsNats := []*hcsshim.NatPolicy{}
 
 	for _, rule := range inputs.NetIn {
-		nat, acl, err := n.applier.In(rule, createdEndpoint.IPAddress.String())
-		if err != nil {
-			return outputs, err
+		if rule.HostPort == 0 {
+			if protocols, err := rule.Protocol.Protocols(); err == nil {
+				rule.HostPort = uint32(previousPorts[natKey(protocols[0].NatProtocol(), rule.ContainerPort)])
+			}
 		}
 
-		if nat != nil {
-			hnsNats = append(hnsNats, nat)
+		if !n.config.IsNAT() {
+			acls, err := n.expose(rule, containerIP)
+			if err != nil {
+				return outputs, err
+			}
+
+			hnsAcls = append(hnsAcls, acls...)
+			continue
 		}
 
-		if acl != nil {
-			hnsAcls = append(hnsAcls, acl)
+		nats, acls, err := n.applier.In(rule, containerIP)
+		if err != nil {
+			return outputs, err
 		}
+
+		hnsNats = append(hnsNats, nats...)
+		hnsAcls = append(hnsAcls, acls...)
 	}
 
 	// This is required for running .NET applications
@@ -236,7 +534,7 @@ func (n *NetworkManager) up(inputs UpInputs) (UpOutputs, error) {
 		logrus.Debugf("input.Properties doesn't contain ports - .Net apps aren't supported")
 	}
 
-	for _, dnsServer := range n.config.DNSServers {
+	for _, dnsServer := range n.config.EndpointDNSServers(inputs.DNS()) {
 		serverIP := net.ParseIP(dnsServer)
 		inputs.NetOut = append(inputs.NetOut,
 			netrules.NetOut{
@@ -252,8 +550,18 @@ func (n *NetworkManager) up(inputs UpInputs) (UpOutputs, error) {
 		)
 	}
 
+	inputs.NetOut = append(inputs.NetOut, n.config.NetOutDefaultRules...)
+
+	for _, rule := range n.config.NetOutDenyRules {
+		rule.Action = netrules.NetOutActionDeny
+		if rule.Priority == 0 {
+			rule.Priority = netrules.OperatorDenyPriority
+		}
+		inputs.NetOut = append(inputs.NetOut, rule)
+	}
+
 	for _, rule := range inputs.NetOut {
-		acl, err := n.applier.Out(rule, createdEndpoint.IPAddress.String())
+		acl, err := n.applier.Out(rule, containerIP)
 		if err != nil {
 			return outputs, err
 		}
@@ -263,35 +571,378 @@ func (n *NetworkManager) up(inputs UpInputs) (UpOutputs, error) {
 		}
 	}
 
+	limits, err := inputs.BandwidthLimits()
+	if err != nil {
+		return outputs, err
+	}
+
+	// an adopted endpoint may still have the limit asked for by the earlier
+	// attempt
+	if limits == nil && outgoingBandwidth(createdEndpoint.Policies) != n.config.MaximumOutgoingBandwidth {
+		limits = &BandwidthLimits{}
+	}
+
+	if limits != nil {
+		createdEndpoint, err = n.setBandwidth(createdEndpoint, *limits)
+		if err != nil {
+			return outputs, err
+		}
+	}
+
 	if _, err := n.endpointManager.ApplyPolicies(createdEndpoint, hnsNats, hnsAcls); err != nil {
 		return outputs, err
 	}
 	logrus.Debugf("applied network mappings %s", createdEndpoint.Name)
 
+	if stale := stalePorts(previousPorts, hnsNats); len(stale) > 0 {
+		if err := n.applier.ReleasePorts(stale); err != nil {
+			return outputs, err
+		}
+		logrus.Debugf("released host ports %v no longer mapped to %s", stale, createdEndpoint.Name)
+	}
+
+	for _, rule := range inputs.NetOut {
+		if err := n.audit(containerIP, rule); err != nil {
+			return outputs, err
+		}
+	}
+
 	if err := n.mtu.SetContainer(n.config.MTU); err != nil {
 		return outputs, err
 	}
 	logrus.Debugf("applied container MTU %d", n.config.MTU)
 
-	mappedPorts := []netrules.PortMapping{}
+	mappings := []netrules.PortMapping{}
 	for _, nat := range hnsNats {
-		mappedPorts = append(mappedPorts, netrules.PortMapping{
+		mappings = append(mappings, netrules.PortMapping{
 			ContainerPort: uint32(nat.InternalPort),
 			HostPort:      uint32(nat.ExternalPort),
+			Protocol:      strings.ToLower(nat.Protocol),
+		})
+	}
+
+	// containers on routable networks are reached on their own address and
+	// ports
+	hostIPs := []string{}
+	if containerIP != "" {
+		hostIPs = []string{containerIP}
+	}
+	if n.config.IsNAT() {
+		hostIPs = n.hostIPs()
+	} else {
+		mappings = exposedPorts(inputs.NetIn)
+	}
+
+	outputs.MappedPorts = []MappedPort{}
+	for _, mapping := range mappings {
+		outputs.MappedPorts = append(outputs.MappedPorts, MappedPort{
+			HostIPs:       hostIPs,
+			HostPort:      mapping.HostPort,
+			ContainerPort: mapping.ContainerPort,
+			Protocol:      mapping.Protocol,
 		})
 	}
-	portBytes, err := json.Marshal(mappedPorts)
+
+	portBytes, err := json.Marshal(mappings)
 	if err != nil {
 		return outputs, err
 	}
 
 	outputs.Properties.MappedPorts = string(portBytes)
-	outputs.Properties.ContainerIP = createdEndpoint.IPAddress.String()
-	outputs.Properties.DeprecatedHostIP = "255.255.255.255"
+	outputs.Properties.ContainerIP = containerIP
+	outputs.Properties.DeprecatedHostIP = unknownHostIP
+	if len(hostIPs) > 0 {
+		outputs.Properties.DeprecatedHostIP = hostIPs[0]
+	}
 
 	return outputs, nil
 }
 
+// expose allows inbound traffic for rule to a container on a routable
+// network, where host ports cannot be mapped to the container
+func (n *NetworkManager) expose(rule netrules.NetIn, containerIP string) ([]*hcsshim.ACLPolicy, error) {
+	if rule.HostPort != 0 && rule.HostPort != rule.ContainerPort {
+		return nil, fmt.Errorf("host port %d cannot be mapped to container port %d on %s networks", rule.HostPort, rule.ContainerPort, n.config.NetworkType)
+	}
+
+	return n.applier.Expose(rule, containerIP)
+}
+
+// exposedPorts returns the container ports exposed by rules on a routable
+// network, which are reached on the same port numbers
+func exposedPorts(rules []netrules.NetIn) []netrules.PortMapping {
+	mappings := []netrules.PortMapping{}
+	for _, rule := range rules {
+		protocols, _ := rule.Protocol.Protocols()
+		count, _ := rule.PortCount()
+
+		for _, protocol := range protocols {
+			for i := 0; i < count; i++ {
+				port := rule.ContainerPort + uint32(i)
+				mappings = append(mappings, netrules.PortMapping{
+					ContainerPort: port,
+					HostPort:      port,
+					Protocol:      strings.ToLower(protocol.NatProtocol()),
+				})
+			}
+		}
+	}
+	return mappings
+}
+
+// hostIPs returns the addresses mapped ports can be reached on, leaving out
+// the host's interfaces to the NAT networks. Failing to list them does not
+// stop the container's network from being set up.
+func (n *NetworkManager) hostIPs() []string {
+	natInterfaces := []string{}
+	for _, config := range n.config.AllNetworks() {
+		if config.IsNAT() {
+			natInterfaces = append(natInterfaces, interfaceAlias(config.NetworkName))
+		}
+	}
+
+	ips, err := n.hostIPLister.HostIPs(natInterfaces)
+	if err != nil {
+		logrus.Warnf("failed to list host ips: %s", err.Error())
+		return []string{}
+	}
+
+	if ips == nil {
+		return []string{}
+	}
+	return ips
+}
+
+// NetIn maps a host port to a running container by adding the policies for
+// rule to its existing endpoint. Host ports allocated for the rule are
+// released again if the endpoint cannot be updated.
+func (n *NetworkManager) NetIn(rule netrules.NetIn) (NetInOutputs, error) {
+	outputs := NetInOutputs{}
+
+	if err := validationError(validateNetIn("netin", rule)); err != nil {
+		return outputs, err
+	}
+
+	endpoint, err := n.endpointManager.Get()
+	if err != nil {
+		return outputs, err
+	}
+
+	if !n.config.IsNAT() {
+		acls, err := n.expose(rule, endpointIP(endpoint))
+		if err != nil {
+			return outputs, err
+		}
+
+		if _, err := n.endpointManager.ApplyPolicies(endpoint, nil, acls); err != nil {
+			return outputs, err
+		}
+		logrus.Debugf("applied net in rule to endpoint %s", endpoint.Name)
+
+		return NetInOutputs{HostPort: rule.ContainerPort, ContainerPort: rule.ContainerPort}, nil
+	}
+
+	nats, acls, err := n.applier.In(rule, endpointIP(endpoint))
+	if err != nil {
+		return outputs, err
+	}
+
+	if _, err := n.endpointManager.ApplyPolicies(endpoint, nats, acls); err != nil {
+		// explicit host ports are reserved as well, but ports the endpoint
+		// already maps for other rules stay with them
+		if reserved := newPorts(nats, hostPorts(endpoint.Policies)); len(reserved) > 0 {
+			if releaseErr := n.applier.ReleasePorts(reserved); releaseErr != nil {
+				return outputs, fmt.Errorf("%s, %s", err.Error(), releaseErr.Error())
+			}
+		}
+		return outputs, err
+	}
+	logrus.Debugf("applied net in rule to endpoint %s", endpoint.Name)
+
+	outputs.HostPort = rule.HostPort
+	if len(nats) > 0 {
+		outputs.HostPort = uint32(nats[0].ExternalPort)
+	}
+	outputs.ContainerPort = rule.ContainerPort
+
+	return outputs, nil
+}
+
+// NetOut allows outbound traffic from a running container by adding the ACL
+// for rule to its existing endpoint
+func (n *NetworkManager) NetOut(rule netrules.NetOut) error {
+	if err := validationError(validateContainerNetOut("netout", rule)); err != nil {
+		return err
+	}
+
+	endpoint, err := n.endpointManager.Get()
+	if err != nil {
+		return err
+	}
+
+	acl, err := n.applier.Out(rule, endpointIP(endpoint))
+	if err != nil {
+		return err
+	}
+
+	// rules applied as firewall rules leave the endpoint unchanged
+	if acl != nil {
+		if _, err := n.endpointManager.ApplyPolicies(endpoint, nil, []*hcsshim.ACLPolicy{acl}); err != nil {
+			return err
+		}
+		logrus.Debugf("applied net out rule to endpoint %s", endpoint.Name)
+	}
+
+	return n.audit(endpointIP(endpoint), rule)
+}
+
+// audit records an applied rule in the audit log if the rule asks for it
+func (n *NetworkManager) audit(containerIP string, rule netrules.NetOut) error {
+	if !rule.Log {
+		return nil
+	}
+
+	if err := n.auditor.NetOut(containerIP, rule); err != nil {
+		return fmt.Errorf("audit net out rule: %s", err.Error())
+	}
+	return nil
+}
+
+// endpointIP returns the address of endpoint, or an empty string when HNS
+// does not know it, as with addresses leased from a DHCP server
+func endpointIP(endpoint hcsshim.HNSEndpoint) string {
+	if endpoint.IPAddress == nil {
+		return ""
+	}
+	return endpoint.IPAddress.String()
+}
+
+// hostPorts returns the host ports of the NAT policies in policies, by
+// protocol and container port
+func hostPorts(policies []json.RawMessage) map[string]uint16 {
+	ports := map[string]uint16{}
+	for _, policy := range policies {
+		nat := hcsshim.NatPolicy{}
+		if err := json.Unmarshal(policy, &nat); err != nil || nat.Type != hcsshim.Nat {
+			continue
+		}
+		ports[natKey(nat.Protocol, uint32(nat.InternalPort))] = nat.ExternalPort
+	}
+	return ports
+}
+
+// stalePorts returns the host ports mapped by an earlier attempt that are not
+// mapped by nats
+func stalePorts(previousPorts map[string]uint16, nats []*hcsshim.NatPolicy) []int {
+	mapped := map[int]bool{}
+	for _, port := range externalPorts(nats) {
+		mapped[port] = true
+	}
+
+	stale := []int{}
+	for _, port := range previousPorts {
+		if !mapped[int(port)] {
+			mapped[int(port)] = true
+			stale = append(stale, int(port))
+		}
+	}
+	sort.Ints(stale)
+	return stale
+}
+
+// newPorts returns the host ports mapped by nats that are not among
+// previousPorts
+func newPorts(nats []*hcsshim.NatPolicy, previousPorts map[string]uint16) []int {
+	previous := map[int]bool{}
+	for _, port := range previousPorts {
+		previous[int(port)] = true
+	}
+
+	ports := []int{}
+	for _, port := range externalPorts(nats) {
+		if !previous[port] {
+			ports = append(ports, port)
+		}
+	}
+	return ports
+}
+
+// outgoingBandwidth returns the limit of the QoS policy in policies, or zero
+// if there is none
+func outgoingBandwidth(policies []json.RawMessage) uint64 {
+	for _, policy := range policies {
+		qos := hcsshim.QosPolicy{}
+		if err := json.Unmarshal(policy, &qos); err == nil && qos.Type == hcsshim.QOS {
+			return qos.MaximumOutgoingBandwidthInBytes
+		}
+	}
+	return 0
+}
+
+func natKey(protocol string, containerPort uint32) string {
+	return fmt.Sprintf("%s/%d", strings.ToLower(protocol), containerPort)
+}
+
+// withoutRulePolicies drops the ACL and NAT policies created for net in and
+// net out rules
+func withoutRulePolicies(policies []json.RawMessage) []json.RawMessage {
+	var kept []json.RawMessage
+	for _, policy := range policies {
+		p := hcsshim.Policy{}
+		if err := json.Unmarshal(policy, &p); err == nil && (p.Type == hcsshim.ACL || p.Type == hcsshim.Nat) {
+			continue
+		}
+		kept = append(kept, policy)
+	}
+	return kept
+}
+
+// SetBandwidth changes the bandwidth limits of a running container. A zero
+// rate reverts the container to the configured maximum.
+func (n *NetworkManager) SetBandwidth(limits BandwidthLimits) error {
+	if err := limits.Validate(n.config.MaximumOutgoingBandwidth); err != nil {
+		return err
+	}
+
+	endpoint, err := n.endpointManager.Get()
+	if err != nil {
+		return err
+	}
+
+	_, err = n.setBandwidth(endpoint, limits)
+	return err
+}
+
+// setBandwidth limits the outgoing traffic of the endpoint to the validated
+// limits
+func (n *NetworkManager) setBandwidth(endpoint hcsshim.HNSEndpoint, limits BandwidthLimits) (hcsshim.HNSEndpoint, error) {
+	rate := limits.RateInBytesPerSecond
+	if rate == 0 {
+		rate = n.config.MaximumOutgoingBandwidth
+	}
+
+	updatedEndpoint, err := n.endpointManager.SetMaximumOutgoingBandwidth(endpoint, rate)
+	if err != nil {
+		return endpoint, err
+	}
+	logrus.Debugf("set maximum outgoing bandwidth of endpoint %s to %d", endpoint.Name, rate)
+
+	return updatedEndpoint, nil
+}
+
+func externalPorts(nats []*hcsshim.NatPolicy) []int {
+	seen := map[int]bool{}
+	ports := []int{}
+	for _, nat := range nats {
+		port := int(nat.ExternalPort)
+		if !seen[port] {
+			seen[port] = true
+			ports = append(ports, port)
+		}
+	}
+	return ports
+}
+
 func (n *NetworkManager) Down() error {
 	deleteErr := n.endpointManager.Delete()
 	cleanupErr := n.applier.Cleanup()
@@ -1,6 +1,7 @@
 package network_test
 
 import (
+	"encoding/json"
 	"errors"
 	"io/ioutil"
 	"net"
@@ -23,6 +24,8 @@ var _ = Describe("NetworkManager", func() {
 		hcsClient       *fakes.HCSClient
 		endpointManager *fakes.EndpointManager
 		mtu             *fakes.Mtu
+		auditor         *fakes.Auditor
+		hostIPLister    *fakes.HostIPLister
 		hnsNetwork      *hcsshim.HNSNetwork
 		config          network.Config
 	)
@@ -32,6 +35,8 @@ var _ = Describe("NetworkManager", func() {
 		netRuleApplier = &fakes.NetRuleApplier{}
 		endpointManager = &fakes.EndpointManager{}
 		mtu = &fakes.Mtu{}
+		auditor = &fakes.Auditor{}
+		hostIPLister = &fakes.HostIPLister{}
 		config = network.Config{
 			MTU:            1434,
 			SubnetRange:    "123.45.0.0/67",
@@ -39,7 +44,7 @@ var _ = Describe("NetworkManager", func() {
 			NetworkName:    "unit-test-name",
 		}
 
-		networkManager = network.NewNetworkManager(hcsClient, netRuleApplier, endpointManager, containerId, config, mtu)
+		networkManager = network.NewNetworkManager(hcsClient, netRuleApplier, endpointManager, containerId, config, mtu, auditor, hostIPLister)
 
 		logrus.SetOutput(ioutil.Discard)
 	})
@@ -68,7 +73,7 @@ var _ = Describe("NetworkManager", func() {
 		Context("DNSSuffix is provided", func() {
 			BeforeEach(func() {
 				config.DNSSuffix = []string{"example1-dns-suffix", "example2-dns-suffix"}
-				networkManager = network.NewNetworkManager(hcsClient, netRuleApplier, endpointManager, containerId, config, mtu)
+				networkManager = network.NewNetworkManager(hcsClient, netRuleApplier, endpointManager, containerId, config, mtu, auditor, hostIPLister)
 			})
 
 			It("creates the network with the correct DNSSuffix values", func() {
@@ -81,7 +86,7 @@ var _ = Describe("NetworkManager", func() {
 		Context("DNSSuffix value is invalid", func() {
 			BeforeEach(func() {
 				config.DNSSuffix = []string{"example1-dns-suffix", "example2,dns-suffix"}
-				networkManager = network.NewNetworkManager(hcsClient, netRuleApplier, endpointManager, containerId, config, mtu)
+				networkManager = network.NewNetworkManager(hcsClient, netRuleApplier, endpointManager, containerId, config, mtu, auditor, hostIPLister)
 			})
 
 			It("returns an error", func() {
@@ -95,6 +100,7 @@ var _ = Describe("NetworkManager", func() {
 			BeforeEach(func() {
 				hnsNetwork = &hcsshim.HNSNetwork{
 					Name:    "unit-test-name",
+					Type:    "NAT",
 					Subnets: []hcsshim.Subnet{{AddressPrefix: "123.45.0.0/67", GatewayAddress: "123.45.0.1"}},
 				}
 				hcsClient.GetHNSNetworkByNameReturns(hnsNetwork, nil)
@@ -139,6 +145,113 @@ var _ = Describe("NetworkManager", func() {
 			})
 		})
 
+		Context("the network already exists with different DNS suffixes", func() {
+			BeforeEach(func() {
+				hnsNetwork = &hcsshim.HNSNetwork{
+					Name:      "unit-test-name",
+					Type:      "nat",
+					Subnets:   []hcsshim.Subnet{{AddressPrefix: "123.45.0.0/67", GatewayAddress: "123.45.0.1"}},
+					DNSSuffix: "example.com",
+				}
+				hcsClient.GetHNSNetworkByNameReturns(hnsNetwork, nil)
+			})
+
+			It("returns an error", func() {
+				err := networkManager.CreateHostNATNetwork()
+				Expect(err).To(BeAssignableToTypeOf(&network.SameNATNetworkNameError{}))
+			})
+		})
+
+		Context("the network type is transparent", func() {
+			BeforeEach(func() {
+				config.NetworkType = network.NetworkTypeTransparent
+				config.NetworkAdapterName = "Ethernet 2"
+				config.VLANID = 12
+				networkManager = network.NewNetworkManager(hcsClient, netRuleApplier, endpointManager, containerId, config, mtu, auditor, hostIPLister)
+			})
+
+			It("creates a transparent network on the adapter and VLAN", func() {
+				Expect(networkManager.CreateHostNATNetwork()).To(Succeed())
+
+				Expect(hcsClient.CreateNetworkCallCount()).To(Equal(1))
+				net, networkReady := hcsClient.CreateNetworkArgsForCall(0)
+				Expect(net.Type).To(Equal("transparent"))
+				Expect(net.NetworkAdapterName).To(Equal("Ethernet 2"))
+				Expect(net.Subnets).To(ConsistOf(hcsshim.Subnet{AddressPrefix: "123.45.0.0/67", GatewayAddress: "123.45.0.1"}))
+				Expect(net.Policies).To(HaveLen(1))
+				Expect(string(net.Policies[0])).To(MatchJSON(`{"Type": "VLAN", "VLAN": 12}`))
+
+				Expect(networkReady()).To(BeTrue())
+				Expect(mtu.SetNatCallCount()).To(Equal(0))
+			})
+
+			Context("addresses are assigned by DHCP", func() {
+				BeforeEach(func() {
+					config.IPAssignment = network.IPAssignmentDHCP
+					networkManager = network.NewNetworkManager(hcsClient, netRuleApplier, endpointManager, containerId, config, mtu, auditor, hostIPLister)
+				})
+
+				It("creates the network without subnets", func() {
+					Expect(networkManager.CreateHostNATNetwork()).To(Succeed())
+
+					net, _ := hcsClient.CreateNetworkArgsForCall(0)
+					Expect(net.Subnets).To(BeEmpty())
+				})
+			})
+
+			Context("the network already exists with the same definition", func() {
+				BeforeEach(func() {
+					hnsNetwork = &hcsshim.HNSNetwork{
+						Name:               "unit-test-name",
+						Type:               "Transparent",
+						NetworkAdapterName: "Ethernet 2",
+						Subnets:            []hcsshim.Subnet{{AddressPrefix: "123.45.0.0/67", GatewayAddress: "123.45.0.1"}},
+						Policies:           []json.RawMessage{[]byte(`{"Type":"VLAN","VLAN":12}`)},
+					}
+					hcsClient.GetHNSNetworkByNameReturns(hnsNetwork, nil)
+				})
+
+				It("does not create the network", func() {
+					Expect(networkManager.CreateHostNATNetwork()).To(Succeed())
+					Expect(hcsClient.CreateNetworkCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("the network already exists on another VLAN", func() {
+				BeforeEach(func() {
+					hnsNetwork = &hcsshim.HNSNetwork{
+						Name:               "unit-test-name",
+						Type:               "transparent",
+						NetworkAdapterName: "Ethernet 2",
+						Subnets:            []hcsshim.Subnet{{AddressPrefix: "123.45.0.0/67", GatewayAddress: "123.45.0.1"}},
+						Policies:           []json.RawMessage{[]byte(`{"Type":"VLAN","VLAN":13}`)},
+					}
+					hcsClient.GetHNSNetworkByNameReturns(hnsNetwork, nil)
+				})
+
+				It("returns an error", func() {
+					err := networkManager.CreateHostNATNetwork()
+					Expect(err).To(MatchError("transparent network unit-test-name exists with subnets [{AddressPrefix:123.45.0.0/67 GatewayAddress:123.45.0.1 Policies:[]}]"))
+				})
+			})
+
+			Context("a NAT network already exists with the name", func() {
+				BeforeEach(func() {
+					hnsNetwork = &hcsshim.HNSNetwork{
+						Name:    "unit-test-name",
+						Type:    "nat",
+						Subnets: []hcsshim.Subnet{{AddressPrefix: "123.45.0.0/67", GatewayAddress: "123.45.0.1"}},
+					}
+					hcsClient.GetHNSNetworkByNameReturns(hnsNetwork, nil)
+				})
+
+				It("returns an error", func() {
+					err := networkManager.CreateHostNATNetwork()
+					Expect(err).To(BeAssignableToTypeOf(&network.SameNetworkNameError{}))
+				})
+			})
+		})
+
 		Context("GetHNSNetwork returns a non network not found error", func() {
 			BeforeEach(func() {
 				hcsClient.GetHNSNetworkByNameReturns(nil, errors.New("some HNS error"))
@@ -242,8 +355,8 @@ var _ = Describe("NetworkManager", func() {
 					{HostPort: 0, ContainerPort: 888},
 				},
 				NetOut: []netrules.NetOut{
-					{Protocol: 6},
-					{Protocol: 17},
+					{Protocol: netrules.ProtocolTCP},
+					{Protocol: netrules.ProtocolUDP},
 				},
 			}
 
@@ -289,13 +402,14 @@ var _ = Describe("NetworkManager", func() {
 				Protocol:  17,
 			}
 
-			netRuleApplier.InReturnsOnCall(0, nat1, inAcl1, nil)
-			netRuleApplier.InReturnsOnCall(1, nat2, inAcl2, nil)
+			netRuleApplier.InReturnsOnCall(0, []*hcsshim.NatPolicy{nat1}, []*hcsshim.ACLPolicy{inAcl1}, nil)
+			netRuleApplier.InReturnsOnCall(1, []*hcsshim.NatPolicy{nat2}, []*hcsshim.ACLPolicy{inAcl2}, nil)
 
 			netRuleApplier.OutReturnsOnCall(0, outAcl1, nil)
 			netRuleApplier.OutReturnsOnCall(1, outAcl2, nil)
 
 			endpointManager.CreateReturns(createdEndpoint, nil)
+			hostIPLister.HostIPsReturns([]string{"10.0.0.4", "fd00::4"}, nil)
 		})
 
 		It("creates an endpoint, applies ports, applies net out, handles mtu, and returns the up outputs", func() {
@@ -303,10 +417,20 @@ var _ = Describe("NetworkManager", func() {
 			Expect(err).NotTo(HaveOccurred())
 
 			Expect(output.Properties.ContainerIP).To(Equal(containerIP.String()))
-			Expect(output.Properties.DeprecatedHostIP).To(Equal("255.255.255.255"))
-			Expect(output.Properties.MappedPorts).To(Equal(`[{"HostPort":111,"ContainerPort":666},{"HostPort":222,"ContainerPort":888}]`))
+			Expect(output.Properties.DeprecatedHostIP).To(Equal("10.0.0.4"))
+			Expect(output.Properties.MappedPorts).To(Equal(`[{"HostPort":111,"ContainerPort":666,"Protocol":"tcp"},{"HostPort":222,"ContainerPort":888,"Protocol":"tcp"}]`))
+			Expect(output.MappedPorts).To(Equal([]network.MappedPort{
+				{HostIPs: []string{"10.0.0.4", "fd00::4"}, HostPort: 111, ContainerPort: 666, Protocol: "tcp"},
+				{HostIPs: []string{"10.0.0.4", "fd00::4"}, HostPort: 222, ContainerPort: 888, Protocol: "tcp"},
+			}))
+
+			Expect(hostIPLister.HostIPsCallCount()).To(Equal(1))
+			Expect(hostIPLister.HostIPsArgsForCall(0)).To(Equal([]string{"vEthernet (unit-test-name)"}))
 
 			Expect(endpointManager.CreateCallCount()).To(Equal(1))
+			requestedIP, dns := endpointManager.CreateArgsForCall(0)
+			Expect(requestedIP).To(BeNil())
+			Expect(dns).To(Equal(network.DNSSettings{}))
 
 			Expect(netRuleApplier.InCallCount()).To(Equal(2))
 			inRule, ip := netRuleApplier.InArgsForCall(0)
@@ -329,11 +453,11 @@ var _ = Describe("NetworkManager", func() {
 
 			Expect(netRuleApplier.OutCallCount()).To(Equal(2))
 			outRule, ip := netRuleApplier.OutArgsForCall(0)
-			Expect(outRule).To(Equal(netrules.NetOut{Protocol: 6}))
+			Expect(outRule).To(Equal(netrules.NetOut{Protocol: netrules.ProtocolTCP}))
 			Expect(ip).To(Equal(containerIP.String()))
 
 			outRule, ip = netRuleApplier.OutArgsForCall(1)
-			Expect(outRule).To(Equal(netrules.NetOut{Protocol: 17}))
+			Expect(outRule).To(Equal(netrules.NetOut{Protocol: netrules.ProtocolUDP}))
 			Expect(ip).To(Equal(containerIP.String()))
 
 			Expect(endpointManager.ApplyPoliciesCallCount()).To(Equal(1))
@@ -359,12 +483,290 @@ var _ = Describe("NetworkManager", func() {
 			Expect(receivedMtu).To(Equal(1434))
 		})
 
+		Context("an endpoint from an earlier attempt is adopted", func() {
+			BeforeEach(func() {
+				oldNat, err := json.Marshal(hcsshim.NatPolicy{Type: hcsshim.Nat, Protocol: "TCP", ExternalPort: 333, InternalPort: 888})
+				Expect(err).NotTo(HaveOccurred())
+				oldAcl, err := json.Marshal(hcsshim.ACLPolicy{Type: hcsshim.ACL, Action: hcsshim.Allow, Direction: hcsshim.Out})
+				Expect(err).NotTo(HaveOccurred())
+				qos, err := json.Marshal(hcsshim.QosPolicy{Type: hcsshim.QOS, MaximumOutgoingBandwidthInBytes: 1000})
+				Expect(err).NotTo(HaveOccurred())
+
+				config.MaximumOutgoingBandwidth = 1000
+				networkManager = network.NewNetworkManager(hcsClient, netRuleApplier, endpointManager, containerId, config, mtu, auditor, hostIPLister)
+
+				createdEndpoint.Policies = []json.RawMessage{oldNat, oldAcl, qos}
+				endpointManager.CreateReturns(createdEndpoint, nil)
+
+				nat2.ExternalPort = 333
+				netRuleApplier.InReturnsOnCall(1, []*hcsshim.NatPolicy{nat2}, []*hcsshim.ACLPolicy{inAcl2}, nil)
+			})
+
+			It("keeps the host ports mapped earlier and replaces the old rules", func() {
+				output, err := networkManager.Up(inputs)
+				Expect(err).NotTo(HaveOccurred())
+
+				rule, _ := netRuleApplier.InArgsForCall(0)
+				Expect(rule.HostPort).To(Equal(uint32(0)))
+				rule, _ = netRuleApplier.InArgsForCall(1)
+				Expect(rule.HostPort).To(Equal(uint32(333)))
+
+				ep, _, _ := endpointManager.ApplyPoliciesArgsForCall(0)
+				Expect(ep.Policies).To(HaveLen(1))
+				Expect(string(ep.Policies[0])).To(ContainSubstring("QOS"))
+
+				Expect(output.Properties.MappedPorts).To(Equal(`[{"HostPort":111,"ContainerPort":666,"Protocol":"tcp"},{"HostPort":333,"ContainerPort":888,"Protocol":"tcp"}]`))
+
+				Expect(endpointManager.SetMaximumOutgoingBandwidthCallCount()).To(Equal(0))
+				Expect(netRuleApplier.ReleasePortsCallCount()).To(Equal(0))
+			})
+
+			Context("it maps host ports that are no longer requested", func() {
+				BeforeEach(func() {
+					staleNat, err := json.Marshal(hcsshim.NatPolicy{Type: hcsshim.Nat, Protocol: "UDP", ExternalPort: 444, InternalPort: 999})
+					Expect(err).NotTo(HaveOccurred())
+
+					createdEndpoint.Policies = append(createdEndpoint.Policies, staleNat)
+					endpointManager.CreateReturns(createdEndpoint, nil)
+				})
+
+				It("releases them once the requested rules are applied", func() {
+					_, err := networkManager.Up(inputs)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(netRuleApplier.ReleasePortsCallCount()).To(Equal(1))
+					Expect(netRuleApplier.ReleasePortsArgsForCall(0)).To(Equal([]int{444}))
+				})
+
+				Context("releasing them fails", func() {
+					BeforeEach(func() {
+						netRuleApplier.ReleasePortsReturns(errors.New("cannot release"))
+					})
+
+					It("returns the error", func() {
+						_, err := networkManager.Up(inputs)
+						Expect(err).To(MatchError("cannot release"))
+					})
+				})
+			})
+
+			Context("it is limited differently than the configured maximum", func() {
+				var limitedEndpoint hcsshim.HNSEndpoint
+
+				BeforeEach(func() {
+					config.MaximumOutgoingBandwidth = 2000
+					networkManager = network.NewNetworkManager(hcsClient, netRuleApplier, endpointManager, containerId, config, mtu, auditor, hostIPLister)
+
+					limitedEndpoint = createdEndpoint
+					limitedEndpoint.Policies = []json.RawMessage{[]byte(`{"Type":"QOS","MaximumOutgoingBandwidthInBytes":2000}`)}
+					endpointManager.SetMaximumOutgoingBandwidthReturns(limitedEndpoint, nil)
+				})
+
+				It("resets the limit to the configured maximum", func() {
+					_, err := networkManager.Up(inputs)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(endpointManager.SetMaximumOutgoingBandwidthCallCount()).To(Equal(1))
+					_, rate := endpointManager.SetMaximumOutgoingBandwidthArgsForCall(0)
+					Expect(rate).To(Equal(uint64(2000)))
+
+					ep, _, _ := endpointManager.ApplyPoliciesArgsForCall(0)
+					Expect(ep).To(Equal(limitedEndpoint))
+				})
+			})
+		})
+
+		Context("bandwidth limits are given", func() {
+			var limitedEndpoint hcsshim.HNSEndpoint
+
+			BeforeEach(func() {
+				config.MaximumOutgoingBandwidth = 10000
+				networkManager = network.NewNetworkManager(hcsClient, netRuleApplier, endpointManager, containerId, config, mtu, auditor, hostIPLister)
+
+				inputs.Properties["bandwidth_limits"] = map[string]interface{}{"rate": float64(5000)}
+
+				limitedEndpoint = createdEndpoint
+				limitedEndpoint.Policies = []json.RawMessage{[]byte(`{"Type":"QOS","MaximumOutgoingBandwidthInBytes":5000}`)}
+				endpointManager.SetMaximumOutgoingBandwidthReturns(limitedEndpoint, nil)
+			})
+
+			It("limits the endpoint's outgoing bandwidth before applying the rules", func() {
+				_, err := networkManager.Up(inputs)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(endpointManager.SetMaximumOutgoingBandwidthCallCount()).To(Equal(1))
+				ep, rate := endpointManager.SetMaximumOutgoingBandwidthArgsForCall(0)
+				Expect(ep).To(Equal(createdEndpoint))
+				Expect(rate).To(Equal(uint64(5000)))
+
+				ep, _, _ = endpointManager.ApplyPoliciesArgsForCall(0)
+				Expect(ep).To(Equal(limitedEndpoint))
+			})
+
+			Context("the rate exceeds the configured maximum", func() {
+				BeforeEach(func() {
+					inputs.Properties["bandwidth_limits"] = map[string]interface{}{"rate": float64(20000)}
+				})
+
+				It("returns an error before creating the endpoint", func() {
+					_, err := networkManager.Up(inputs)
+					Expect(err).To(MatchError(&network.UpInputsValidationError{Errors: []network.RuleValidationError{
+						{Rule: "bandwidth_limits", Message: "bandwidth rate 20000 exceeds the maximum of 10000 bytes per second"},
+					}}))
+					Expect(endpointManager.CreateCallCount()).To(Equal(0))
+				})
+			})
+		})
+
+		Context("an ip is requested", func() {
+			BeforeEach(func() {
+				inputs.IP = "123.45.0.9"
+			})
+
+			It("creates the endpoint with the ip", func() {
+				_, err := networkManager.Up(inputs)
+				Expect(err).NotTo(HaveOccurred())
+				requestedIP, _ := endpointManager.CreateArgsForCall(0)
+				Expect(requestedIP).To(Equal(net.ParseIP("123.45.0.9")))
+			})
+		})
+
+		Context("further networks are configured", func() {
+			BeforeEach(func() {
+				config.Networks = []network.NetworkDefinition{
+					{NetworkName: "other-nat", SubnetRange: "10.1.0.0/24", GatewayAddress: "10.1.0.1"},
+					{NetworkName: "routable", NetworkType: network.NetworkTypeL2Bridge},
+				}
+				networkManager = network.NewNetworkManager(hcsClient, netRuleApplier, endpointManager, containerId, config, mtu, auditor, hostIPLister)
+			})
+
+			It("leaves out the host's interfaces to every NAT network", func() {
+				_, err := networkManager.Up(inputs)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(hostIPLister.HostIPsArgsForCall(0)).To(Equal([]string{"vEthernet (unit-test-name)", "vEthernet (other-nat)"}))
+			})
+		})
+
+		Context("the network is transparent", func() {
+			BeforeEach(func() {
+				config.NetworkType = network.NetworkTypeTransparent
+				networkManager = network.NewNetworkManager(hcsClient, netRuleApplier, endpointManager, containerId, config, mtu, auditor, hostIPLister)
+
+				inputs.NetIn = []netrules.NetIn{{ContainerPort: 666, Protocol: netrules.NetInProtocolBoth}}
+				netRuleApplier.ExposeReturns([]*hcsshim.ACLPolicy{inAcl1}, nil)
+			})
+
+			It("allows the container ports without NAT policies", func() {
+				output, err := networkManager.Up(inputs)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(netRuleApplier.InCallCount()).To(Equal(0))
+				Expect(netRuleApplier.ExposeCallCount()).To(Equal(1))
+				exposedRule, ip := netRuleApplier.ExposeArgsForCall(0)
+				Expect(exposedRule).To(Equal(inputs.NetIn[0]))
+				Expect(ip).To(Equal(containerIP.String()))
+
+				_, nats, acls := endpointManager.ApplyPoliciesArgsForCall(0)
+				Expect(nats).To(BeEmpty())
+				Expect(acls).To(Equal([]*hcsshim.ACLPolicy{inAcl1, outAcl1, outAcl2}))
+
+				Expect(output.Properties.DeprecatedHostIP).To(Equal(containerIP.String()))
+				Expect(output.Properties.MappedPorts).To(Equal(`[{"HostPort":666,"ContainerPort":666,"Protocol":"tcp"},{"HostPort":666,"ContainerPort":666,"Protocol":"udp"}]`))
+				Expect(output.MappedPorts).To(ConsistOf(
+					network.MappedPort{HostIPs: []string{containerIP.String()}, HostPort: 666, ContainerPort: 666, Protocol: "tcp"},
+					network.MappedPort{HostIPs: []string{containerIP.String()}, HostPort: 666, ContainerPort: 666, Protocol: "udp"},
+				))
+				Expect(hostIPLister.HostIPsCallCount()).To(Equal(0))
+			})
+
+			Context("a rule maps a different host port", func() {
+				BeforeEach(func() {
+					inputs.NetIn = []netrules.NetIn{{HostPort: 8080, ContainerPort: 666}}
+				})
+
+				It("returns an error", func() {
+					_, err := networkManager.Up(inputs)
+					Expect(err).To(MatchError("host port 8080 cannot be mapped to container port 666 on transparent networks"))
+					Expect(endpointManager.DeleteCallCount()).To(Equal(1))
+				})
+			})
+
+			Context("addresses are leased from a DHCP server", func() {
+				BeforeEach(func() {
+					config.IPAssignment = network.IPAssignmentDHCP
+					networkManager = network.NewNetworkManager(hcsClient, netRuleApplier, endpointManager, containerId, config, mtu, auditor, hostIPLister)
+
+					endpointManager.CreateReturns(hcsshim.HNSEndpoint{}, nil)
+				})
+
+				It("applies the rules without a container address and does not report one", func() {
+					output, err := networkManager.Up(inputs)
+					Expect(err).NotTo(HaveOccurred())
+
+					_, ip := netRuleApplier.ExposeArgsForCall(0)
+					Expect(ip).To(BeEmpty())
+					_, ip = netRuleApplier.OutArgsForCall(0)
+					Expect(ip).To(BeEmpty())
+
+					Expect(output.Properties.ContainerIP).To(BeEmpty())
+					Expect(output.Properties.DeprecatedHostIP).To(Equal("255.255.255.255"))
+					Expect(output.MappedPorts).To(ConsistOf(
+						network.MappedPort{HostIPs: []string{}, HostPort: 666, ContainerPort: 666, Protocol: "tcp"},
+						network.MappedPort{HostIPs: []string{}, HostPort: 666, ContainerPort: 666, Protocol: "udp"},
+					))
+
+					outputJSON, err := json.Marshal(output)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(string(outputJSON)).NotTo(ContainSubstring("container-ip"))
+					Expect(string(outputJSON)).NotTo(ContainSubstring("<nil>"))
+				})
+			})
+		})
+
+		Context("the host IPs cannot be listed", func() {
+			BeforeEach(func() {
+				hostIPLister.HostIPsReturns(nil, errors.New("no interfaces"))
+			})
+
+			It("still sets up the network without reporting a host IP", func() {
+				output, err := networkManager.Up(inputs)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(output.Properties.DeprecatedHostIP).To(Equal("255.255.255.255"))
+				Expect(output.MappedPorts).To(HaveLen(2))
+				Expect(output.MappedPorts[0].HostIPs).To(BeEmpty())
+			})
+		})
+
+		Context("when a net in rule maps both tcp and udp", func() {
+			BeforeEach(func() {
+				inputs.NetIn = []netrules.NetIn{{HostPort: 0, ContainerPort: 666, Protocol: netrules.NetInProtocolBoth}}
+				udpNat := &hcsshim.NatPolicy{
+					Type:         hcsshim.Nat,
+					Protocol:     "UDP",
+					ExternalPort: 111,
+					InternalPort: 666,
+				}
+				netRuleApplier.InReturnsOnCall(0, []*hcsshim.NatPolicy{nat1, udpNat}, []*hcsshim.ACLPolicy{inAcl1}, nil)
+			})
+
+			It("reports a mapped port for each protocol", func() {
+				output, err := networkManager.Up(inputs)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(output.Properties.MappedPorts).To(Equal(`[{"HostPort":111,"ContainerPort":666,"Protocol":"tcp"},{"HostPort":111,"ContainerPort":666,"Protocol":"udp"}]`))
+
+				_, nats, _ := endpointManager.ApplyPoliciesArgsForCall(0)
+				Expect(nats).To(HaveLen(2))
+			})
+		})
+
 		Context("when the config specifies DNS servers", func() {
 			BeforeEach(func() {
 				config := network.Config{
 					DNSServers: []string{"1.1.1.1", "2.2.2.2"},
 				}
-				networkManager = network.NewNetworkManager(hcsClient, netRuleApplier, endpointManager, containerId, config, mtu)
+				networkManager = network.NewNetworkManager(hcsClient, netRuleApplier, endpointManager, containerId, config, mtu, auditor, hostIPLister)
 				inputs.NetOut = []netrules.NetOut{}
 			})
 
@@ -410,16 +812,159 @@ var _ = Describe("NetworkManager", func() {
 			})
 		})
 
+		Context("the container has its own DNS configuration", func() {
+			BeforeEach(func() {
+				config.DNSServers = []string{"1.1.1.1"}
+				networkManager = network.NewNetworkManager(hcsClient, netRuleApplier, endpointManager, containerId, config, mtu, auditor, hostIPLister)
+
+				inputs.NetOut = []netrules.NetOut{}
+				inputs.DNSServers = []string{"9.9.9.9"}
+				inputs.SearchDomains = []string{"apps.internal", "example.com"}
+			})
+
+			It("creates the endpoint with the container's servers and search domains", func() {
+				_, err := networkManager.Up(inputs)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, dns := endpointManager.CreateArgsForCall(0)
+				Expect(dns).To(Equal(network.DNSSettings{
+					Servers:       []string{"9.9.9.9"},
+					SearchDomains: []string{"apps.internal", "example.com"},
+				}))
+			})
+
+			It("creates netout rules for the container's servers instead of the configured ones", func() {
+				_, err := networkManager.Up(inputs)
+				Expect(err).NotTo(HaveOccurred())
+
+				dnsServer := net.ParseIP("9.9.9.9")
+				Expect(netRuleApplier.OutCallCount()).To(Equal(2))
+
+				outRule, _ := netRuleApplier.OutArgsForCall(0)
+				Expect(outRule).To(Equal(netrules.NetOut{
+					Protocol: netrules.ProtocolTCP,
+					Networks: []netrules.IPRange{{Start: dnsServer, End: dnsServer}},
+					Ports:    []netrules.PortRange{{Start: 53, End: 53}},
+				}))
+
+				outRule, _ = netRuleApplier.OutArgsForCall(1)
+				Expect(outRule).To(Equal(netrules.NetOut{
+					Protocol: netrules.ProtocolUDP,
+					Networks: []netrules.IPRange{{Start: dnsServer, End: dnsServer}},
+					Ports:    []netrules.PortRange{{Start: 53, End: 53}},
+				}))
+			})
+		})
+
+		Context("dns options are given", func() {
+			BeforeEach(func() {
+				inputs.DNSOptions = []string{"ndots:2"}
+			})
+
+			It("returns an error before creating the endpoint", func() {
+				_, err := networkManager.Up(inputs)
+				Expect(err).To(MatchError(&network.UpInputsValidationError{Errors: []network.RuleValidationError{
+					{Rule: "dns_options", Message: "dns options are not supported by HNS"},
+				}}))
+				Expect(endpointManager.CreateCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the config specifies default rules", func() {
+			var ntp netrules.NetOut
+
+			BeforeEach(func() {
+				ntp = netrules.NetOut{
+					Protocol: netrules.ProtocolUDP,
+					Networks: []netrules.IPRange{{Start: net.ParseIP("10.0.0.5"), End: net.ParseIP("10.0.0.5")}},
+					Ports:    []netrules.PortRange{{Start: 123, End: 123}},
+					Log:      true,
+				}
+				config.DNSServers = []string{"8.8.8.8"}
+				config.NetOutDefaultRules = []netrules.NetOut{ntp}
+				networkManager = network.NewNetworkManager(hcsClient, netRuleApplier, endpointManager, containerId, config, mtu, auditor, hostIPLister)
+			})
+
+			It("applies them after the container's rules and the DNS rules", func() {
+				_, err := networkManager.Up(inputs)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(netRuleApplier.OutCallCount()).To(Equal(5))
+				rule, _ := netRuleApplier.OutArgsForCall(4)
+				Expect(rule).To(Equal(ntp))
+
+				Expect(auditor.NetOutCallCount()).To(Equal(1))
+				_, auditedRule := auditor.NetOutArgsForCall(0)
+				Expect(auditedRule).To(Equal(ntp))
+			})
+
+			It("applies them even if the container has no rules of its own", func() {
+				_, err := networkManager.Up(network.UpInputs{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(netRuleApplier.OutCallCount()).To(Equal(3))
+				rule, _ := netRuleApplier.OutArgsForCall(2)
+				Expect(rule).To(Equal(ntp))
+			})
+		})
+
+		Context("when the config specifies deny rules", func() {
+			BeforeEach(func() {
+				config.NetOutDenyRules = []netrules.NetOut{
+					{Networks: []netrules.IPRange{{Start: net.ParseIP("169.254.169.254"), End: net.ParseIP("169.254.169.254")}}},
+					{Protocol: netrules.ProtocolTCP, Priority: 10},
+				}
+				networkManager = network.NewNetworkManager(hcsClient, netRuleApplier, endpointManager, containerId, config, mtu, auditor, hostIPLister)
+			})
+
+			It("applies them as operator deny rules after the container's rules", func() {
+				_, err := networkManager.Up(inputs)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(netRuleApplier.OutCallCount()).To(Equal(4))
+
+				rule, _ := netRuleApplier.OutArgsForCall(2)
+				Expect(rule).To(Equal(netrules.NetOut{
+					Networks: []netrules.IPRange{{Start: net.ParseIP("169.254.169.254"), End: net.ParseIP("169.254.169.254")}},
+					Action:   netrules.NetOutActionDeny,
+					Priority: netrules.OperatorDenyPriority,
+				}))
+
+				rule, _ = netRuleApplier.OutArgsForCall(3)
+				Expect(rule.Action).To(Equal(netrules.NetOutActionDeny))
+				Expect(rule.Priority).To(Equal(uint16(10)))
+			})
+		})
+
+		Context("the inputs are invalid", func() {
+			BeforeEach(func() {
+				inputs.NetIn[1].ContainerPort = 0
+				inputs.NetOut[0].Ports = []netrules.PortRange{{Start: 90, End: 80}}
+			})
+
+			It("returns all of the problems before creating the endpoint", func() {
+				_, err := networkManager.Up(inputs)
+				Expect(err).To(MatchError(&network.UpInputsValidationError{Errors: []network.RuleValidationError{
+					{Rule: "netin[1]", Message: "container port must not be zero"},
+					{Rule: "netout_rules[0]", Message: "ports[0]: start 90 is greater than end 80"},
+				}}))
+
+				Expect(endpointManager.CreateCallCount()).To(Equal(0))
+				Expect(netRuleApplier.InCallCount()).To(Equal(0))
+				Expect(netRuleApplier.CleanupCallCount()).To(Equal(0))
+			})
+		})
+
 		Context("when 'default_allow_outbound_traffic' flag is set AND inputs are not empty", func() {
 			BeforeEach(func() {
 				config := network.Config{AllowOutboundTrafficByDefault: true}
-				networkManager = network.NewNetworkManager(hcsClient, netRuleApplier, endpointManager, containerId, config, mtu)
+				networkManager = network.NewNetworkManager(hcsClient, netRuleApplier, endpointManager, containerId, config, mtu, auditor, hostIPLister)
 				inputs = network.UpInputs{
 					Pid:        1234,
 					Properties: map[string]interface{}{},
 					NetOut: []netrules.NetOut{
-						{Protocol: 6},
-						{Protocol: 17},
+						{Protocol: netrules.ProtocolTCP},
+						{Protocol: netrules.ProtocolUDP},
 					}}
 			})
 
@@ -429,11 +974,11 @@ var _ = Describe("NetworkManager", func() {
 
 				Expect(netRuleApplier.OutCallCount()).To(Equal(2))
 				outRule, ip := netRuleApplier.OutArgsForCall(0)
-				Expect(outRule).To(Equal(netrules.NetOut{Protocol: 6}))
+				Expect(outRule).To(Equal(netrules.NetOut{Protocol: netrules.ProtocolTCP}))
 				Expect(ip).To(Equal(containerIP.String()))
 
 				outRule, ip = netRuleApplier.OutArgsForCall(1)
-				Expect(outRule).To(Equal(netrules.NetOut{Protocol: 17}))
+				Expect(outRule).To(Equal(netrules.NetOut{Protocol: netrules.ProtocolUDP}))
 				Expect(ip).To(Equal(containerIP.String()))
 			})
 		})
@@ -441,7 +986,7 @@ var _ = Describe("NetworkManager", func() {
 		Context("when 'default_allow_outbound_traffic' flag not set AND inputs are empty", func() {
 			BeforeEach(func() {
 				config := network.Config{}
-				networkManager = network.NewNetworkManager(hcsClient, netRuleApplier, endpointManager, containerId, config, mtu)
+				networkManager = network.NewNetworkManager(hcsClient, netRuleApplier, endpointManager, containerId, config, mtu, auditor, hostIPLister)
 				inputs = network.UpInputs{Pid: 1234, Properties: map[string]interface{}{}}
 			})
 
@@ -456,7 +1001,7 @@ var _ = Describe("NetworkManager", func() {
 		Context("when 'default_allow_outbound_traffic' flag is set AND inputs are empty", func() {
 			BeforeEach(func() {
 				config := network.Config{AllowOutboundTrafficByDefault: true}
-				networkManager = network.NewNetworkManager(hcsClient, netRuleApplier, endpointManager, containerId, config, mtu)
+				networkManager = network.NewNetworkManager(hcsClient, netRuleApplier, endpointManager, containerId, config, mtu, auditor, hostIPLister)
 				inputs = network.UpInputs{Pid: 1234, Properties: map[string]interface{}{}}
 			})
 
@@ -555,6 +1100,35 @@ var _ = Describe("NetworkManager", func() {
 			})
 		})
 
+		Context("net out rules ask to be logged", func() {
+			BeforeEach(func() {
+				inputs.NetOut[1].Log = true
+			})
+
+			It("audits only those rules once they have been applied", func() {
+				_, err := networkManager.Up(inputs)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(auditor.NetOutCallCount()).To(Equal(1))
+				ip, rule := auditor.NetOutArgsForCall(0)
+				Expect(ip).To(Equal(containerIP.String()))
+				Expect(rule).To(Equal(inputs.NetOut[1]))
+			})
+
+			Context("auditing fails", func() {
+				BeforeEach(func() {
+					auditor.NetOutReturns(errors.New("disk full"))
+				})
+
+				It("cleans up and returns an error", func() {
+					_, err := networkManager.Up(inputs)
+					Expect(err).To(MatchError("audit net out rule: disk full"))
+					Expect(netRuleApplier.CleanupCallCount()).To(Equal(1))
+					Expect(endpointManager.DeleteCallCount()).To(Equal(1))
+				})
+			})
+		})
+
 		Context("MTU fails", func() {
 			BeforeEach(func() {
 				mtu.SetContainerReturns(errors.New("couldn't set MTU"))
@@ -569,6 +1143,276 @@ var _ = Describe("NetworkManager", func() {
 		})
 	})
 
+	Describe("NetIn", func() {
+		var (
+			endpoint hcsshim.HNSEndpoint
+			rule     netrules.NetIn
+			nat      *hcsshim.NatPolicy
+			acl      *hcsshim.ACLPolicy
+		)
+
+		BeforeEach(func() {
+			endpoint = hcsshim.HNSEndpoint{Name: containerId, IPAddress: net.ParseIP("5.4.3.2")}
+			endpointManager.GetReturns(endpoint, nil)
+
+			rule = netrules.NetIn{ContainerPort: 8080}
+			nat = &hcsshim.NatPolicy{Type: hcsshim.Nat, Protocol: "TCP", InternalPort: 8080, ExternalPort: 40001}
+			acl = &hcsshim.ACLPolicy{Type: hcsshim.ACL, Direction: hcsshim.In, LocalPorts: "8080"}
+			netRuleApplier.InReturns([]*hcsshim.NatPolicy{nat}, []*hcsshim.ACLPolicy{acl}, nil)
+		})
+
+		It("adds the policies for the rule to the container's endpoint", func() {
+			outputs, err := networkManager.NetIn(rule)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(outputs).To(Equal(network.NetInOutputs{HostPort: 40001, ContainerPort: 8080}))
+
+			Expect(netRuleApplier.InCallCount()).To(Equal(1))
+			appliedRule, containerIP := netRuleApplier.InArgsForCall(0)
+			Expect(appliedRule).To(Equal(rule))
+			Expect(containerIP).To(Equal("5.4.3.2"))
+
+			Expect(endpointManager.ApplyPoliciesCallCount()).To(Equal(1))
+			ep, nats, acls := endpointManager.ApplyPoliciesArgsForCall(0)
+			Expect(ep).To(Equal(endpoint))
+			Expect(nats).To(Equal([]*hcsshim.NatPolicy{nat}))
+			Expect(acls).To(Equal([]*hcsshim.ACLPolicy{acl}))
+
+			Expect(endpointManager.CreateCallCount()).To(Equal(0))
+		})
+
+		Context("the network is l2bridge", func() {
+			BeforeEach(func() {
+				config.NetworkType = network.NetworkTypeL2Bridge
+				networkManager = network.NewNetworkManager(hcsClient, netRuleApplier, endpointManager, containerId, config, mtu, auditor, hostIPLister)
+				netRuleApplier.ExposeReturns([]*hcsshim.ACLPolicy{acl}, nil)
+			})
+
+			It("allows the container port without a NAT policy", func() {
+				outputs, err := networkManager.NetIn(rule)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(outputs).To(Equal(network.NetInOutputs{HostPort: 8080, ContainerPort: 8080}))
+
+				Expect(netRuleApplier.InCallCount()).To(Equal(0))
+				_, nats, acls := endpointManager.ApplyPoliciesArgsForCall(0)
+				Expect(nats).To(BeEmpty())
+				Expect(acls).To(Equal([]*hcsshim.ACLPolicy{acl}))
+			})
+		})
+
+		Context("the rule is invalid", func() {
+			BeforeEach(func() {
+				rule.ContainerPort = 0
+			})
+
+			It("returns a validation error without touching the endpoint", func() {
+				_, err := networkManager.NetIn(rule)
+				Expect(err).To(MatchError("network rules are invalid:\n\tnetin: container port must not be zero"))
+				Expect(endpointManager.GetCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("the endpoint cannot be found", func() {
+			BeforeEach(func() {
+				endpointManager.GetReturns(hcsshim.HNSEndpoint{}, errors.New("no endpoint"))
+			})
+
+			It("returns the error without allocating ports", func() {
+				_, err := networkManager.NetIn(rule)
+				Expect(err).To(MatchError("no endpoint"))
+				Expect(netRuleApplier.InCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("updating the endpoint fails", func() {
+			BeforeEach(func() {
+				endpointManager.ApplyPoliciesReturns(hcsshim.HNSEndpoint{}, errors.New("cannot update endpoint"))
+			})
+
+			It("releases the allocated host ports", func() {
+				_, err := networkManager.NetIn(rule)
+				Expect(err).To(MatchError("cannot update endpoint"))
+
+				Expect(netRuleApplier.ReleasePortsCallCount()).To(Equal(1))
+				Expect(netRuleApplier.ReleasePortsArgsForCall(0)).To(Equal([]int{40001}))
+				Expect(netRuleApplier.CleanupCallCount()).To(Equal(0))
+			})
+
+			Context("the host port was given", func() {
+				BeforeEach(func() {
+					rule.HostPort = 40001
+				})
+
+				It("releases its reservation", func() {
+					_, err := networkManager.NetIn(rule)
+					Expect(err).To(MatchError("cannot update endpoint"))
+
+					Expect(netRuleApplier.ReleasePortsCallCount()).To(Equal(1))
+					Expect(netRuleApplier.ReleasePortsArgsForCall(0)).To(Equal([]int{40001}))
+				})
+
+				Context("the endpoint already maps it for another rule", func() {
+					BeforeEach(func() {
+						udpNat, err := json.Marshal(hcsshim.NatPolicy{Type: hcsshim.Nat, Protocol: "UDP", InternalPort: 8080, ExternalPort: 40001})
+						Expect(err).NotTo(HaveOccurred())
+						endpoint.Policies = []json.RawMessage{udpNat}
+						endpointManager.GetReturns(endpoint, nil)
+					})
+
+					It("keeps it reserved for that rule", func() {
+						_, err := networkManager.NetIn(rule)
+						Expect(err).To(MatchError("cannot update endpoint"))
+						Expect(netRuleApplier.ReleasePortsCallCount()).To(Equal(0))
+					})
+				})
+			})
+
+			Context("releasing the ports fails too", func() {
+				BeforeEach(func() {
+					netRuleApplier.ReleasePortsReturns(errors.New("cannot release ports"))
+				})
+
+				It("returns both errors", func() {
+					_, err := networkManager.NetIn(rule)
+					Expect(err).To(MatchError("cannot update endpoint, cannot release ports"))
+				})
+			})
+		})
+	})
+
+	Describe("NetOut", func() {
+		var (
+			endpoint hcsshim.HNSEndpoint
+			rule     netrules.NetOut
+			acl      *hcsshim.ACLPolicy
+		)
+
+		BeforeEach(func() {
+			endpoint = hcsshim.HNSEndpoint{Name: containerId, IPAddress: net.ParseIP("5.4.3.2")}
+			endpointManager.GetReturns(endpoint, nil)
+
+			rule = netrules.NetOut{Protocol: netrules.ProtocolTCP}
+			acl = &hcsshim.ACLPolicy{Type: hcsshim.ACL, Direction: hcsshim.Out}
+			netRuleApplier.OutReturns(acl, nil)
+		})
+
+		It("adds the ACL for the rule to the container's endpoint", func() {
+			Expect(networkManager.NetOut(rule)).To(Succeed())
+
+			appliedRule, containerIP := netRuleApplier.OutArgsForCall(0)
+			Expect(appliedRule).To(Equal(rule))
+			Expect(containerIP).To(Equal("5.4.3.2"))
+
+			Expect(endpointManager.ApplyPoliciesCallCount()).To(Equal(1))
+			ep, nats, acls := endpointManager.ApplyPoliciesArgsForCall(0)
+			Expect(ep).To(Equal(endpoint))
+			Expect(nats).To(BeEmpty())
+			Expect(acls).To(Equal([]*hcsshim.ACLPolicy{acl}))
+		})
+
+		Context("the applier creates no ACL", func() {
+			BeforeEach(func() {
+				netRuleApplier.OutReturns(nil, nil)
+			})
+
+			It("leaves the endpoint unchanged", func() {
+				Expect(networkManager.NetOut(rule)).To(Succeed())
+				Expect(endpointManager.ApplyPoliciesCallCount()).To(Equal(0))
+			})
+		})
+
+		It("does not audit the rule", func() {
+			Expect(networkManager.NetOut(rule)).To(Succeed())
+			Expect(auditor.NetOutCallCount()).To(Equal(0))
+		})
+
+		Context("the rule asks to be logged", func() {
+			BeforeEach(func() {
+				rule.Log = true
+			})
+
+			It("audits the rule after applying it", func() {
+				Expect(networkManager.NetOut(rule)).To(Succeed())
+
+				Expect(auditor.NetOutCallCount()).To(Equal(1))
+				ip, auditedRule := auditor.NetOutArgsForCall(0)
+				Expect(ip).To(Equal("5.4.3.2"))
+				Expect(auditedRule).To(Equal(rule))
+			})
+		})
+
+		Context("the rule fails validation", func() {
+			BeforeEach(func() {
+				rule = netrules.NetOut{Protocol: netrules.ProtocolICMP, Ports: []netrules.PortRange{{Start: 80, End: 80}}}
+			})
+
+			It("returns a validation error without touching the endpoint", func() {
+				Expect(networkManager.NetOut(rule)).To(MatchError("network rules are invalid:\n\tnetout: ports must not be specified for icmp rules"))
+				Expect(endpointManager.GetCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("the applier rejects the rule", func() {
+			BeforeEach(func() {
+				netRuleApplier.OutReturns(nil, errors.New("invalid protocol"))
+			})
+
+			It("returns the error", func() {
+				Expect(networkManager.NetOut(rule)).To(MatchError("invalid protocol"))
+				Expect(endpointManager.ApplyPoliciesCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Describe("SetBandwidth", func() {
+		var endpoint hcsshim.HNSEndpoint
+
+		BeforeEach(func() {
+			endpoint = hcsshim.HNSEndpoint{Name: containerId, IPAddress: net.ParseIP("5.4.3.2")}
+			endpointManager.GetReturns(endpoint, nil)
+
+			config.MaximumOutgoingBandwidth = 10000
+			networkManager = network.NewNetworkManager(hcsClient, netRuleApplier, endpointManager, containerId, config, mtu, auditor, hostIPLister)
+		})
+
+		It("updates the running container's endpoint", func() {
+			Expect(networkManager.SetBandwidth(network.BandwidthLimits{RateInBytesPerSecond: 2000})).To(Succeed())
+
+			Expect(endpointManager.SetMaximumOutgoingBandwidthCallCount()).To(Equal(1))
+			ep, rate := endpointManager.SetMaximumOutgoingBandwidthArgsForCall(0)
+			Expect(ep).To(Equal(endpoint))
+			Expect(rate).To(Equal(uint64(2000)))
+		})
+
+		It("reverts to the configured maximum for a zero rate", func() {
+			Expect(networkManager.SetBandwidth(network.BandwidthLimits{})).To(Succeed())
+
+			_, rate := endpointManager.SetMaximumOutgoingBandwidthArgsForCall(0)
+			Expect(rate).To(Equal(uint64(10000)))
+		})
+
+		It("rejects rates above the configured maximum", func() {
+			err := networkManager.SetBandwidth(network.BandwidthLimits{RateInBytesPerSecond: 10001})
+			Expect(err).To(MatchError("bandwidth rate 10001 exceeds the maximum of 10000 bytes per second"))
+			Expect(endpointManager.SetMaximumOutgoingBandwidthCallCount()).To(Equal(0))
+		})
+
+		It("rejects burst rates", func() {
+			err := networkManager.SetBandwidth(network.BandwidthLimits{RateInBytesPerSecond: 2000, BurstRateInBytesPerSecond: 3000})
+			Expect(err).To(MatchError("burst rates are not supported by HNS QoS policies"))
+			Expect(endpointManager.SetMaximumOutgoingBandwidthCallCount()).To(Equal(0))
+		})
+
+		Context("updating the endpoint fails", func() {
+			BeforeEach(func() {
+				endpointManager.SetMaximumOutgoingBandwidthReturns(hcsshim.HNSEndpoint{}, errors.New("update failed"))
+			})
+
+			It("returns the error", func() {
+				Expect(networkManager.SetBandwidth(network.BandwidthLimits{RateInBytesPerSecond: 2000})).To(MatchError("update failed"))
+			})
+		})
+	})
+
 	Describe("Down", func() {
 		It("deletes the endpoint and cleans up the ports and firewall rules", func() {
 			Expect(networkManager.Down()).To(Succeed())
@@ -0,0 +1,30 @@
+package port_allocator
+
+import "fmt"
+
+type InvalidPortRangeError struct {
+	Start int
+	Size  int
+}
+
+func (e *InvalidPortRangeError) Error() string {
+	return fmt.Sprintf("invalid port range: start %d, size %d", e.Start, e.Size)
+}
+
+type DynamicPortRangeOverlapError struct {
+	Range   PortRange
+	Dynamic PortRange
+}
+
+func (e *DynamicPortRangeOverlapError) Error() string {
+	return fmt.Sprintf("port range %d-%d overlaps the dynamic port range %d-%d", e.Range.Start, e.Range.End(), e.Dynamic.Start, e.Dynamic.End())
+}
+
+type PortInUseError struct {
+	Port   int
+	Handle string
+}
+
+func (e *PortInUseError) Error() string {
+	return fmt.Sprintf("port %d is allocated to %s", e.Port, e.Handle)
+}
@@ -8,11 +8,11 @@ import (
 )
 
 type Tracker struct {
-	AcquireOneStub        func(pool *port_allocator.Pool, handle string) (int, error)
+	AcquireOneStub        func(*port_allocator.Pool, string) (int, error)
 	acquireOneMutex       sync.RWMutex
 	acquireOneArgsForCall []struct {
-		pool   *port_allocator.Pool
-		handle string
+		arg1 *port_allocator.Pool
+		arg2 string
 	}
 	acquireOneReturns struct {
 		result1 int
@@ -22,22 +22,25 @@ type Tracker struct {
 		result1 int
 		result2 error
 	}
-	ReleaseAllStub        func(pool *port_allocator.Pool, handle string) error
-	releaseAllMutex       sync.RWMutex
-	releaseAllArgsForCall []struct {
-		pool   *port_allocator.Pool
-		handle string
+	AcquireRangeStub        func(*port_allocator.Pool, string, int) (int, error)
+	acquireRangeMutex       sync.RWMutex
+	acquireRangeArgsForCall []struct {
+		arg1 *port_allocator.Pool
+		arg2 string
+		arg3 int
 	}
-	releaseAllReturns struct {
-		result1 error
+	acquireRangeReturns struct {
+		result1 int
+		result2 error
 	}
-	releaseAllReturnsOnCall map[int]struct {
-		result1 error
+	acquireRangeReturnsOnCall map[int]struct {
+		result1 int
+		result2 error
 	}
-	InRangeStub        func(port int) bool
+	InRangeStub        func(int) bool
 	inRangeMutex       sync.RWMutex
 	inRangeArgsForCall []struct {
-		port int
+		arg1 int
 	}
 	inRangeReturns struct {
 		result1 bool
@@ -45,26 +48,53 @@ type Tracker struct {
 	inRangeReturnsOnCall map[int]struct {
 		result1 bool
 	}
+	ReleaseStub        func(*port_allocator.Pool, string, []int) error
+	releaseMutex       sync.RWMutex
+	releaseArgsForCall []struct {
+		arg1 *port_allocator.Pool
+		arg2 string
+		arg3 []int
+	}
+	releaseReturns struct {
+		result1 error
+	}
+	releaseReturnsOnCall map[int]struct {
+		result1 error
+	}
+	ReleaseAllStub        func(*port_allocator.Pool, string) error
+	releaseAllMutex       sync.RWMutex
+	releaseAllArgsForCall []struct {
+		arg1 *port_allocator.Pool
+		arg2 string
+	}
+	releaseAllReturns struct {
+		result1 error
+	}
+	releaseAllReturnsOnCall map[int]struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *Tracker) AcquireOne(pool *port_allocator.Pool, handle string) (int, error) {
+func (fake *Tracker) AcquireOne(arg1 *port_allocator.Pool, arg2 string) (int, error) {
 	fake.acquireOneMutex.Lock()
 	ret, specificReturn := fake.acquireOneReturnsOnCall[len(fake.acquireOneArgsForCall)]
 	fake.acquireOneArgsForCall = append(fake.acquireOneArgsForCall, struct {
-		pool   *port_allocator.Pool
-		handle string
-	}{pool, handle})
-	fake.recordInvocation("AcquireOne", []interface{}{pool, handle})
+		arg1 *port_allocator.Pool
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.AcquireOneStub
+	fakeReturns := fake.acquireOneReturns
+	fake.recordInvocation("AcquireOne", []interface{}{arg1, arg2})
 	fake.acquireOneMutex.Unlock()
-	if fake.AcquireOneStub != nil {
-		return fake.AcquireOneStub(pool, handle)
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	return fake.acquireOneReturns.result1, fake.acquireOneReturns.result2
+	return fakeReturns.result1, fakeReturns.result2
 }
 
 func (fake *Tracker) AcquireOneCallCount() int {
@@ -73,13 +103,22 @@ func (fake *Tracker) AcquireOneCallCount() int {
 	return len(fake.acquireOneArgsForCall)
 }
 
+func (fake *Tracker) AcquireOneCalls(stub func(*port_allocator.Pool, string) (int, error)) {
+	fake.acquireOneMutex.Lock()
+	defer fake.acquireOneMutex.Unlock()
+	fake.AcquireOneStub = stub
+}
+
 func (fake *Tracker) AcquireOneArgsForCall(i int) (*port_allocator.Pool, string) {
 	fake.acquireOneMutex.RLock()
 	defer fake.acquireOneMutex.RUnlock()
-	return fake.acquireOneArgsForCall[i].pool, fake.acquireOneArgsForCall[i].handle
+	argsForCall := fake.acquireOneArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *Tracker) AcquireOneReturns(result1 int, result2 error) {
+	fake.acquireOneMutex.Lock()
+	defer fake.acquireOneMutex.Unlock()
 	fake.AcquireOneStub = nil
 	fake.acquireOneReturns = struct {
 		result1 int
@@ -88,6 +127,8 @@ func (fake *Tracker) AcquireOneReturns(result1 int, result2 error) {
 }
 
 func (fake *Tracker) AcquireOneReturnsOnCall(i int, result1 int, result2 error) {
+	fake.acquireOneMutex.Lock()
+	defer fake.acquireOneMutex.Unlock()
 	fake.AcquireOneStub = nil
 	if fake.acquireOneReturnsOnCall == nil {
 		fake.acquireOneReturnsOnCall = make(map[int]struct {
@@ -101,70 +142,89 @@ func (fake *Tracker) AcquireOneReturnsOnCall(i int, result1 int, result2 error)
 	}{result1, result2}
 }
 
-func (fake *Tracker) ReleaseAll(pool *port_allocator.Pool, handle string) error {
-	fake.releaseAllMutex.Lock()
-	ret, specificReturn := fake.releaseAllReturnsOnCall[len(fake.releaseAllArgsForCall)]
-	fake.releaseAllArgsForCall = append(fake.releaseAllArgsForCall, struct {
-		pool   *port_allocator.Pool
-		handle string
-	}{pool, handle})
-	fake.recordInvocation("ReleaseAll", []interface{}{pool, handle})
-	fake.releaseAllMutex.Unlock()
-	if fake.ReleaseAllStub != nil {
-		return fake.ReleaseAllStub(pool, handle)
+func (fake *Tracker) AcquireRange(arg1 *port_allocator.Pool, arg2 string, arg3 int) (int, error) {
+	fake.acquireRangeMutex.Lock()
+	ret, specificReturn := fake.acquireRangeReturnsOnCall[len(fake.acquireRangeArgsForCall)]
+	fake.acquireRangeArgsForCall = append(fake.acquireRangeArgsForCall, struct {
+		arg1 *port_allocator.Pool
+		arg2 string
+		arg3 int
+	}{arg1, arg2, arg3})
+	stub := fake.AcquireRangeStub
+	fakeReturns := fake.acquireRangeReturns
+	fake.recordInvocation("AcquireRange", []interface{}{arg1, arg2, arg3})
+	fake.acquireRangeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
-		return ret.result1
+		return ret.result1, ret.result2
 	}
-	return fake.releaseAllReturns.result1
+	return fakeReturns.result1, fakeReturns.result2
 }
 
-func (fake *Tracker) ReleaseAllCallCount() int {
-	fake.releaseAllMutex.RLock()
-	defer fake.releaseAllMutex.RUnlock()
-	return len(fake.releaseAllArgsForCall)
+func (fake *Tracker) AcquireRangeCallCount() int {
+	fake.acquireRangeMutex.RLock()
+	defer fake.acquireRangeMutex.RUnlock()
+	return len(fake.acquireRangeArgsForCall)
 }
 
-func (fake *Tracker) ReleaseAllArgsForCall(i int) (*port_allocator.Pool, string) {
-	fake.releaseAllMutex.RLock()
-	defer fake.releaseAllMutex.RUnlock()
-	return fake.releaseAllArgsForCall[i].pool, fake.releaseAllArgsForCall[i].handle
+func (fake *Tracker) AcquireRangeCalls(stub func(*port_allocator.Pool, string, int) (int, error)) {
+	fake.acquireRangeMutex.Lock()
+	defer fake.acquireRangeMutex.Unlock()
+	fake.AcquireRangeStub = stub
 }
 
-func (fake *Tracker) ReleaseAllReturns(result1 error) {
-	fake.ReleaseAllStub = nil
-	fake.releaseAllReturns = struct {
-		result1 error
-	}{result1}
+func (fake *Tracker) AcquireRangeArgsForCall(i int) (*port_allocator.Pool, string, int) {
+	fake.acquireRangeMutex.RLock()
+	defer fake.acquireRangeMutex.RUnlock()
+	argsForCall := fake.acquireRangeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
-func (fake *Tracker) ReleaseAllReturnsOnCall(i int, result1 error) {
-	fake.ReleaseAllStub = nil
-	if fake.releaseAllReturnsOnCall == nil {
-		fake.releaseAllReturnsOnCall = make(map[int]struct {
-			result1 error
+func (fake *Tracker) AcquireRangeReturns(result1 int, result2 error) {
+	fake.acquireRangeMutex.Lock()
+	defer fake.acquireRangeMutex.Unlock()
+	fake.AcquireRangeStub = nil
+	fake.acquireRangeReturns = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Tracker) AcquireRangeReturnsOnCall(i int, result1 int, result2 error) {
+	fake.acquireRangeMutex.Lock()
+	defer fake.acquireRangeMutex.Unlock()
+	fake.AcquireRangeStub = nil
+	if fake.acquireRangeReturnsOnCall == nil {
+		fake.acquireRangeReturnsOnCall = make(map[int]struct {
+			result1 int
+			result2 error
 		})
 	}
-	fake.releaseAllReturnsOnCall[i] = struct {
-		result1 error
-	}{result1}
+	fake.acquireRangeReturnsOnCall[i] = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
 }
 
-func (fake *Tracker) InRange(port int) bool {
+func (fake *Tracker) InRange(arg1 int) bool {
 	fake.inRangeMutex.Lock()
 	ret, specificReturn := fake.inRangeReturnsOnCall[len(fake.inRangeArgsForCall)]
 	fake.inRangeArgsForCall = append(fake.inRangeArgsForCall, struct {
-		port int
-	}{port})
-	fake.recordInvocation("InRange", []interface{}{port})
+		arg1 int
+	}{arg1})
+	stub := fake.InRangeStub
+	fakeReturns := fake.inRangeReturns
+	fake.recordInvocation("InRange", []interface{}{arg1})
 	fake.inRangeMutex.Unlock()
-	if fake.InRangeStub != nil {
-		return fake.InRangeStub(port)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	return fake.inRangeReturns.result1
+	return fakeReturns.result1
 }
 
 func (fake *Tracker) InRangeCallCount() int {
@@ -173,13 +233,22 @@ func (fake *Tracker) InRangeCallCount() int {
 	return len(fake.inRangeArgsForCall)
 }
 
+func (fake *Tracker) InRangeCalls(stub func(int) bool) {
+	fake.inRangeMutex.Lock()
+	defer fake.inRangeMutex.Unlock()
+	fake.InRangeStub = stub
+}
+
 func (fake *Tracker) InRangeArgsForCall(i int) int {
 	fake.inRangeMutex.RLock()
 	defer fake.inRangeMutex.RUnlock()
-	return fake.inRangeArgsForCall[i].port
+	argsForCall := fake.inRangeArgsForCall[i]
+	return argsForCall.arg1
 }
 
 func (fake *Tracker) InRangeReturns(result1 bool) {
+	fake.inRangeMutex.Lock()
+	defer fake.inRangeMutex.Unlock()
 	fake.InRangeStub = nil
 	fake.inRangeReturns = struct {
 		result1 bool
@@ -187,6 +256,8 @@ func (fake *Tracker) InRangeReturns(result1 bool) {
 }
 
 func (fake *Tracker) InRangeReturnsOnCall(i int, result1 bool) {
+	fake.inRangeMutex.Lock()
+	defer fake.inRangeMutex.Unlock()
 	fake.InRangeStub = nil
 	if fake.inRangeReturnsOnCall == nil {
 		fake.inRangeReturnsOnCall = make(map[int]struct {
@@ -198,16 +269,154 @@ func (fake *Tracker) InRangeReturnsOnCall(i int, result1 bool) {
 	}{result1}
 }
 
+func (fake *Tracker) Release(arg1 *port_allocator.Pool, arg2 string, arg3 []int) error {
+	var arg3Copy []int
+	if arg3 != nil {
+		arg3Copy = make([]int, len(arg3))
+		copy(arg3Copy, arg3)
+	}
+	fake.releaseMutex.Lock()
+	ret, specificReturn := fake.releaseReturnsOnCall[len(fake.releaseArgsForCall)]
+	fake.releaseArgsForCall = append(fake.releaseArgsForCall, struct {
+		arg1 *port_allocator.Pool
+		arg2 string
+		arg3 []int
+	}{arg1, arg2, arg3Copy})
+	stub := fake.ReleaseStub
+	fakeReturns := fake.releaseReturns
+	fake.recordInvocation("Release", []interface{}{arg1, arg2, arg3Copy})
+	fake.releaseMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *Tracker) ReleaseCallCount() int {
+	fake.releaseMutex.RLock()
+	defer fake.releaseMutex.RUnlock()
+	return len(fake.releaseArgsForCall)
+}
+
+func (fake *Tracker) ReleaseCalls(stub func(*port_allocator.Pool, string, []int) error) {
+	fake.releaseMutex.Lock()
+	defer fake.releaseMutex.Unlock()
+	fake.ReleaseStub = stub
+}
+
+func (fake *Tracker) ReleaseArgsForCall(i int) (*port_allocator.Pool, string, []int) {
+	fake.releaseMutex.RLock()
+	defer fake.releaseMutex.RUnlock()
+	argsForCall := fake.releaseArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *Tracker) ReleaseReturns(result1 error) {
+	fake.releaseMutex.Lock()
+	defer fake.releaseMutex.Unlock()
+	fake.ReleaseStub = nil
+	fake.releaseReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Tracker) ReleaseReturnsOnCall(i int, result1 error) {
+	fake.releaseMutex.Lock()
+	defer fake.releaseMutex.Unlock()
+	fake.ReleaseStub = nil
+	if fake.releaseReturnsOnCall == nil {
+		fake.releaseReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.releaseReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Tracker) ReleaseAll(arg1 *port_allocator.Pool, arg2 string) error {
+	fake.releaseAllMutex.Lock()
+	ret, specificReturn := fake.releaseAllReturnsOnCall[len(fake.releaseAllArgsForCall)]
+	fake.releaseAllArgsForCall = append(fake.releaseAllArgsForCall, struct {
+		arg1 *port_allocator.Pool
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.ReleaseAllStub
+	fakeReturns := fake.releaseAllReturns
+	fake.recordInvocation("ReleaseAll", []interface{}{arg1, arg2})
+	fake.releaseAllMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *Tracker) ReleaseAllCallCount() int {
+	fake.releaseAllMutex.RLock()
+	defer fake.releaseAllMutex.RUnlock()
+	return len(fake.releaseAllArgsForCall)
+}
+
+func (fake *Tracker) ReleaseAllCalls(stub func(*port_allocator.Pool, string) error) {
+	fake.releaseAllMutex.Lock()
+	defer fake.releaseAllMutex.Unlock()
+	fake.ReleaseAllStub = stub
+}
+
+func (fake *Tracker) ReleaseAllArgsForCall(i int) (*port_allocator.Pool, string) {
+	fake.releaseAllMutex.RLock()
+	defer fake.releaseAllMutex.RUnlock()
+	argsForCall := fake.releaseAllArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *Tracker) ReleaseAllReturns(result1 error) {
+	fake.releaseAllMutex.Lock()
+	defer fake.releaseAllMutex.Unlock()
+	fake.ReleaseAllStub = nil
+	fake.releaseAllReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Tracker) ReleaseAllReturnsOnCall(i int, result1 error) {
+	fake.releaseAllMutex.Lock()
+	defer fake.releaseAllMutex.Unlock()
+	fake.ReleaseAllStub = nil
+	if fake.releaseAllReturnsOnCall == nil {
+		fake.releaseAllReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.releaseAllReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *Tracker) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.acquireOneMutex.RLock()
 	defer fake.acquireOneMutex.RUnlock()
-	fake.releaseAllMutex.RLock()
-	defer fake.releaseAllMutex.RUnlock()
+	fake.acquireRangeMutex.RLock()
+	defer fake.acquireRangeMutex.RUnlock()
 	fake.inRangeMutex.RLock()
 	defer fake.inRangeMutex.RUnlock()
-	return fake.invocations
+	fake.releaseMutex.RLock()
+	defer fake.releaseMutex.RUnlock()
+	fake.releaseAllMutex.RLock()
+	defer fake.releaseAllMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
 }
 
 func (fake *Tracker) recordInvocation(key string, args []interface{}) {
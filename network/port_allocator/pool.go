@@ -3,19 +3,32 @@ package port_allocator
 import (
 	"encoding/json"
 	"errors"
+	"time"
 )
 
 var ErrorPortPoolExhausted = errors.New("port pool exhausted")
 
 type Pool struct {
 	AcquiredPorts map[int]string
+	// NextPort is where the search for a free port starts, so that ports are
+	// handed out in turn rather than lowest first
+	NextPort int
+	// ReleasedPorts records when ports were released for the reuse quarantine
+	ReleasedPorts map[int]time.Time
+}
+
+type poolJSON struct {
+	AcquiredPorts map[string][]int  `json:"acquired_ports"`
+	NextPort      int               `json:"next_port,omitempty"`
+	ReleasedPorts map[int]time.Time `json:"released_ports,omitempty"`
 }
 
 func (p *Pool) MarshalJSON() ([]byte, error) {
-	var jsonData struct {
-		AcquiredPorts map[string][]int `json:"acquired_ports"`
+	jsonData := poolJSON{
+		AcquiredPorts: make(map[string][]int),
+		NextPort:      p.NextPort,
+		ReleasedPorts: p.ReleasedPorts,
 	}
-	jsonData.AcquiredPorts = make(map[string][]int)
 
 	for port, handle := range p.AcquiredPorts {
 		jsonData.AcquiredPorts[handle] = append(jsonData.AcquiredPorts[handle], port)
@@ -23,10 +36,10 @@ func (p *Pool) MarshalJSON() ([]byte, error) {
 	return json.Marshal(jsonData)
 }
 
+// UnmarshalJSON also accepts state files written before the cursor and
+// quarantine were added, which only hold the acquired ports
 func (p *Pool) UnmarshalJSON(bytes []byte) error {
-	var jsonData struct {
-		AcquiredPorts map[string][]int `json:"acquired_ports"`
-	}
+	var jsonData poolJSON
 	err := json.Unmarshal(bytes, &jsonData)
 	if err != nil {
 		return err
@@ -38,12 +51,16 @@ func (p *Pool) UnmarshalJSON(bytes []byte) error {
 			p.AcquiredPorts[port] = handle
 		}
 	}
+	p.NextPort = jsonData.NextPort
+	p.ReleasedPorts = jsonData.ReleasedPorts
 	return nil
 }
 
 type Tracker struct {
 	StartPort int
 	Capacity  int
+	// Quarantine is how long a released port is kept from being reissued
+	Quarantine time.Duration
 }
 
 func (t *Tracker) InRange(port int) bool {
@@ -51,29 +68,108 @@ func (t *Tracker) InRange(port int) bool {
 }
 
 func (t *Tracker) AcquireOne(pool *Pool, handler string) (int, error) {
+	return t.AcquireRange(pool, handler, 1)
+}
+
+// AcquireRange reserves count contiguous ports and returns the first of them.
+// The search starts from the pool's cursor and wraps around the range,
+// skipping ports that are acquired or still in quarantine.
+func (t *Tracker) AcquireRange(pool *Pool, handle string, count int) (int, error) {
 	if pool.AcquiredPorts == nil {
 		pool.AcquiredPorts = make(map[int]string)
 	}
+	t.expireQuarantine(pool, time.Now())
+
+	first := 0
+	if t.InRange(pool.NextPort) {
+		first = pool.NextPort - t.StartPort
+	}
 
 	for i := 0; i < t.Capacity; i++ {
-		candidatePort := t.StartPort + i
-		if !contains(pool.AcquiredPorts, candidatePort) {
-			pool.AcquiredPorts[candidatePort] = handler
+		candidatePort := t.StartPort + (first+i)%t.Capacity
+		if candidatePort+count > t.StartPort+t.Capacity {
+			continue
+		}
+
+		free := true
+		for j := 0; j < count; j++ {
+			if !t.available(pool, candidatePort+j) {
+				// no block containing this port is free, so skip past it
+				i += j
+				free = false
+				break
+			}
+		}
+
+		if free {
+			for j := 0; j < count; j++ {
+				pool.AcquiredPorts[candidatePort+j] = handle
+				delete(pool.ReleasedPorts, candidatePort+j)
+			}
+			pool.NextPort = candidatePort + count
+			if !t.InRange(pool.NextPort) {
+				pool.NextPort = t.StartPort
+			}
 			return candidatePort, nil
 		}
 	}
+
 	return -1, ErrorPortPoolExhausted
 }
 
 func (t *Tracker) ReleaseAll(pool *Pool, handle string) error {
+	now := time.Now()
 	for port, h := range pool.AcquiredPorts {
 		if h == handle {
 			delete(pool.AcquiredPorts, port)
+			t.quarantine(pool, port, now)
 		}
 	}
+	t.expireQuarantine(pool, now)
 	return nil
 }
 
+// Release frees those of ports that are held by handle
+func (t *Tracker) Release(pool *Pool, handle string, ports []int) error {
+	now := time.Now()
+	for _, port := range ports {
+		if h, ok := pool.AcquiredPorts[port]; ok && h == handle {
+			delete(pool.AcquiredPorts, port)
+			t.quarantine(pool, port, now)
+		}
+	}
+	t.expireQuarantine(pool, now)
+	return nil
+}
+
+func (t *Tracker) available(pool *Pool, port int) bool {
+	if contains(pool.AcquiredPorts, port) {
+		return false
+	}
+
+	_, quarantined := pool.ReleasedPorts[port]
+	return !quarantined
+}
+
+func (t *Tracker) quarantine(pool *Pool, port int, now time.Time) {
+	if t.Quarantine <= 0 || !t.InRange(port) {
+		return
+	}
+
+	if pool.ReleasedPorts == nil {
+		pool.ReleasedPorts = make(map[int]time.Time)
+	}
+	pool.ReleasedPorts[port] = now
+}
+
+func (t *Tracker) expireQuarantine(pool *Pool, now time.Time) {
+	for port, released := range pool.ReleasedPorts {
+		if now.Sub(released) >= t.Quarantine {
+			delete(pool.ReleasedPorts, port)
+		}
+	}
+}
+
 func contains(list map[int]string, candidate int) bool {
 	_, ok := list[candidate]
 	return ok
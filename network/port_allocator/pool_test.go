@@ -100,6 +100,58 @@ var _ = Describe("Tracker", func() {
 		})
 	})
 
+	Describe("AcquireRange", func() {
+		It("reserves and returns the start of a contiguous block of ports", func() {
+			start, err := tracker.AcquireRange(pool, "some-handle", 3)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(start).To(Equal(100))
+			Expect(pool.AcquiredPorts).To(Equal(map[int]string{
+				100: "some-handle",
+				101: "some-handle",
+				102: "some-handle",
+			}))
+		})
+
+		Context("when acquired ports fragment the range", func() {
+			BeforeEach(func() {
+				pool.AcquiredPorts = map[int]string{
+					101: "other-handle",
+					104: "other-handle",
+				}
+			})
+
+			It("skips blocks that are not wholly free", func() {
+				start, err := tracker.AcquireRange(pool, "some-handle", 3)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(start).To(Equal(105))
+				Expect(pool.AcquiredPorts).To(HaveLen(5))
+				Expect(pool.AcquiredPorts[101]).To(Equal("other-handle"))
+			})
+		})
+
+		Context("when no block is large enough", func() {
+			BeforeEach(func() {
+				pool.AcquiredPorts = map[int]string{
+					103: "other-handle",
+					106: "other-handle",
+				}
+			})
+
+			It("returns a useful error and acquires nothing", func() {
+				_, err := tracker.AcquireRange(pool, "some-handle", 4)
+				Expect(err).To(Equal(port_allocator.ErrorPortPoolExhausted))
+				Expect(pool.AcquiredPorts).To(HaveLen(2))
+			})
+		})
+
+		Context("when the block would run past the end of the range", func() {
+			It("returns a useful error", func() {
+				_, err := tracker.AcquireRange(pool, "some-handle", 11)
+				Expect(err).To(Equal(port_allocator.ErrorPortPoolExhausted))
+			})
+		})
+	})
+
 	Describe("acquire and release lifecycle", func() {
 		It("can re-acquire ports which have been acquired and then released", func() {
 			var err error
@@ -118,6 +170,109 @@ var _ = Describe("Tracker", func() {
 		})
 	})
 
+	Describe("next-free cursor", func() {
+		It("does not immediately reissue a released port", func() {
+			first, err := tracker.AcquireOne(pool, "some-handle")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tracker.ReleaseAll(pool, "some-handle")).To(Succeed())
+
+			second, err := tracker.AcquireOne(pool, "other-handle")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(second).To(Equal(first + 1))
+		})
+
+		It("wraps around to the start of the range", func() {
+			pool.NextPort = 109
+
+			port, err := tracker.AcquireOne(pool, "some-handle")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(port).To(Equal(109))
+
+			port, err = tracker.AcquireOne(pool, "some-handle")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(port).To(Equal(100))
+		})
+
+		It("starts from the beginning when the cursor is outside the range", func() {
+			pool.NextPort = 40000
+
+			port, err := tracker.AcquireOne(pool, "some-handle")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(port).To(Equal(100))
+			Expect(pool.NextPort).To(Equal(101))
+		})
+
+		It("does not hand out ranges that wrap past the end of the range", func() {
+			pool.NextPort = 108
+
+			port, err := tracker.AcquireRange(pool, "some-handle", 3)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(port).To(Equal(100))
+			Expect(pool.NextPort).To(Equal(103))
+		})
+	})
+
+	Describe("reuse quarantine", func() {
+		BeforeEach(func() {
+			tracker.Quarantine = time.Hour
+		})
+
+		It("records when ports are released", func() {
+			pool.AcquiredPorts = map[int]string{100: "some-handle", 101: "other-handle", 42: "some-handle"}
+
+			Expect(tracker.ReleaseAll(pool, "some-handle")).To(Succeed())
+			Expect(pool.ReleasedPorts).To(HaveLen(1))
+			Expect(pool.ReleasedPorts[100]).To(BeTemporally("~", time.Now(), time.Minute))
+
+			Expect(tracker.Release(pool, "other-handle", []int{101})).To(Succeed())
+			Expect(pool.ReleasedPorts).To(HaveKey(101))
+		})
+
+		It("does not reissue ports in quarantine", func() {
+			tracker.Capacity = 2
+			pool.ReleasedPorts = map[int]time.Time{100: time.Now().Add(-time.Minute)}
+
+			port, err := tracker.AcquireOne(pool, "some-handle")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(port).To(Equal(101))
+
+			_, err = tracker.AcquireOne(pool, "some-handle")
+			Expect(err).To(Equal(port_allocator.ErrorPortPoolExhausted))
+		})
+
+		It("reissues ports once the quarantine has passed", func() {
+			tracker.Capacity = 1
+			pool.ReleasedPorts = map[int]time.Time{100: time.Now().Add(-2 * time.Hour)}
+
+			port, err := tracker.AcquireOne(pool, "some-handle")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(port).To(Equal(100))
+			Expect(pool.ReleasedPorts).To(BeEmpty())
+		})
+
+		Context("when there is no quarantine", func() {
+			BeforeEach(func() {
+				tracker.Quarantine = 0
+			})
+
+			It("does not record released ports", func() {
+				pool.AcquiredPorts = map[int]string{100: "some-handle"}
+
+				Expect(tracker.ReleaseAll(pool, "some-handle")).To(Succeed())
+				Expect(pool.ReleasedPorts).To(BeEmpty())
+			})
+		})
+	})
+
+	Describe("Release", func() {
+		It("releases only the given ports held by the handle", func() {
+			pool.AcquiredPorts = map[int]string{100: "some-handle", 101: "some-handle", 102: "other-handle"}
+
+			Expect(tracker.Release(pool, "some-handle", []int{100, 102, 8080})).To(Succeed())
+			Expect(pool.AcquiredPorts).To(Equal(map[int]string{101: "some-handle", 102: "other-handle"}))
+		})
+	})
+
 	Describe("InRange", func() {
 		It("returns true if the given port is in the allocation range", func() {
 			for i := 100; i < 110; i++ {
@@ -145,6 +300,32 @@ var _ = Describe("Tracker", func() {
 			Expect(newPool.AcquiredPorts).To(Equal(pool.AcquiredPorts))
 		})
 
+		It("round-trips the cursor and quarantine", func() {
+			released := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+			pool.AcquiredPorts = map[int]string{105: "some-handle"}
+			pool.NextPort = 106
+			pool.ReleasedPorts = map[int]time.Time{104: released}
+
+			bytes, err := json.Marshal(pool)
+			Expect(err).NotTo(HaveOccurred())
+
+			var newPool port_allocator.Pool
+			Expect(json.Unmarshal(bytes, &newPool)).To(Succeed())
+
+			Expect(newPool.NextPort).To(Equal(106))
+			Expect(newPool.ReleasedPorts).To(HaveLen(1))
+			Expect(newPool.ReleasedPorts[104].Equal(released)).To(BeTrue())
+		})
+
+		It("decodes state files which only contain acquired ports", func() {
+			var newPool port_allocator.Pool
+			Expect(json.Unmarshal([]byte(`{"acquired_ports":{"some-handle":[42]}}`), &newPool)).To(Succeed())
+
+			Expect(newPool.AcquiredPorts).To(Equal(map[int]string{42: "some-handle"}))
+			Expect(newPool.NextPort).To(Equal(0))
+			Expect(newPool.ReleasedPorts).To(BeEmpty())
+		})
+
 		It("marshals as a map from container handle to list of allocated ports", func() {
 			pool.AcquiredPorts = map[int]string{
 				42:  "some-handle",
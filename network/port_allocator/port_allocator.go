@@ -3,6 +3,7 @@ package port_allocator
 import (
 	"errors"
 	"fmt"
+	"sort"
 
 	"code.cloudfoundry.org/filelock"
 	"code.cloudfoundry.org/winc/network/port_allocator/serial"
@@ -11,7 +12,9 @@ import (
 //go:generate counterfeiter -o fakes/tracker.go --fake-name Tracker . tracker
 type tracker interface {
 	AcquireOne(pool *Pool, handle string) (int, error)
+	AcquireRange(pool *Pool, handle string, count int) (int, error)
 	ReleaseAll(pool *Pool, handle string) error
+	Release(pool *Pool, handle string, ports []int) error
 	InRange(port int) bool
 }
 
@@ -21,13 +24,14 @@ type PortAllocator struct {
 	Locker     filelock.FileLocker
 }
 
+// AllocatePort allocates a port from the allocation range to handle. A
+// non-zero port is reserved for handle as is, see reservePorts.
 func (p *PortAllocator) AllocatePort(handle string, port int) (int, error) {
 	if port != 0 {
-		if p.Tracker.InRange(port) {
-			return -1, errors.New("cannot specify port from allocation range")
-		} else {
-			return port, nil
+		if err := p.reservePorts(handle, port, 1); err != nil {
+			return -1, err
 		}
+		return port, nil
 	}
 
 	file, err := p.Locker.Open()
@@ -55,6 +59,42 @@ func (p *PortAllocator) AllocatePort(handle string, port int) (int, error) {
 	return newPort, nil
 }
 
+// AllocatePortRange allocates count contiguous ports under a single lock of the
+// state file and returns the first of them. A non-zero port is reserved for
+// handle as is, see reservePorts.
+func (p *PortAllocator) AllocatePortRange(handle string, port, count int) (int, error) {
+	if port != 0 {
+		if err := p.reservePorts(handle, port, count); err != nil {
+			return -1, err
+		}
+		return port, nil
+	}
+
+	file, err := p.Locker.Open()
+	if err != nil {
+		return -1, fmt.Errorf("open lock: %s", err)
+	}
+	defer file.Close() // defer not tested
+
+	pool := &Pool{}
+	err = p.Serializer.DecodeAll(file, pool)
+	if err != nil {
+		return -1, fmt.Errorf("decoding state file: %s", err)
+	}
+
+	startPort, err := p.Tracker.AcquireRange(pool, handle, count)
+	if err != nil {
+		return -1, fmt.Errorf("acquire port range: %s", err)
+	}
+
+	err = p.Serializer.EncodeAndOverwrite(file, pool)
+	if err != nil {
+		return -1, fmt.Errorf("encode and overwrite: %s", err)
+	}
+
+	return startPort, nil
+}
+
 func (p *PortAllocator) ReleaseAllPorts(handle string) error {
 	file, err := p.Locker.Open()
 	if err != nil {
@@ -79,3 +119,142 @@ func (p *PortAllocator) ReleaseAllPorts(handle string) error {
 
 	return nil
 }
+
+// ReleasePorts returns ports allocated to handle to the pool, leaving any other
+// ports the handle holds allocated.
+func (p *PortAllocator) ReleasePorts(handle string, ports []int) error {
+	file, err := p.Locker.Open()
+	if err != nil {
+		return fmt.Errorf("open lock: %s", err)
+	}
+	defer file.Close() // defer not tested
+
+	pool := &Pool{}
+	err = p.Serializer.DecodeAll(file, pool)
+	if err != nil {
+		return fmt.Errorf("decoding state file: %s", err)
+	}
+
+	if err := p.Tracker.Release(pool, handle, ports); err != nil {
+		return fmt.Errorf("release ports: %s", err)
+	}
+
+	err = p.Serializer.EncodeAndOverwrite(file, pool)
+	if err != nil {
+		return fmt.Errorf("encode and overwrite: %s", err)
+	}
+
+	return nil
+}
+
+// Pool returns the current state of the pool without changing it
+func (p *PortAllocator) Pool() (Pool, error) {
+	file, err := p.Locker.Open()
+	if err != nil {
+		return Pool{}, fmt.Errorf("open lock: %s", err)
+	}
+	defer file.Close() // defer not tested
+
+	pool := &Pool{}
+	err = p.Serializer.DecodeAll(file, pool)
+	if err != nil {
+		return Pool{}, fmt.Errorf("decoding state file: %s", err)
+	}
+
+	return *pool, nil
+}
+
+// ReleaseStale releases the ports of every handle missing from the handles
+// returned by liveHandles and returns the ports it released for each of them.
+// liveHandles is called while the state file is locked so that containers
+// allocating ports concurrently are not mistaken for stale ones.
+func (p *PortAllocator) ReleaseStale(liveHandles func() (map[string]bool, error)) (map[string][]int, error) {
+	file, err := p.Locker.Open()
+	if err != nil {
+		return nil, fmt.Errorf("open lock: %s", err)
+	}
+	defer file.Close() // defer not tested
+
+	pool := &Pool{}
+	err = p.Serializer.DecodeAll(file, pool)
+	if err != nil {
+		return nil, fmt.Errorf("decoding state file: %s", err)
+	}
+
+	live, err := liveHandles()
+	if err != nil {
+		return nil, fmt.Errorf("list live handles: %s", err)
+	}
+
+	released := map[string][]int{}
+	for port, handle := range pool.AcquiredPorts {
+		if !live[handle] {
+			released[handle] = append(released[handle], port)
+		}
+	}
+
+	if len(released) == 0 {
+		return released, nil
+	}
+
+	for handle, ports := range released {
+		sort.Ints(ports)
+		if err := p.Tracker.ReleaseAll(pool, handle); err != nil {
+			return nil, fmt.Errorf("release all ports: %s", err)
+		}
+	}
+
+	err = p.Serializer.EncodeAndOverwrite(file, pool)
+	if err != nil {
+		return nil, fmt.Errorf("encode and overwrite: %s", err)
+	}
+
+	return released, nil
+}
+
+// reservePorts records count ports from port as allocated to handle, so that
+// no other container can be given them until handle releases them. Ports
+// handle already holds, e.g. from an earlier attempt at the same rule, are
+// kept. Other ports in the allocation range can only be handed out by the
+// tracker, and ports outside it may still be held by other containers if the
+// range was changed while they held them.
+func (p *PortAllocator) reservePorts(handle string, port, count int) error {
+	file, err := p.Locker.Open()
+	if err != nil {
+		return fmt.Errorf("open lock: %s", err)
+	}
+	defer file.Close() // defer not tested
+
+	pool := &Pool{}
+	err = p.Serializer.DecodeAll(file, pool)
+	if err != nil {
+		return fmt.Errorf("decoding state file: %s", err)
+	}
+
+	for i := 0; i < count; i++ {
+		if h, ok := pool.AcquiredPorts[port+i]; ok {
+			if h != handle {
+				return &PortInUseError{Port: port + i, Handle: h}
+			}
+			continue
+		}
+
+		if p.Tracker.InRange(port + i) {
+			return errors.New("cannot specify port from allocation range")
+		}
+	}
+
+	if pool.AcquiredPorts == nil {
+		pool.AcquiredPorts = make(map[int]string)
+	}
+	for i := 0; i < count; i++ {
+		pool.AcquiredPorts[port+i] = handle
+	}
+
+	err = p.Serializer.EncodeAndOverwrite(file, pool)
+	if err != nil {
+		return fmt.Errorf("encode and overwrite: %s", err)
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package port_allocator_test
 
 import (
 	"errors"
+	"io"
 	"io/ioutil"
 	"os"
 
@@ -70,12 +71,33 @@ var _ = Describe("PortAllocator", func() {
 			BeforeEach(func() {
 				tracker.InRangeReturns(false)
 			})
-			It("noops and returns the port", func() {
+
+			It("reserves the port for the handle and returns it", func() {
 				port, err := portAllocator.AllocatePort("some-handle", 42)
 				Expect(err).NotTo(HaveOccurred())
 
 				Expect(tracker.AcquireOneCallCount()).To(Equal(0))
 				Expect(port).To(Equal(42))
+
+				Expect(serializer.EncodeAndOverwriteCallCount()).To(Equal(1))
+				file, pool := serializer.EncodeAndOverwriteArgsForCall(0)
+				Expect(file).To(Equal(lockedFile))
+				Expect(pool.(*port_allocator.Pool).AcquiredPorts).To(Equal(map[int]string{42: "some-handle"}))
+			})
+
+			Context("when the port is allocated to another handle", func() {
+				BeforeEach(func() {
+					serializer.DecodeAllStub = func(_ io.ReadSeeker, v interface{}) error {
+						v.(*port_allocator.Pool).AcquiredPorts = map[int]string{42: "other-handle"}
+						return nil
+					}
+				})
+
+				It("returns an error", func() {
+					_, err := portAllocator.AllocatePort("some-handle", 42)
+					Expect(err).To(MatchError("port 42 is allocated to other-handle"))
+					Expect(serializer.EncodeAndOverwriteCallCount()).To(Equal(0))
+				})
 			})
 		})
 
@@ -83,10 +105,26 @@ var _ = Describe("PortAllocator", func() {
 			BeforeEach(func() {
 				tracker.InRangeReturns(true)
 			})
+
 			It("returns an error", func() {
 				_, err := portAllocator.AllocatePort("some-handle", 42)
 				Expect(err).To(MatchError(errors.New("cannot specify port from allocation range")))
 			})
+
+			Context("when the port is already allocated to the handle", func() {
+				BeforeEach(func() {
+					serializer.DecodeAllStub = func(_ io.ReadSeeker, v interface{}) error {
+						v.(*port_allocator.Pool).AcquiredPorts = map[int]string{42: "some-handle"}
+						return nil
+					}
+				})
+
+				It("keeps it allocated and returns it", func() {
+					port, err := portAllocator.AllocatePort("some-handle", 42)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(port).To(Equal(42))
+				})
+			})
 		})
 
 		It("re-serializes the pool to the locked file", func() {
@@ -162,6 +200,101 @@ var _ = Describe("PortAllocator", func() {
 		})
 	})
 
+	Describe("AllocatePortRange", func() {
+		BeforeEach(func() {
+			tracker.AcquireRangeReturns(200, nil)
+		})
+
+		It("acquires the whole range from the pool under a single lock", func() {
+			port, err := portAllocator.AllocatePortRange("some-handle", 0, 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(port).To(Equal(200))
+
+			Expect(locker.OpenCallCount()).To(Equal(1))
+			Expect(serializer.DecodeAllCallCount()).To(Equal(1))
+			Expect(tracker.AcquireOneCallCount()).To(Equal(0))
+			Expect(tracker.AcquireRangeCallCount()).To(Equal(1))
+
+			_, pool := serializer.DecodeAllArgsForCall(0)
+			receivedPool, receivedHandle, receivedCount := tracker.AcquireRangeArgsForCall(0)
+			Expect(receivedPool).To(Equal(pool))
+			Expect(receivedHandle).To(Equal("some-handle"))
+			Expect(receivedCount).To(Equal(10))
+
+			Expect(serializer.EncodeAndOverwriteCallCount()).To(Equal(1))
+		})
+
+		Context("when the passed in port is non-zero and the range is outside the allocation range", func() {
+			BeforeEach(func() {
+				tracker.InRangeReturns(false)
+			})
+
+			It("reserves the ports for the handle and returns the first", func() {
+				port, err := portAllocator.AllocatePortRange("some-handle", 42, 10)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(port).To(Equal(42))
+				Expect(tracker.InRangeCallCount()).To(Equal(10))
+				Expect(tracker.AcquireRangeCallCount()).To(Equal(0))
+
+				Expect(serializer.EncodeAndOverwriteCallCount()).To(Equal(1))
+				_, pool := serializer.EncodeAndOverwriteArgsForCall(0)
+				acquired := pool.(*port_allocator.Pool).AcquiredPorts
+				Expect(acquired).To(HaveLen(10))
+				Expect(acquired).To(HaveKeyWithValue(51, "some-handle"))
+			})
+
+			Context("when a port in the range is allocated to another handle", func() {
+				BeforeEach(func() {
+					serializer.DecodeAllStub = func(_ io.ReadSeeker, v interface{}) error {
+						v.(*port_allocator.Pool).AcquiredPorts = map[int]string{45: "other-handle"}
+						return nil
+					}
+				})
+
+				It("returns an error", func() {
+					_, err := portAllocator.AllocatePortRange("some-handle", 42, 10)
+					Expect(err).To(MatchError(&port_allocator.PortInUseError{Port: 45, Handle: "other-handle"}))
+				})
+			})
+		})
+
+		Context("when the passed in range overlaps the allocation range", func() {
+			BeforeEach(func() {
+				tracker.InRangeStub = func(port int) bool {
+					return port == 45
+				}
+			})
+
+			It("returns an error", func() {
+				_, err := portAllocator.AllocatePortRange("some-handle", 42, 10)
+				Expect(err).To(MatchError(errors.New("cannot specify port from allocation range")))
+			})
+		})
+
+		Context("when the tracker cannot acquire the range", func() {
+			BeforeEach(func() {
+				tracker.AcquireRangeReturns(0, errors.New("turnip"))
+			})
+
+			It("wraps and returns the error", func() {
+				_, err := portAllocator.AllocatePortRange("some-handle", 0, 10)
+				Expect(err).To(MatchError("acquire port range: turnip"))
+				Expect(serializer.EncodeAndOverwriteCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when serializing the pool fails", func() {
+			BeforeEach(func() {
+				serializer.EncodeAndOverwriteReturns(errors.New("turnip"))
+			})
+
+			It("wraps and returns the error", func() {
+				_, err := portAllocator.AllocatePortRange("some-handle", 0, 10)
+				Expect(err).To(MatchError("encode and overwrite: turnip"))
+			})
+		})
+	})
+
 	Describe("ReleaseAllPorts", func() {
 		It("deserializes the pool from the locked file", func() {
 			err := portAllocator.ReleaseAllPorts("some-handle")
@@ -240,4 +373,117 @@ var _ = Describe("PortAllocator", func() {
 		})
 
 	})
+
+	Describe("ReleasePorts", func() {
+		It("releases the ports from the pool under a single lock", func() {
+			err := portAllocator.ReleasePorts("some-handle", []int{100, 101})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(locker.OpenCallCount()).To(Equal(1))
+			Expect(tracker.ReleaseCallCount()).To(Equal(1))
+			_, handle, ports := tracker.ReleaseArgsForCall(0)
+			Expect(handle).To(Equal("some-handle"))
+			Expect(ports).To(Equal([]int{100, 101}))
+
+			_, poolForDecode := serializer.DecodeAllArgsForCall(0)
+			file, poolForEncode := serializer.EncodeAndOverwriteArgsForCall(0)
+			Expect(file).To(Equal(lockedFile))
+			Expect(poolForEncode).To(Equal(poolForDecode))
+		})
+
+		Context("when the tracker fails to release the ports", func() {
+			BeforeEach(func() {
+				tracker.ReleaseReturns(errors.New("turnip"))
+			})
+			It("wraps and returns the error", func() {
+				err := portAllocator.ReleasePorts("some-handle", []int{100})
+				Expect(err).To(MatchError("release ports: turnip"))
+				Expect(serializer.EncodeAndOverwriteCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Describe("Pool", func() {
+		It("returns the pool decoded from the locked file without re-serializing it", func() {
+			serializer.DecodeAllStub = func(_ io.ReadSeeker, v interface{}) error {
+				v.(*port_allocator.Pool).AcquiredPorts = map[int]string{40000: "some-handle"}
+				return nil
+			}
+
+			pool, err := portAllocator.Pool()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pool.AcquiredPorts).To(Equal(map[int]string{40000: "some-handle"}))
+
+			file, _ := serializer.DecodeAllArgsForCall(0)
+			Expect(file).To(Equal(lockedFile))
+			Expect(serializer.EncodeAndOverwriteCallCount()).To(Equal(0))
+		})
+
+		Context("when the serializer fails to decode", func() {
+			BeforeEach(func() {
+				serializer.DecodeAllReturns(errors.New("potato"))
+			})
+
+			It("wraps and returns the error", func() {
+				_, err := portAllocator.Pool()
+				Expect(err).To(MatchError("decoding state file: potato"))
+			})
+		})
+	})
+
+	Describe("ReleaseStale", func() {
+		var liveHandles map[string]bool
+
+		BeforeEach(func() {
+			liveHandles = map[string]bool{"live-handle": true}
+			serializer.DecodeAllStub = func(_ io.ReadSeeker, v interface{}) error {
+				v.(*port_allocator.Pool).AcquiredPorts = map[int]string{
+					40002: "stale-handle",
+					40000: "stale-handle",
+					40001: "live-handle",
+				}
+				return nil
+			}
+		})
+
+		It("releases the ports of handles that are not live", func() {
+			released, err := portAllocator.ReleaseStale(func() (map[string]bool, error) {
+				Expect(locker.OpenCallCount()).To(Equal(1))
+				return liveHandles, nil
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(released).To(Equal(map[string][]int{"stale-handle": {40000, 40002}}))
+
+			Expect(tracker.ReleaseAllCallCount()).To(Equal(1))
+			_, handle := tracker.ReleaseAllArgsForCall(0)
+			Expect(handle).To(Equal("stale-handle"))
+
+			_, poolForDecode := serializer.DecodeAllArgsForCall(0)
+			file, poolForEncode := serializer.EncodeAndOverwriteArgsForCall(0)
+			Expect(file).To(Equal(lockedFile))
+			Expect(poolForEncode).To(Equal(poolForDecode))
+		})
+
+		Context("when every handle is live", func() {
+			BeforeEach(func() {
+				liveHandles["stale-handle"] = true
+			})
+
+			It("leaves the state file alone", func() {
+				released, err := portAllocator.ReleaseStale(func() (map[string]bool, error) { return liveHandles, nil })
+				Expect(err).NotTo(HaveOccurred())
+				Expect(released).To(BeEmpty())
+				Expect(serializer.EncodeAndOverwriteCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when listing the live handles fails", func() {
+			It("wraps and returns the error without releasing anything", func() {
+				_, err := portAllocator.ReleaseStale(func() (map[string]bool, error) { return nil, errors.New("turnip") })
+				Expect(err).To(MatchError("list live handles: turnip"))
+				Expect(tracker.ReleaseAllCallCount()).To(Equal(0))
+				Expect(serializer.EncodeAndOverwriteCallCount()).To(Equal(0))
+			})
+		})
+	})
 })
@@ -0,0 +1,78 @@
+package port_allocator
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+)
+
+type PortRange struct {
+	Start int
+	Size  int
+}
+
+func (r PortRange) End() int {
+	return r.Start + r.Size - 1
+}
+
+func (r PortRange) Overlaps(other PortRange) bool {
+	return r.Start <= other.End() && other.Start <= r.End()
+}
+
+// ValidateRange checks that r is a range of valid ports which Windows will not
+// also hand out as ephemeral ports from any of the dynamic ranges
+func ValidateRange(r PortRange, dynamic []PortRange) error {
+	if r.Start < 1 || r.Size < 1 || r.End() > 65535 {
+		return &InvalidPortRangeError{Start: r.Start, Size: r.Size}
+	}
+
+	for _, d := range dynamic {
+		if r.Overlaps(d) {
+			return &DynamicPortRangeOverlapError{Range: r, Dynamic: d}
+		}
+	}
+
+	return nil
+}
+
+// DynamicPortRanges returns the host's dynamic port ranges as reported by
+// netsh for each IP version and protocol
+func DynamicPortRanges() ([]PortRange, error) {
+	ranges := []PortRange{}
+	for _, ipVersion := range []string{"ipv4", "ipv6"} {
+		for _, protocol := range []string{"tcp", "udp"} {
+			output, err := exec.Command("netsh", "int", ipVersion, "show", "dynamicport", protocol).CombinedOutput()
+			if err != nil {
+				return nil, fmt.Errorf("show %s %s dynamic port range: %s: %s", ipVersion, protocol, err, string(output))
+			}
+
+			r, err := ParseDynamicPortRange(string(output))
+			if err != nil {
+				return nil, fmt.Errorf("show %s %s dynamic port range: %s", ipVersion, protocol, err)
+			}
+			ranges = append(ranges, r)
+		}
+	}
+
+	return ranges, nil
+}
+
+var (
+	startPortRegexp     = regexp.MustCompile(`Start Port\s*:\s*(\d+)`)
+	numberOfPortsRegexp = regexp.MustCompile(`Number of Ports\s*:\s*(\d+)`)
+)
+
+// ParseDynamicPortRange parses the output of `netsh int ipv4 show dynamicport tcp`
+func ParseDynamicPortRange(output string) (PortRange, error) {
+	start := startPortRegexp.FindStringSubmatch(output)
+	size := numberOfPortsRegexp.FindStringSubmatch(output)
+	if start == nil || size == nil {
+		return PortRange{}, fmt.Errorf("unexpected netsh output: %q", output)
+	}
+
+	r := PortRange{}
+	r.Start, _ = strconv.Atoi(start[1])
+	r.Size, _ = strconv.Atoi(size[1])
+	return r, nil
+}
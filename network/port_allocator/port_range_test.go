@@ -0,0 +1,63 @@
+package port_allocator_test
+
+import (
+	"code.cloudfoundry.org/winc/network/port_allocator"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PortRange", func() {
+	Describe("ValidateRange", func() {
+		var dynamic []port_allocator.PortRange
+
+		BeforeEach(func() {
+			dynamic = []port_allocator.PortRange{{Start: 49152, Size: 16384}}
+		})
+
+		It("accepts a range below the dynamic port range", func() {
+			Expect(port_allocator.ValidateRange(port_allocator.PortRange{Start: 40000, Size: 5000}, dynamic)).To(Succeed())
+		})
+
+		It("accepts a range ending right before the dynamic port range", func() {
+			Expect(port_allocator.ValidateRange(port_allocator.PortRange{Start: 44152, Size: 5000}, dynamic)).To(Succeed())
+		})
+
+		It("rejects a range overlapping the dynamic port range", func() {
+			r := port_allocator.PortRange{Start: 45000, Size: 5000}
+			err := port_allocator.ValidateRange(r, dynamic)
+			Expect(err).To(MatchError(&port_allocator.DynamicPortRangeOverlapError{Range: r, Dynamic: dynamic[0]}))
+			Expect(err).To(MatchError("port range 45000-49999 overlaps the dynamic port range 49152-65535"))
+		})
+
+		It("rejects a range containing the dynamic port range", func() {
+			dynamic = append(dynamic, port_allocator.PortRange{Start: 20000, Size: 100})
+			err := port_allocator.ValidateRange(port_allocator.PortRange{Start: 10000, Size: 15000}, dynamic)
+			Expect(err).To(BeAssignableToTypeOf(&port_allocator.DynamicPortRangeOverlapError{}))
+		})
+
+		DescribeTable("invalid ranges",
+			func(start, size int) {
+				err := port_allocator.ValidateRange(port_allocator.PortRange{Start: start, Size: size}, nil)
+				Expect(err).To(MatchError(&port_allocator.InvalidPortRangeError{Start: start, Size: size}))
+			},
+			Entry("zero start", 0, 10),
+			Entry("zero size", 40000, 0),
+			Entry("beyond the last port", 65000, 1000),
+		)
+	})
+
+	Describe("ParseDynamicPortRange", func() {
+		It("parses the netsh output", func() {
+			output := "\r\nProtocol tcp Dynamic Port Range\r\n---------------------------------\r\nStart Port      : 49152\r\nNumber of Ports : 16384\r\n\r\n"
+			r, err := port_allocator.ParseDynamicPortRange(output)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(r).To(Equal(port_allocator.PortRange{Start: 49152, Size: 16384}))
+		})
+
+		It("returns an error for unexpected output", func() {
+			_, err := port_allocator.ParseDynamicPortRange("The following command was not found")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,187 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	"sync"
+
+	"code.cloudfoundry.org/winc/network/reconciler"
+	"github.com/Microsoft/hcsshim"
+)
+
+type HCSClient struct {
+	GetContainersStub        func(hcsshim.ComputeSystemQuery) ([]hcsshim.ContainerProperties, error)
+	getContainersMutex       sync.RWMutex
+	getContainersArgsForCall []struct {
+		arg1 hcsshim.ComputeSystemQuery
+	}
+	getContainersReturns struct {
+		result1 []hcsshim.ContainerProperties
+		result2 error
+	}
+	getContainersReturnsOnCall map[int]struct {
+		result1 []hcsshim.ContainerProperties
+		result2 error
+	}
+	HNSListEndpointRequestStub        func() ([]hcsshim.HNSEndpoint, error)
+	hNSListEndpointRequestMutex       sync.RWMutex
+	hNSListEndpointRequestArgsForCall []struct {
+	}
+	hNSListEndpointRequestReturns struct {
+		result1 []hcsshim.HNSEndpoint
+		result2 error
+	}
+	hNSListEndpointRequestReturnsOnCall map[int]struct {
+		result1 []hcsshim.HNSEndpoint
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *HCSClient) GetContainers(arg1 hcsshim.ComputeSystemQuery) ([]hcsshim.ContainerProperties, error) {
+	fake.getContainersMutex.Lock()
+	ret, specificReturn := fake.getContainersReturnsOnCall[len(fake.getContainersArgsForCall)]
+	fake.getContainersArgsForCall = append(fake.getContainersArgsForCall, struct {
+		arg1 hcsshim.ComputeSystemQuery
+	}{arg1})
+	stub := fake.GetContainersStub
+	fakeReturns := fake.getContainersReturns
+	fake.recordInvocation("GetContainers", []interface{}{arg1})
+	fake.getContainersMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *HCSClient) GetContainersCallCount() int {
+	fake.getContainersMutex.RLock()
+	defer fake.getContainersMutex.RUnlock()
+	return len(fake.getContainersArgsForCall)
+}
+
+func (fake *HCSClient) GetContainersCalls(stub func(hcsshim.ComputeSystemQuery) ([]hcsshim.ContainerProperties, error)) {
+	fake.getContainersMutex.Lock()
+	defer fake.getContainersMutex.Unlock()
+	fake.GetContainersStub = stub
+}
+
+func (fake *HCSClient) GetContainersArgsForCall(i int) hcsshim.ComputeSystemQuery {
+	fake.getContainersMutex.RLock()
+	defer fake.getContainersMutex.RUnlock()
+	argsForCall := fake.getContainersArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *HCSClient) GetContainersReturns(result1 []hcsshim.ContainerProperties, result2 error) {
+	fake.getContainersMutex.Lock()
+	defer fake.getContainersMutex.Unlock()
+	fake.GetContainersStub = nil
+	fake.getContainersReturns = struct {
+		result1 []hcsshim.ContainerProperties
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *HCSClient) GetContainersReturnsOnCall(i int, result1 []hcsshim.ContainerProperties, result2 error) {
+	fake.getContainersMutex.Lock()
+	defer fake.getContainersMutex.Unlock()
+	fake.GetContainersStub = nil
+	if fake.getContainersReturnsOnCall == nil {
+		fake.getContainersReturnsOnCall = make(map[int]struct {
+			result1 []hcsshim.ContainerProperties
+			result2 error
+		})
+	}
+	fake.getContainersReturnsOnCall[i] = struct {
+		result1 []hcsshim.ContainerProperties
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *HCSClient) HNSListEndpointRequest() ([]hcsshim.HNSEndpoint, error) {
+	fake.hNSListEndpointRequestMutex.Lock()
+	ret, specificReturn := fake.hNSListEndpointRequestReturnsOnCall[len(fake.hNSListEndpointRequestArgsForCall)]
+	fake.hNSListEndpointRequestArgsForCall = append(fake.hNSListEndpointRequestArgsForCall, struct {
+	}{})
+	stub := fake.HNSListEndpointRequestStub
+	fakeReturns := fake.hNSListEndpointRequestReturns
+	fake.recordInvocation("HNSListEndpointRequest", []interface{}{})
+	fake.hNSListEndpointRequestMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *HCSClient) HNSListEndpointRequestCallCount() int {
+	fake.hNSListEndpointRequestMutex.RLock()
+	defer fake.hNSListEndpointRequestMutex.RUnlock()
+	return len(fake.hNSListEndpointRequestArgsForCall)
+}
+
+func (fake *HCSClient) HNSListEndpointRequestCalls(stub func() ([]hcsshim.HNSEndpoint, error)) {
+	fake.hNSListEndpointRequestMutex.Lock()
+	defer fake.hNSListEndpointRequestMutex.Unlock()
+	fake.HNSListEndpointRequestStub = stub
+}
+
+func (fake *HCSClient) HNSListEndpointRequestReturns(result1 []hcsshim.HNSEndpoint, result2 error) {
+	fake.hNSListEndpointRequestMutex.Lock()
+	defer fake.hNSListEndpointRequestMutex.Unlock()
+	fake.HNSListEndpointRequestStub = nil
+	fake.hNSListEndpointRequestReturns = struct {
+		result1 []hcsshim.HNSEndpoint
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *HCSClient) HNSListEndpointRequestReturnsOnCall(i int, result1 []hcsshim.HNSEndpoint, result2 error) {
+	fake.hNSListEndpointRequestMutex.Lock()
+	defer fake.hNSListEndpointRequestMutex.Unlock()
+	fake.HNSListEndpointRequestStub = nil
+	if fake.hNSListEndpointRequestReturnsOnCall == nil {
+		fake.hNSListEndpointRequestReturnsOnCall = make(map[int]struct {
+			result1 []hcsshim.HNSEndpoint
+			result2 error
+		})
+	}
+	fake.hNSListEndpointRequestReturnsOnCall[i] = struct {
+		result1 []hcsshim.HNSEndpoint
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *HCSClient) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.getContainersMutex.RLock()
+	defer fake.getContainersMutex.RUnlock()
+	fake.hNSListEndpointRequestMutex.RLock()
+	defer fake.hNSListEndpointRequestMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *HCSClient) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ reconciler.HCSClient = new(HCSClient)
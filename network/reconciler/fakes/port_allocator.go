@@ -0,0 +1,116 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	"sync"
+
+	"code.cloudfoundry.org/winc/network/reconciler"
+)
+
+type PortAllocator struct {
+	ReleaseStaleStub        func(func() (map[string]bool, error)) (map[string][]int, error)
+	releaseStaleMutex       sync.RWMutex
+	releaseStaleArgsForCall []struct {
+		arg1 func() (map[string]bool, error)
+	}
+	releaseStaleReturns struct {
+		result1 map[string][]int
+		result2 error
+	}
+	releaseStaleReturnsOnCall map[int]struct {
+		result1 map[string][]int
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *PortAllocator) ReleaseStale(arg1 func() (map[string]bool, error)) (map[string][]int, error) {
+	fake.releaseStaleMutex.Lock()
+	ret, specificReturn := fake.releaseStaleReturnsOnCall[len(fake.releaseStaleArgsForCall)]
+	fake.releaseStaleArgsForCall = append(fake.releaseStaleArgsForCall, struct {
+		arg1 func() (map[string]bool, error)
+	}{arg1})
+	stub := fake.ReleaseStaleStub
+	fakeReturns := fake.releaseStaleReturns
+	fake.recordInvocation("ReleaseStale", []interface{}{arg1})
+	fake.releaseStaleMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *PortAllocator) ReleaseStaleCallCount() int {
+	fake.releaseStaleMutex.RLock()
+	defer fake.releaseStaleMutex.RUnlock()
+	return len(fake.releaseStaleArgsForCall)
+}
+
+func (fake *PortAllocator) ReleaseStaleCalls(stub func(func() (map[string]bool, error)) (map[string][]int, error)) {
+	fake.releaseStaleMutex.Lock()
+	defer fake.releaseStaleMutex.Unlock()
+	fake.ReleaseStaleStub = stub
+}
+
+func (fake *PortAllocator) ReleaseStaleArgsForCall(i int) func() (map[string]bool, error) {
+	fake.releaseStaleMutex.RLock()
+	defer fake.releaseStaleMutex.RUnlock()
+	argsForCall := fake.releaseStaleArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *PortAllocator) ReleaseStaleReturns(result1 map[string][]int, result2 error) {
+	fake.releaseStaleMutex.Lock()
+	defer fake.releaseStaleMutex.Unlock()
+	fake.ReleaseStaleStub = nil
+	fake.releaseStaleReturns = struct {
+		result1 map[string][]int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PortAllocator) ReleaseStaleReturnsOnCall(i int, result1 map[string][]int, result2 error) {
+	fake.releaseStaleMutex.Lock()
+	defer fake.releaseStaleMutex.Unlock()
+	fake.ReleaseStaleStub = nil
+	if fake.releaseStaleReturnsOnCall == nil {
+		fake.releaseStaleReturnsOnCall = make(map[int]struct {
+			result1 map[string][]int
+			result2 error
+		})
+	}
+	fake.releaseStaleReturnsOnCall[i] = struct {
+		result1 map[string][]int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PortAllocator) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.releaseStaleMutex.RLock()
+	defer fake.releaseStaleMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *PortAllocator) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ reconciler.PortAllocator = new(PortAllocator)
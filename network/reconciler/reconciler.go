@@ -0,0 +1,72 @@
+package reconciler
+
+import (
+	"github.com/Microsoft/hcsshim"
+	"github.com/sirupsen/logrus"
+)
+
+//go:generate counterfeiter -o fakes/hcs_client.go --fake-name HCSClient . HCSClient
+type HCSClient interface {
+	HNSListEndpointRequest() ([]hcsshim.HNSEndpoint, error)
+	GetContainers(hcsshim.ComputeSystemQuery) ([]hcsshim.ContainerProperties, error)
+}
+
+//go:generate counterfeiter -o fakes/port_allocator.go --fake-name PortAllocator . PortAllocator
+type PortAllocator interface {
+	ReleaseStale(func() (map[string]bool, error)) (map[string][]int, error)
+}
+
+type Report struct {
+	ReleasedPorts map[string][]int `json:"released_ports"`
+}
+
+// Reconciler releases the ports of containers whose network was never torn
+// down, e.g. because the host rebooted before `down` ran
+type Reconciler struct {
+	hcsClient     HCSClient
+	portAllocator PortAllocator
+}
+
+func New(hcsClient HCSClient, portAllocator PortAllocator) *Reconciler {
+	return &Reconciler{
+		hcsClient:     hcsClient,
+		portAllocator: portAllocator,
+	}
+}
+
+// Reconcile releases the ports held by handles that have neither an HNS
+// endpoint nor a compute system
+func (r *Reconciler) Reconcile() (Report, error) {
+	released, err := r.portAllocator.ReleaseStale(r.liveHandles)
+	if err != nil {
+		return Report{}, err
+	}
+
+	for handle, ports := range released {
+		logrus.Infof("released ports %v of stale handle %s", ports, handle)
+	}
+
+	return Report{ReleasedPorts: released}, nil
+}
+
+func (r *Reconciler) liveHandles() (map[string]bool, error) {
+	endpoints, err := r.hcsClient.HNSListEndpointRequest()
+	if err != nil {
+		return nil, err
+	}
+
+	containers, err := r.hcsClient.GetContainers(hcsshim.ComputeSystemQuery{})
+	if err != nil {
+		return nil, err
+	}
+
+	live := map[string]bool{}
+	for _, endpoint := range endpoints {
+		live[endpoint.Name] = true
+	}
+	for _, container := range containers {
+		live[container.ID] = true
+	}
+
+	return live, nil
+}
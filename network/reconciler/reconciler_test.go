@@ -0,0 +1,91 @@
+package reconciler_test
+
+import (
+	"errors"
+	"io/ioutil"
+
+	"code.cloudfoundry.org/winc/network/reconciler"
+	"code.cloudfoundry.org/winc/network/reconciler/fakes"
+	"github.com/Microsoft/hcsshim"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/sirupsen/logrus"
+)
+
+var _ = Describe("Reconciler", func() {
+	var (
+		hcsClient     *fakes.HCSClient
+		portAllocator *fakes.PortAllocator
+		r             *reconciler.Reconciler
+	)
+
+	BeforeEach(func() {
+		hcsClient = &fakes.HCSClient{}
+		portAllocator = &fakes.PortAllocator{}
+		r = reconciler.New(hcsClient, portAllocator)
+
+		hcsClient.HNSListEndpointRequestReturns([]hcsshim.HNSEndpoint{{Name: "endpoint-handle"}}, nil)
+		hcsClient.GetContainersReturns([]hcsshim.ContainerProperties{{ID: "container-handle"}}, nil)
+		portAllocator.ReleaseStaleReturns(map[string][]int{"stale-handle": {40000, 40001}}, nil)
+
+		logrus.SetOutput(ioutil.Discard)
+	})
+
+	It("reports the ports released for stale handles", func() {
+		report, err := r.Reconcile()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report).To(Equal(reconciler.Report{ReleasedPorts: map[string][]int{"stale-handle": {40000, 40001}}}))
+	})
+
+	It("treats handles with an endpoint or a compute system as live", func() {
+		_, err := r.Reconcile()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(portAllocator.ReleaseStaleCallCount()).To(Equal(1))
+		liveHandles := portAllocator.ReleaseStaleArgsForCall(0)
+
+		live, err := liveHandles()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(live).To(Equal(map[string]bool{"endpoint-handle": true, "container-handle": true}))
+		Expect(hcsClient.GetContainersArgsForCall(0)).To(Equal(hcsshim.ComputeSystemQuery{}))
+	})
+
+	Context("listing the endpoints fails", func() {
+		BeforeEach(func() {
+			hcsClient.HNSListEndpointRequestReturns(nil, errors.New("cannot list endpoints"))
+		})
+
+		It("fails the live handle lookup", func() {
+			_, err := r.Reconcile()
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = portAllocator.ReleaseStaleArgsForCall(0)()
+			Expect(err).To(MatchError("cannot list endpoints"))
+		})
+	})
+
+	Context("listing the compute systems fails", func() {
+		BeforeEach(func() {
+			hcsClient.GetContainersReturns(nil, errors.New("cannot list containers"))
+		})
+
+		It("fails the live handle lookup", func() {
+			_, err := r.Reconcile()
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = portAllocator.ReleaseStaleArgsForCall(0)()
+			Expect(err).To(MatchError("cannot list containers"))
+		})
+	})
+
+	Context("releasing the stale ports fails", func() {
+		BeforeEach(func() {
+			portAllocator.ReleaseStaleReturns(nil, errors.New("cannot release"))
+		})
+
+		It("returns the error", func() {
+			_, err := r.Reconcile()
+			Expect(err).To(MatchError("cannot release"))
+		})
+	})
+})
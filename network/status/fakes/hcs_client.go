@@ -0,0 +1,178 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	"sync"
+
+	"code.cloudfoundry.org/winc/network/status"
+	"github.com/Microsoft/hcsshim"
+)
+
+type HCSClient struct {
+	HNSListEndpointRequestStub        func() ([]hcsshim.HNSEndpoint, error)
+	hNSListEndpointRequestMutex       sync.RWMutex
+	hNSListEndpointRequestArgsForCall []struct {
+	}
+	hNSListEndpointRequestReturns struct {
+		result1 []hcsshim.HNSEndpoint
+		result2 error
+	}
+	hNSListEndpointRequestReturnsOnCall map[int]struct {
+		result1 []hcsshim.HNSEndpoint
+		result2 error
+	}
+	HNSListNetworkRequestStub        func() ([]hcsshim.HNSNetwork, error)
+	hNSListNetworkRequestMutex       sync.RWMutex
+	hNSListNetworkRequestArgsForCall []struct {
+	}
+	hNSListNetworkRequestReturns struct {
+		result1 []hcsshim.HNSNetwork
+		result2 error
+	}
+	hNSListNetworkRequestReturnsOnCall map[int]struct {
+		result1 []hcsshim.HNSNetwork
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *HCSClient) HNSListEndpointRequest() ([]hcsshim.HNSEndpoint, error) {
+	fake.hNSListEndpointRequestMutex.Lock()
+	ret, specificReturn := fake.hNSListEndpointRequestReturnsOnCall[len(fake.hNSListEndpointRequestArgsForCall)]
+	fake.hNSListEndpointRequestArgsForCall = append(fake.hNSListEndpointRequestArgsForCall, struct {
+	}{})
+	stub := fake.HNSListEndpointRequestStub
+	fakeReturns := fake.hNSListEndpointRequestReturns
+	fake.recordInvocation("HNSListEndpointRequest", []interface{}{})
+	fake.hNSListEndpointRequestMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *HCSClient) HNSListEndpointRequestCallCount() int {
+	fake.hNSListEndpointRequestMutex.RLock()
+	defer fake.hNSListEndpointRequestMutex.RUnlock()
+	return len(fake.hNSListEndpointRequestArgsForCall)
+}
+
+func (fake *HCSClient) HNSListEndpointRequestCalls(stub func() ([]hcsshim.HNSEndpoint, error)) {
+	fake.hNSListEndpointRequestMutex.Lock()
+	defer fake.hNSListEndpointRequestMutex.Unlock()
+	fake.HNSListEndpointRequestStub = stub
+}
+
+func (fake *HCSClient) HNSListEndpointRequestReturns(result1 []hcsshim.HNSEndpoint, result2 error) {
+	fake.hNSListEndpointRequestMutex.Lock()
+	defer fake.hNSListEndpointRequestMutex.Unlock()
+	fake.HNSListEndpointRequestStub = nil
+	fake.hNSListEndpointRequestReturns = struct {
+		result1 []hcsshim.HNSEndpoint
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *HCSClient) HNSListEndpointRequestReturnsOnCall(i int, result1 []hcsshim.HNSEndpoint, result2 error) {
+	fake.hNSListEndpointRequestMutex.Lock()
+	defer fake.hNSListEndpointRequestMutex.Unlock()
+	fake.HNSListEndpointRequestStub = nil
+	if fake.hNSListEndpointRequestReturnsOnCall == nil {
+		fake.hNSListEndpointRequestReturnsOnCall = make(map[int]struct {
+			result1 []hcsshim.HNSEndpoint
+			result2 error
+		})
+	}
+	fake.hNSListEndpointRequestReturnsOnCall[i] = struct {
+		result1 []hcsshim.HNSEndpoint
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *HCSClient) HNSListNetworkRequest() ([]hcsshim.HNSNetwork, error) {
+	fake.hNSListNetworkRequestMutex.Lock()
+	ret, specificReturn := fake.hNSListNetworkRequestReturnsOnCall[len(fake.hNSListNetworkRequestArgsForCall)]
+	fake.hNSListNetworkRequestArgsForCall = append(fake.hNSListNetworkRequestArgsForCall, struct {
+	}{})
+	stub := fake.HNSListNetworkRequestStub
+	fakeReturns := fake.hNSListNetworkRequestReturns
+	fake.recordInvocation("HNSListNetworkRequest", []interface{}{})
+	fake.hNSListNetworkRequestMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *HCSClient) HNSListNetworkRequestCallCount() int {
+	fake.hNSListNetworkRequestMutex.RLock()
+	defer fake.hNSListNetworkRequestMutex.RUnlock()
+	return len(fake.hNSListNetworkRequestArgsForCall)
+}
+
+func (fake *HCSClient) HNSListNetworkRequestCalls(stub func() ([]hcsshim.HNSNetwork, error)) {
+	fake.hNSListNetworkRequestMutex.Lock()
+	defer fake.hNSListNetworkRequestMutex.Unlock()
+	fake.HNSListNetworkRequestStub = stub
+}
+
+func (fake *HCSClient) HNSListNetworkRequestReturns(result1 []hcsshim.HNSNetwork, result2 error) {
+	fake.hNSListNetworkRequestMutex.Lock()
+	defer fake.hNSListNetworkRequestMutex.Unlock()
+	fake.HNSListNetworkRequestStub = nil
+	fake.hNSListNetworkRequestReturns = struct {
+		result1 []hcsshim.HNSNetwork
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *HCSClient) HNSListNetworkRequestReturnsOnCall(i int, result1 []hcsshim.HNSNetwork, result2 error) {
+	fake.hNSListNetworkRequestMutex.Lock()
+	defer fake.hNSListNetworkRequestMutex.Unlock()
+	fake.HNSListNetworkRequestStub = nil
+	if fake.hNSListNetworkRequestReturnsOnCall == nil {
+		fake.hNSListNetworkRequestReturnsOnCall = make(map[int]struct {
+			result1 []hcsshim.HNSNetwork
+			result2 error
+		})
+	}
+	fake.hNSListNetworkRequestReturnsOnCall[i] = struct {
+		result1 []hcsshim.HNSNetwork
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *HCSClient) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.hNSListEndpointRequestMutex.RLock()
+	defer fake.hNSListEndpointRequestMutex.RUnlock()
+	fake.hNSListNetworkRequestMutex.RLock()
+	defer fake.hNSListNetworkRequestMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *HCSClient) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ status.HCSClient = new(HCSClient)
@@ -0,0 +1,108 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	"sync"
+
+	"code.cloudfoundry.org/winc/network/port_allocator"
+	"code.cloudfoundry.org/winc/network/status"
+)
+
+type PortAllocator struct {
+	PoolStub        func() (port_allocator.Pool, error)
+	poolMutex       sync.RWMutex
+	poolArgsForCall []struct {
+	}
+	poolReturns struct {
+		result1 port_allocator.Pool
+		result2 error
+	}
+	poolReturnsOnCall map[int]struct {
+		result1 port_allocator.Pool
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *PortAllocator) Pool() (port_allocator.Pool, error) {
+	fake.poolMutex.Lock()
+	ret, specificReturn := fake.poolReturnsOnCall[len(fake.poolArgsForCall)]
+	fake.poolArgsForCall = append(fake.poolArgsForCall, struct {
+	}{})
+	stub := fake.PoolStub
+	fakeReturns := fake.poolReturns
+	fake.recordInvocation("Pool", []interface{}{})
+	fake.poolMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *PortAllocator) PoolCallCount() int {
+	fake.poolMutex.RLock()
+	defer fake.poolMutex.RUnlock()
+	return len(fake.poolArgsForCall)
+}
+
+func (fake *PortAllocator) PoolCalls(stub func() (port_allocator.Pool, error)) {
+	fake.poolMutex.Lock()
+	defer fake.poolMutex.Unlock()
+	fake.PoolStub = stub
+}
+
+func (fake *PortAllocator) PoolReturns(result1 port_allocator.Pool, result2 error) {
+	fake.poolMutex.Lock()
+	defer fake.poolMutex.Unlock()
+	fake.PoolStub = nil
+	fake.poolReturns = struct {
+		result1 port_allocator.Pool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PortAllocator) PoolReturnsOnCall(i int, result1 port_allocator.Pool, result2 error) {
+	fake.poolMutex.Lock()
+	defer fake.poolMutex.Unlock()
+	fake.PoolStub = nil
+	if fake.poolReturnsOnCall == nil {
+		fake.poolReturnsOnCall = make(map[int]struct {
+			result1 port_allocator.Pool
+			result2 error
+		})
+	}
+	fake.poolReturnsOnCall[i] = struct {
+		result1 port_allocator.Pool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PortAllocator) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.poolMutex.RLock()
+	defer fake.poolMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *PortAllocator) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ status.PortAllocator = new(PortAllocator)
@@ -0,0 +1,140 @@
+package status
+
+import (
+	"sort"
+
+	"code.cloudfoundry.org/winc/network/port_allocator"
+	"github.com/Microsoft/hcsshim"
+)
+
+//go:generate counterfeiter -o fakes/hcs_client.go --fake-name HCSClient . HCSClient
+type HCSClient interface {
+	HNSListNetworkRequest() ([]hcsshim.HNSNetwork, error)
+	HNSListEndpointRequest() ([]hcsshim.HNSEndpoint, error)
+}
+
+//go:generate counterfeiter -o fakes/port_allocator.go --fake-name PortAllocator . PortAllocator
+type PortAllocator interface {
+	Pool() (port_allocator.Pool, error)
+}
+
+type Status struct {
+	PortRange        PortRange        `json:"port_range"`
+	AcquiredPorts    int              `json:"acquired_ports"`
+	QuarantinedPorts int              `json:"quarantined_ports"`
+	Allocations      map[string][]int `json:"allocations"`
+	Networks         []Network        `json:"networks"`
+}
+
+type PortRange struct {
+	Start int `json:"start"`
+	Size  int `json:"size"`
+}
+
+type Network struct {
+	Name          string   `json:"name"`
+	Subnets       []Subnet `json:"subnets"`
+	EndpointCount int      `json:"endpoint_count"`
+}
+
+type Subnet struct {
+	AddressPrefix  string `json:"address_prefix"`
+	GatewayAddress string `json:"gateway_address"`
+}
+
+// Reporter describes how full the port pool is and the state of the
+// container networks
+type Reporter struct {
+	hcsClient     HCSClient
+	portAllocator PortAllocator
+	portRange     port_allocator.PortRange
+	networkNames  []string
+}
+
+func New(hcsClient HCSClient, portAllocator PortAllocator, portRange port_allocator.PortRange, networkNames []string) *Reporter {
+	return &Reporter{
+		hcsClient:     hcsClient,
+		portAllocator: portAllocator,
+		portRange:     portRange,
+		networkNames:  networkNames,
+	}
+}
+
+// Status reports the port pool and the configured networks, in the order
+// they are configured. Networks that have not been created are left out.
+func (r *Reporter) Status() (Status, error) {
+	pool, err := r.portAllocator.Pool()
+	if err != nil {
+		return Status{}, err
+	}
+
+	status := Status{
+		PortRange:        PortRange{Start: r.portRange.Start, Size: r.portRange.Size},
+		AcquiredPorts:    len(pool.AcquiredPorts),
+		QuarantinedPorts: len(pool.ReleasedPorts),
+		Allocations:      map[string][]int{},
+	}
+
+	for port, handle := range pool.AcquiredPorts {
+		status.Allocations[handle] = append(status.Allocations[handle], port)
+	}
+	for _, ports := range status.Allocations {
+		sort.Ints(ports)
+	}
+
+	status.Networks, err = r.networks()
+	if err != nil {
+		return Status{}, err
+	}
+
+	return status, nil
+}
+
+func (r *Reporter) networks() ([]Network, error) {
+	hnsNetworks, err := r.hcsClient.HNSListNetworkRequest()
+	if err != nil {
+		return nil, err
+	}
+
+	byName := map[string]hcsshim.HNSNetwork{}
+	for _, n := range hnsNetworks {
+		byName[n.Name] = n
+	}
+
+	created := []hcsshim.HNSNetwork{}
+	for _, name := range r.networkNames {
+		if n, ok := byName[name]; ok {
+			created = append(created, n)
+		}
+	}
+
+	networks := []Network{}
+	if len(created) == 0 {
+		return networks, nil
+	}
+
+	endpoints, err := r.hcsClient.HNSListEndpointRequest()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, n := range created {
+		network := Network{Name: n.Name, Subnets: []Subnet{}}
+		for _, subnet := range n.Subnets {
+			network.Subnets = append(network.Subnets, Subnet{
+				AddressPrefix:  subnet.AddressPrefix,
+				GatewayAddress: subnet.GatewayAddress,
+			})
+		}
+
+		for _, endpoint := range endpoints {
+			if endpoint.VirtualNetwork == n.Id {
+				network.EndpointCount++
+			}
+		}
+
+		networks = append(networks, network)
+	}
+
+	return networks, nil
+}
@@ -0,0 +1,157 @@
+package status_test
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"code.cloudfoundry.org/winc/network/port_allocator"
+	"code.cloudfoundry.org/winc/network/status"
+	"code.cloudfoundry.org/winc/network/status/fakes"
+	"github.com/Microsoft/hcsshim"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Reporter", func() {
+	var (
+		hcsClient     *fakes.HCSClient
+		portAllocator *fakes.PortAllocator
+		reporter      *status.Reporter
+	)
+
+	BeforeEach(func() {
+		hcsClient = &fakes.HCSClient{}
+		portAllocator = &fakes.PortAllocator{}
+		reporter = status.New(hcsClient, portAllocator, port_allocator.PortRange{Start: 40000, Size: 5000}, []string{"winc-nat", "winc-transparent"})
+
+		portAllocator.PoolReturns(port_allocator.Pool{
+			AcquiredPorts: map[int]string{40002: "handle-1", 40000: "handle-1", 40001: "handle-2"},
+			ReleasedPorts: map[int]time.Time{40003: time.Now()},
+		}, nil)
+
+		hcsClient.HNSListNetworkRequestReturns([]hcsshim.HNSNetwork{
+			{Id: "other-id", Name: "other-network"},
+			{Id: "transparent-id", Name: "winc-transparent"},
+			{Id: "network-id", Name: "winc-nat", Subnets: []hcsshim.Subnet{{AddressPrefix: "172.30.0.0/22", GatewayAddress: "172.30.0.1"}}},
+		}, nil)
+		hcsClient.HNSListEndpointRequestReturns([]hcsshim.HNSEndpoint{
+			{Name: "handle-1", VirtualNetwork: "network-id"},
+			{Name: "handle-2", VirtualNetwork: "network-id"},
+			{Name: "handle-3", VirtualNetwork: "transparent-id"},
+			{Name: "other", VirtualNetwork: "other-id"},
+		}, nil)
+	})
+
+	It("reports the port pool and the networks", func() {
+		s, err := reporter.Status()
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(s).To(Equal(status.Status{
+			PortRange:        status.PortRange{Start: 40000, Size: 5000},
+			AcquiredPorts:    3,
+			QuarantinedPorts: 1,
+			Allocations:      map[string][]int{"handle-1": {40000, 40002}, "handle-2": {40001}},
+			Networks: []status.Network{
+				{
+					Name:          "winc-nat",
+					Subnets:       []status.Subnet{{AddressPrefix: "172.30.0.0/22", GatewayAddress: "172.30.0.1"}},
+					EndpointCount: 2,
+				},
+				{
+					Name:          "winc-transparent",
+					Subnets:       []status.Subnet{},
+					EndpointCount: 1,
+				},
+			},
+		}))
+	})
+
+	It("marshals to JSON", func() {
+		s, err := reporter.Status()
+		Expect(err).NotTo(HaveOccurred())
+
+		bytes, err := json.Marshal(s)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(bytes).To(MatchJSON(`{
+			"port_range": {"start": 40000, "size": 5000},
+			"acquired_ports": 3,
+			"quarantined_ports": 1,
+			"allocations": {"handle-1": [40000, 40002], "handle-2": [40001]},
+			"networks": [
+				{
+					"name": "winc-nat",
+					"subnets": [{"address_prefix": "172.30.0.0/22", "gateway_address": "172.30.0.1"}],
+					"endpoint_count": 2
+				},
+				{
+					"name": "winc-transparent",
+					"subnets": [],
+					"endpoint_count": 1
+				}
+			]
+		}`))
+	})
+
+	Context("a configured network does not exist", func() {
+		BeforeEach(func() {
+			hcsClient.HNSListNetworkRequestReturns([]hcsshim.HNSNetwork{
+				{Id: "other-id", Name: "other-network"},
+				{Id: "transparent-id", Name: "winc-transparent"},
+			}, nil)
+		})
+
+		It("reports only the networks that exist", func() {
+			s, err := reporter.Status()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s.Networks).To(Equal([]status.Network{{Name: "winc-transparent", Subnets: []status.Subnet{}, EndpointCount: 1}}))
+		})
+	})
+
+	Context("no configured network exists", func() {
+		BeforeEach(func() {
+			hcsClient.HNSListNetworkRequestReturns([]hcsshim.HNSNetwork{{Id: "other-id", Name: "other-network"}}, nil)
+		})
+
+		It("reports no networks", func() {
+			s, err := reporter.Status()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s.Networks).To(BeEmpty())
+			Expect(s.AcquiredPorts).To(Equal(3))
+			Expect(hcsClient.HNSListEndpointRequestCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("reading the pool fails", func() {
+		BeforeEach(func() {
+			portAllocator.PoolReturns(port_allocator.Pool{}, errors.New("decoding state file: potato"))
+		})
+
+		It("returns the error", func() {
+			_, err := reporter.Status()
+			Expect(err).To(MatchError("decoding state file: potato"))
+		})
+	})
+
+	Context("listing the networks fails", func() {
+		BeforeEach(func() {
+			hcsClient.HNSListNetworkRequestReturns(nil, errors.New("cannot list networks"))
+		})
+
+		It("returns the error", func() {
+			_, err := reporter.Status()
+			Expect(err).To(MatchError("cannot list networks"))
+		})
+	})
+
+	Context("listing the endpoints fails", func() {
+		BeforeEach(func() {
+			hcsClient.HNSListEndpointRequestReturns(nil, errors.New("cannot list endpoints"))
+		})
+
+		It("returns the error", func() {
+			_, err := reporter.Status()
+			Expect(err).To(MatchError("cannot list endpoints"))
+		})
+	})
+})
@@ -0,0 +1,235 @@
+package network
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"strings"
+
+	"code.cloudfoundry.org/winc/network/netrules"
+)
+
+// Validate checks every NetIn and NetOut rule, and the bandwidth limits
+// against the network's maximum, and returns all problems found as a single
+// UpInputsValidationError
+func (u *UpInputs) Validate(maximumOutgoingBandwidth uint64) error {
+	var errs []RuleValidationError
+
+	limits, err := u.BandwidthLimits()
+	if err == nil && limits != nil {
+		err = limits.Validate(maximumOutgoingBandwidth)
+	}
+	if err != nil {
+		errs = append(errs, RuleValidationError{Rule: "bandwidth_limits", Message: err.Error()})
+	}
+
+	if u.IP != "" && net.ParseIP(u.IP) == nil {
+		errs = append(errs, RuleValidationError{Rule: "ip", Message: fmt.Sprintf("%s is not an ip address", u.IP)})
+	}
+
+	for i, server := range u.DNSServers {
+		if net.ParseIP(server) == nil {
+			errs = append(errs, RuleValidationError{Rule: fmt.Sprintf("dns_servers[%d]", i), Message: fmt.Sprintf("%s is not an ip address", server)})
+		}
+	}
+
+	for i, domain := range u.SearchDomains {
+		// HNS takes the search domains as a single comma separated list
+		if domain == "" || strings.ContainsAny(domain, ", ") {
+			errs = append(errs, RuleValidationError{Rule: fmt.Sprintf("search_domains[%d]", i), Message: fmt.Sprintf("invalid search domain %q", domain)})
+		}
+	}
+
+	if len(u.DNSOptions) > 0 {
+		errs = append(errs, RuleValidationError{Rule: "dns_options", Message: "dns options are not supported by HNS"})
+	}
+
+	for i, rule := range u.NetIn {
+		errs = append(errs, validateNetIn(fmt.Sprintf("netin[%d]", i), rule)...)
+	}
+
+	for i, rule := range u.NetOut {
+		errs = append(errs, validateContainerNetOut(fmt.Sprintf("netout_rules[%d]", i), rule)...)
+	}
+
+	return validationError(errs)
+}
+
+// Validate checks the operator's default and deny rules. Default rules are
+// treated like container rules, while deny rules must be evaluated before
+// any container rule
+func (c *Config) Validate() error {
+	if err := c.validateNetworks(); err != nil {
+		return err
+	}
+
+	var errs []RuleValidationError
+
+	for i, rule := range c.NetOutDefaultRules {
+		name := fmt.Sprintf("netout_default_rules[%d]", i)
+		errs = append(errs, validateContainerNetOut(name, rule)...)
+
+		if rule.IsDeny() {
+			errs = append(errs, RuleValidationError{Rule: name, Message: "deny rules belong in netout_deny_rules"})
+		}
+	}
+
+	for i, rule := range c.NetOutDenyRules {
+		name := fmt.Sprintf("netout_deny_rules[%d]", i)
+		errs = append(errs, validateNetOut(name, rule)...)
+
+		if rule.Action != "" && rule.Action != netrules.NetOutActionDeny {
+			errs = append(errs, RuleValidationError{Rule: name, Message: fmt.Sprintf("action must be %s", netrules.NetOutActionDeny)})
+		}
+
+		if rule.Priority > netrules.OperatorDenyPriority {
+			errs = append(errs, RuleValidationError{Rule: name, Message: fmt.Sprintf("priority %d must not be greater than %d", rule.Priority, netrules.OperatorDenyPriority)})
+		}
+	}
+
+	return validationError(errs)
+}
+
+func (c *Config) validateNetwork() *NetworkConfigError {
+	switch c.NetworkType {
+	case "", NetworkTypeNAT, NetworkTypeTransparent, NetworkTypeL2Bridge:
+	default:
+		return &NetworkConfigError{Field: "network_type", Message: fmt.Sprintf("unsupported network type %s", c.NetworkType)}
+	}
+
+	switch c.IPAssignment {
+	case "", IPAssignmentStatic:
+	case IPAssignmentDHCP:
+		if c.NetworkType != NetworkTypeTransparent {
+			return &NetworkConfigError{Field: "ip_assignment", Message: "dhcp is only supported on transparent networks"}
+		}
+	default:
+		return &NetworkConfigError{Field: "ip_assignment", Message: fmt.Sprintf("unsupported ip assignment %s", c.IPAssignment)}
+	}
+
+	if c.VLANID != 0 && c.IsNAT() {
+		return &NetworkConfigError{Field: "vlan_id", Message: "vlans are not supported on nat networks"}
+	}
+
+	if c.VLANID > 4094 {
+		return &NetworkConfigError{Field: "vlan_id", Message: fmt.Sprintf("vlan %d is out of range", c.VLANID)}
+	}
+
+	return nil
+}
+
+func validationError(errs []RuleValidationError) error {
+	if len(errs) > 0 {
+		return &UpInputsValidationError{Errors: errs}
+	}
+	return nil
+}
+
+func validateNetIn(name string, rule netrules.NetIn) []RuleValidationError {
+	var errs []RuleValidationError
+	invalid := func(format string, args ...interface{}) {
+		errs = append(errs, RuleValidationError{Rule: name, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if rule.ContainerPort == 0 {
+		invalid("container port must not be zero")
+	}
+
+	if rule.ContainerPort > 65535 {
+		invalid("container port %d is out of range", rule.ContainerPort)
+	}
+
+	if rule.ContainerPortEnd > 65535 {
+		invalid("container port end %d is out of range", rule.ContainerPortEnd)
+	}
+
+	if rule.HostPort > 65535 {
+		invalid("host port %d is out of range", rule.HostPort)
+	}
+
+	if _, err := rule.Protocol.Protocols(); err != nil {
+		invalid("%s", err)
+	}
+
+	if _, err := rule.PortCount(); err != nil {
+		invalid("%s", err)
+	}
+
+	return errs
+}
+
+// validateContainerNetOut also makes sure a container rule cannot take
+// precedence over the operator's deny rules
+func validateContainerNetOut(name string, rule netrules.NetOut) []RuleValidationError {
+	errs := validateNetOut(name, rule)
+
+	if rule.Priority != 0 && rule.Priority <= netrules.OperatorDenyPriority {
+		errs = append(errs, RuleValidationError{Rule: name, Message: fmt.Sprintf("priority %d must be greater than %d", rule.Priority, netrules.OperatorDenyPriority)})
+	}
+
+	return errs
+}
+
+func validateNetOut(name string, rule netrules.NetOut) []RuleValidationError {
+	var errs []RuleValidationError
+	invalid := func(format string, args ...interface{}) {
+		errs = append(errs, RuleValidationError{Rule: name, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if rule.Protocol > netrules.ProtocolICMP {
+		invalid("invalid protocol: %d", rule.Protocol)
+	}
+
+	if rule.Protocol == netrules.ProtocolICMP && len(rule.Ports) > 0 {
+		invalid("ports must not be specified for icmp rules")
+	}
+
+	if rule.Protocol == netrules.ProtocolICMP && mixesIPFamilies(rule.Networks) {
+		invalid("icmp rules must not mix IPv4 and IPv6 networks")
+	}
+
+	for i, ipr := range rule.Networks {
+		if err := ipr.Validate(); err != nil {
+			invalid("networks[%d]: %s", i, err)
+			continue
+		}
+
+		if bytes.Compare(ipr.Start.To16(), ipr.End.To16()) > 0 {
+			invalid("networks[%d]: start %s is after end %s", i, ipr.Start, ipr.End)
+		}
+	}
+
+	for i, pr := range rule.Ports {
+		if pr.Start > pr.End {
+			invalid("ports[%d]: start %d is greater than end %d", i, pr.Start, pr.End)
+		}
+	}
+
+	switch rule.Action {
+	case "", netrules.NetOutActionAllow, netrules.NetOutActionDeny:
+	default:
+		invalid("invalid action: %s", rule.Action)
+	}
+
+	if rule.Priority > netrules.MaxPriority {
+		invalid("priority %d is out of range", rule.Priority)
+	}
+
+	return errs
+}
+
+func mixesIPFamilies(networks []netrules.IPRange) bool {
+	var v4, v6 bool
+	for _, ipr := range networks {
+		if ipr.Start == nil {
+			continue
+		}
+
+		if ipr.Start.To4() != nil {
+			v4 = true
+		} else {
+			v6 = true
+		}
+	}
+	return v4 && v6
+}
@@ -0,0 +1,241 @@
+package network_test
+
+import (
+	"net"
+
+	"code.cloudfoundry.org/winc/network"
+	"code.cloudfoundry.org/winc/network/netrules"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("UpInputs", func() {
+	Describe("Validate", func() {
+		var inputs network.UpInputs
+
+		BeforeEach(func() {
+			inputs = network.UpInputs{
+				NetIn: []netrules.NetIn{
+					{ContainerPort: 8080},
+					{HostPort: 2000, ContainerPort: 3000, ContainerPortEnd: 3010, Protocol: netrules.NetInProtocolBoth},
+				},
+				NetOut: []netrules.NetOut{
+					{Protocol: netrules.ProtocolAll},
+					{
+						Protocol: netrules.ProtocolTCP,
+						Networks: []netrules.IPRange{{Start: net.ParseIP("10.0.0.1"), End: net.ParseIP("10.0.0.255")}},
+						Ports:    []netrules.PortRange{{Start: 80, End: 443}},
+					},
+					{
+						Protocol: netrules.ProtocolICMP,
+						Networks: []netrules.IPRange{{Start: net.ParseIP("2001:db8::1"), End: net.ParseIP("2001:db8::1")}},
+					},
+				},
+			}
+		})
+
+		It("accepts valid rules", func() {
+			Expect(inputs.Validate(0)).To(Succeed())
+		})
+
+		It("accepts empty inputs", func() {
+			Expect((&network.UpInputs{}).Validate(0)).To(Succeed())
+		})
+
+		DescribeTable("invalid netin rules",
+			func(rule netrules.NetIn, message string) {
+				inputs.NetIn = append(inputs.NetIn, rule)
+				Expect(inputs.Validate(0)).To(MatchError(&network.UpInputsValidationError{Errors: []network.RuleValidationError{
+					{Rule: "netin[2]", Message: message},
+				}}))
+			},
+			Entry("zero container port", netrules.NetIn{HostPort: 80}, "container port must not be zero"),
+			Entry("container port out of range", netrules.NetIn{ContainerPort: 70000}, "container port 70000 is out of range"),
+			Entry("host port out of range", netrules.NetIn{HostPort: 70000, ContainerPort: 80}, "host port 70000 is out of range"),
+			Entry("unknown protocol", netrules.NetIn{ContainerPort: 80, Protocol: "sctp"}, "invalid protocol: sctp"),
+			Entry("reversed container port range", netrules.NetIn{ContainerPort: 90, ContainerPortEnd: 80}, "invalid container port range: 90-80"),
+		)
+
+		DescribeTable("invalid netout rules",
+			func(rule netrules.NetOut, message string) {
+				inputs.NetOut = append(inputs.NetOut, rule)
+				Expect(inputs.Validate(0)).To(MatchError(&network.UpInputsValidationError{Errors: []network.RuleValidationError{
+					{Rule: "netout_rules[3]", Message: message},
+				}}))
+			},
+			Entry("unknown protocol", netrules.NetOut{Protocol: 7}, "invalid protocol: 7"),
+			Entry("icmp with ports",
+				netrules.NetOut{Protocol: netrules.ProtocolICMP, Ports: []netrules.PortRange{{Start: 80, End: 80}}},
+				"ports must not be specified for icmp rules"),
+			Entry("icmp with both IPv4 and IPv6 networks",
+				netrules.NetOut{Protocol: netrules.ProtocolICMP, Networks: []netrules.IPRange{
+					{Start: net.ParseIP("10.0.0.1"), End: net.ParseIP("10.0.0.1")},
+					{Start: net.ParseIP("2001:db8::1"), End: net.ParseIP("2001:db8::1")},
+				}},
+				"icmp rules must not mix IPv4 and IPv6 networks"),
+			Entry("reversed ip range",
+				netrules.NetOut{Networks: []netrules.IPRange{{Start: net.ParseIP("10.0.0.2"), End: net.ParseIP("10.0.0.1")}}},
+				"networks[0]: start 10.0.0.2 is after end 10.0.0.1"),
+			Entry("reversed IPv6 range",
+				netrules.NetOut{Networks: []netrules.IPRange{{Start: net.ParseIP("2001:db8::2"), End: net.ParseIP("2001:db8::1")}}},
+				"networks[0]: start 2001:db8::2 is after end 2001:db8::1"),
+			Entry("mixed family ip range",
+				netrules.NetOut{Networks: []netrules.IPRange{{Start: net.ParseIP("10.0.0.1"), End: net.ParseIP("2001:db8::1")}}},
+				"networks[0]: invalid ip range 10.0.0.1-2001:db8::1: start and end must both be IPv4 or both be IPv6 addresses"),
+			Entry("reversed port range",
+				netrules.NetOut{Protocol: netrules.ProtocolUDP, Ports: []netrules.PortRange{{Start: 80, End: 80}, {Start: 90, End: 80}}},
+				"ports[1]: start 90 is greater than end 80"),
+			Entry("unknown action", netrules.NetOut{Action: "reject"}, "invalid action: reject"),
+			Entry("priority out of range", netrules.NetOut{Priority: 65501}, "priority 65501 is out of range"),
+			Entry("priority reserved for the operator",
+				netrules.NetOut{Action: netrules.NetOutActionDeny, Priority: 100},
+				"priority 100 must be greater than 100"),
+		)
+
+		It("accepts icmp rules with icmps, which are ignored", func() {
+			inputs.NetOut = append(inputs.NetOut, netrules.NetOut{Protocol: netrules.ProtocolICMP, ICMPs: &netrules.ICMPControl{Type: 8}})
+			Expect(inputs.Validate(0)).To(Succeed())
+		})
+
+		It("accepts deny rules with priorities", func() {
+			inputs.NetOut = append(inputs.NetOut, netrules.NetOut{Action: netrules.NetOutActionDeny, Priority: 101})
+			Expect(inputs.Validate(0)).To(Succeed())
+		})
+
+		It("rejects bandwidth limits that cannot be parsed", func() {
+			inputs.Properties = map[string]interface{}{"bandwidth_limits": map[string]interface{}{"rate": "fast"}}
+			err := inputs.Validate(0)
+			Expect(err).To(BeAssignableToTypeOf(&network.UpInputsValidationError{}))
+			Expect(err.Error()).To(ContainSubstring("bandwidth_limits: json: cannot unmarshal string"))
+		})
+
+		It("accepts bandwidth limits encoded as a string", func() {
+			inputs.Properties = map[string]interface{}{"bandwidth_limits": `{"rate":5000}`}
+			Expect(inputs.Validate(10000)).To(Succeed())
+		})
+
+		DescribeTable("invalid bandwidth limits",
+			func(limits interface{}, message string) {
+				inputs.Properties = map[string]interface{}{"bandwidth_limits": limits}
+				Expect(inputs.Validate(10000)).To(MatchError(&network.UpInputsValidationError{Errors: []network.RuleValidationError{
+					{Rule: "bandwidth_limits", Message: message},
+				}}))
+			},
+			Entry("rate above the maximum", map[string]interface{}{"rate": float64(20000)}, "bandwidth rate 20000 exceeds the maximum of 10000 bytes per second"),
+			Entry("encoded rate above the maximum", `{"rate":20000}`, "bandwidth rate 20000 exceeds the maximum of 10000 bytes per second"),
+			Entry("burst rate", map[string]interface{}{"rate": float64(5000), "burst": float64(6000)}, "burst rates are not supported by HNS QoS policies"),
+			Entry("ingress rate", map[string]interface{}{"ingress_rate": float64(5000)}, "unsupported limit ingress_rate: only the outgoing rate can be limited"),
+		)
+
+		It("rejects invalid dns servers and search domains", func() {
+			inputs.DNSServers = []string{"8.8.8.8", "dns.example.com"}
+			inputs.SearchDomains = []string{"apps.internal", "a,b"}
+			Expect(inputs.Validate(0)).To(MatchError(&network.UpInputsValidationError{Errors: []network.RuleValidationError{
+				{Rule: "dns_servers[1]", Message: "dns.example.com is not an ip address"},
+				{Rule: "search_domains[1]", Message: `invalid search domain "a,b"`},
+			}}))
+		})
+
+		It("rejects a requested ip that cannot be parsed", func() {
+			inputs.IP = "172.30.0"
+			Expect(inputs.Validate(0)).To(MatchError(&network.UpInputsValidationError{Errors: []network.RuleValidationError{
+				{Rule: "ip", Message: "172.30.0 is not an ip address"},
+			}}))
+		})
+
+		It("reports every problem", func() {
+			inputs.NetIn[0].ContainerPort = 0
+			inputs.NetIn[1].Protocol = "sctp"
+			inputs.NetOut[1].Protocol = netrules.ProtocolICMP
+			inputs.NetOut[1].Networks[0].End = net.ParseIP("10.0.0.0")
+
+			err := inputs.Validate(0)
+			Expect(err).To(MatchError(&network.UpInputsValidationError{Errors: []network.RuleValidationError{
+				{Rule: "netin[0]", Message: "container port must not be zero"},
+				{Rule: "netin[1]", Message: "invalid protocol: sctp"},
+				{Rule: "netout_rules[1]", Message: "ports must not be specified for icmp rules"},
+				{Rule: "netout_rules[1]", Message: "networks[0]: start 10.0.0.1 is after end 10.0.0.0"},
+			}}))
+			Expect(err.Error()).To(Equal("network rules are invalid:" +
+				"\n\tnetin[0]: container port must not be zero" +
+				"\n\tnetin[1]: invalid protocol: sctp" +
+				"\n\tnetout_rules[1]: ports must not be specified for icmp rules" +
+				"\n\tnetout_rules[1]: networks[0]: start 10.0.0.1 is after end 10.0.0.0"))
+		})
+	})
+})
+
+var _ = Describe("Config", func() {
+	Describe("Validate", func() {
+		var config network.Config
+
+		BeforeEach(func() {
+			config = network.Config{
+				NetOutDenyRules: []netrules.NetOut{
+					{Networks: []netrules.IPRange{{Start: net.ParseIP("169.254.169.254"), End: net.ParseIP("169.254.169.254")}}},
+					{Action: netrules.NetOutActionDeny, Priority: 50, Protocol: netrules.ProtocolTCP},
+				},
+			}
+		})
+
+		DescribeTable("network definitions",
+			func(networkType, ipAssignment string, vlanID uint, expected error) {
+				config.NetworkType = networkType
+				config.IPAssignment = ipAssignment
+				config.VLANID = vlanID
+
+				if expected == nil {
+					Expect(config.Validate()).To(Succeed())
+				} else {
+					Expect(config.Validate()).To(MatchError(expected))
+				}
+			},
+			Entry("default", "", "", uint(0), nil),
+			Entry("transparent with dhcp and a vlan", "transparent", "dhcp", uint(12), nil),
+			Entry("static l2bridge", "l2bridge", "static", uint(0), nil),
+			Entry("unknown type", "overlay", "", uint(0),
+				&network.NetworkConfigError{Field: "network_type", Message: "unsupported network type overlay"}),
+			Entry("dhcp on l2bridge", "l2bridge", "dhcp", uint(0),
+				&network.NetworkConfigError{Field: "ip_assignment", Message: "dhcp is only supported on transparent networks"}),
+			Entry("unknown ip assignment", "transparent", "auto", uint(0),
+				&network.NetworkConfigError{Field: "ip_assignment", Message: "unsupported ip assignment auto"}),
+			Entry("vlan on nat", "nat", "", uint(12),
+				&network.NetworkConfigError{Field: "vlan_id", Message: "vlans are not supported on nat networks"}),
+			Entry("vlan out of range", "l2bridge", "", uint(4095),
+				&network.NetworkConfigError{Field: "vlan_id", Message: "vlan 4095 is out of range"}),
+		)
+
+		It("accepts valid deny rules", func() {
+			Expect(config.Validate()).To(Succeed())
+		})
+
+		It("accepts valid default rules", func() {
+			config.NetOutDefaultRules = []netrules.NetOut{
+				{Protocol: netrules.ProtocolUDP, Ports: []netrules.PortRange{{Start: 123, End: 123}}},
+			}
+			Expect(config.Validate()).To(Succeed())
+		})
+
+		It("rejects invalid default rules", func() {
+			config.NetOutDefaultRules = []netrules.NetOut{
+				{Protocol: netrules.ProtocolICMP, Ports: []netrules.PortRange{{Start: 123, End: 123}}},
+				{Action: netrules.NetOutActionDeny},
+			}
+
+			Expect(config.Validate()).To(MatchError(&network.UpInputsValidationError{Errors: []network.RuleValidationError{
+				{Rule: "netout_default_rules[0]", Message: "ports must not be specified for icmp rules"},
+				{Rule: "netout_default_rules[1]", Message: "deny rules belong in netout_deny_rules"},
+			}}))
+		})
+
+		It("rejects allow rules and priorities after the container rules", func() {
+			config.NetOutDenyRules[0].Action = netrules.NetOutActionAllow
+			config.NetOutDenyRules[1].Priority = 101
+
+			Expect(config.Validate()).To(MatchError(&network.UpInputsValidationError{Errors: []network.RuleValidationError{
+				{Rule: "netout_deny_rules[0]", Message: "action must be deny"},
+				{Rule: "netout_deny_rules[1]", Message: "priority 101 must not be greater than 100"},
+			}}))
+		})
+	})
+})
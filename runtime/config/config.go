@@ -18,9 +18,25 @@ import (
 const (
 	SpecConfig = "config.json"
 	defaultCwd = "C:\\"
+
+	layerChainFile = "layerchain.json"
+	sandboxVHD     = "sandbox.vhdx"
 )
 
+// ValidateBundle validates a bundle whose volume is provided in root.path. The
+// layer folders must form a consistent chain of read-only image layers.
 func ValidateBundle(logger *logrus.Entry, bundlePath string) (*specs.Spec, error) {
+	return validateBundle(logger, bundlePath, checkRootAndImageLayers)
+}
+
+// ValidateLayerBundle validates a bundle whose volume is mounted by winc from
+// its layer folders rather than provided in root.path. The layer folders must
+// form a consistent chain ending in a writable sandbox layer.
+func ValidateLayerBundle(logger *logrus.Entry, bundlePath string) (*specs.Spec, error) {
+	return validateBundle(logger, bundlePath, checkLayerChain)
+}
+
+func validateBundle(logger *logrus.Entry, bundlePath string, checkRootfs func(specs.Spec) []string) (*specs.Spec, error) {
 	logger.Debug("validating bundle")
 
 	if _, err := os.Stat(bundlePath); err != nil {
@@ -42,6 +58,7 @@ func ValidateBundle(logger *logrus.Entry, bundlePath string) (*specs.Spec, error
 
 	validator := validate.NewValidator(&spec, bundlePath, true, "windows")
 	msgs := checkAll(spec, validator)
+	msgs = append(msgs, checkRootfs(spec)...)
 	if len(msgs) != 0 {
 		for _, m := range msgs {
 			logger.WithField("bundleConfigError", m).Error(fmt.Sprintf("error in bundle %s", SpecConfig))
@@ -57,16 +74,120 @@ func checkAll(spec specs.Spec, v validate.Validator) []string {
 	msgs = append(msgs, v.CheckPlatform()...)
 	msgs = append(msgs, v.CheckMandatoryFields()...)
 	msgs = append(msgs, checkSemVer(spec.Version)...)
+	return msgs
+}
+
+func checkRoot(spec specs.Spec) []string {
 	if spec.Root == nil {
-		msgs = append(msgs, "'root' MUST be set when platform is `windows`")
-	} else {
-		if spec.Root.Path == "" {
-			msgs = append(msgs, "'Spec.Root.Path' should not be empty.")
+		return []string{"'root' MUST be set when platform is `windows`"}
+	}
+	if spec.Root.Path == "" {
+		return []string{"'Spec.Root.Path' should not be empty."}
+	}
+	return []string{}
+}
+
+func checkRootAndImageLayers(spec specs.Spec) []string {
+	msgs := checkRoot(spec)
+	if spec.Windows == nil || len(spec.Windows.LayerFolders) == 0 {
+		// the validator already reports missing layer folders
+		return msgs
+	}
+
+	return append(msgs, checkLayerFolders(spec.Windows.LayerFolders, false)...)
+}
+
+func checkLayerChain(spec specs.Spec) []string {
+	if spec.Windows == nil {
+		return []string{}
+	}
+
+	layerFolders := spec.Windows.LayerFolders
+	if len(layerFolders) < 2 {
+		return []string{"'Windows.LayerFolders' must contain at least one image layer followed by a sandbox layer"}
+	}
+
+	return checkLayerFolders(layerFolders, true)
+}
+
+// checkLayerFolders checks that every layer folder exists and that their
+// layerchain.json files agree with the order of the folders. When hasSandbox
+// is set the last folder must be a writable sandbox on top of all the others.
+func checkLayerFolders(layerFolders []string, hasSandbox bool) []string {
+	msgs := []string{}
+	imageLayers := len(layerFolders)
+	if hasSandbox {
+		imageLayers--
+	}
+
+	for i, layerFolder := range layerFolders {
+		field := fmt.Sprintf("'Windows.LayerFolders[%d]'", i)
+
+		fileInfo, err := os.Stat(layerFolder)
+		if err != nil {
+			msgs = append(msgs, fmt.Sprintf("%s %s does not exist", field, layerFolder))
+			continue
+		}
+		if !fileInfo.IsDir() {
+			msgs = append(msgs, fmt.Sprintf("%s %s is not a directory", field, layerFolder))
+			continue
+		}
+
+		// each layer records the layers beneath it, top-most first; the sandbox
+		// sits on top of every image layer and the base layer has no parents
+		var expectedParents []string
+		if i == imageLayers {
+			expectedParents = layerFolders[:imageLayers]
+		} else {
+			expectedParents = layerFolders[i+1 : imageLayers]
+		}
+		msgs = append(msgs, checkLayerParents(field, layerFolder, expectedParents)...)
+
+		if i == imageLayers {
+			if _, err := os.Stat(filepath.Join(layerFolder, sandboxVHD)); err != nil {
+				msgs = append(msgs, fmt.Sprintf("%s %s is not a writable sandbox layer: missing %s", field, layerFolder, sandboxVHD))
+			}
 		}
 	}
+
 	return msgs
 }
 
+func checkLayerParents(field, layerFolder string, expectedParents []string) []string {
+	content, err := ioutil.ReadFile(filepath.Join(layerFolder, layerChainFile))
+	if err != nil {
+		if os.IsNotExist(err) && len(expectedParents) == 0 {
+			return []string{}
+		}
+		return []string{fmt.Sprintf("%s %s has no readable %s: %s", field, layerFolder, layerChainFile, err)}
+	}
+
+	var parents []string
+	if err := json.Unmarshal(content, &parents); err != nil {
+		return []string{fmt.Sprintf("%s %s has an invalid %s: %s", field, layerFolder, layerChainFile, err)}
+	}
+
+	if !sameLayers(parents, expectedParents) {
+		return []string{fmt.Sprintf("%s %s has a %s inconsistent with 'Windows.LayerFolders': expected %v, got %v", field, layerFolder, layerChainFile, expectedParents, parents)}
+	}
+
+	return []string{}
+}
+
+func sameLayers(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if !strings.EqualFold(filepath.Clean(a[i]), filepath.Clean(b[i])) {
+			return false
+		}
+	}
+
+	return true
+}
+
 func ValidateProcess(logger *logrus.Entry, processConfig string, overrides *specs.Process) (*specs.Process, error) {
 	logger.Debug("validating process config")
 
@@ -133,6 +254,65 @@ func ValidateProcess(logger *logrus.Entry, processConfig string, overrides *spec
 	return &spec, nil
 }
 
+type credentialSpec struct {
+	CmsPlugins       []string `json:"CmsPlugins"`
+	DomainJoinConfig *struct {
+		Sid                string `json:"Sid"`
+		MachineAccountName string `json:"MachineAccountName"`
+		Guid               string `json:"Guid"`
+		DnsTreeName        string `json:"DnsTreeName"`
+		DnsName            string `json:"DnsName"`
+		NetBiosName        string `json:"NetBiosName"`
+	} `json:"DomainJoinConfig"`
+}
+
+func ValidateCredentialSpec(logger *logrus.Entry, source string, content []byte) error {
+	logger.Debug("validating credential spec")
+
+	if !utf8.Valid(content) {
+		return &CredentialSpecInvalidEncodingError{Source: source}
+	}
+
+	var cs credentialSpec
+	if err := json.Unmarshal(content, &cs); err != nil {
+		return &CredentialSpecInvalidJSONError{Source: source, InternalError: err}
+	}
+
+	msgs := []string{}
+
+	if len(cs.CmsPlugins) == 0 {
+		msgs = append(msgs, "'CmsPlugins' must not be empty")
+	}
+
+	if cs.DomainJoinConfig == nil {
+		msgs = append(msgs, "'DomainJoinConfig' must be set")
+	} else {
+		djc := cs.DomainJoinConfig
+		fields := []struct{ name, value string }{
+			{"Sid", djc.Sid},
+			{"MachineAccountName", djc.MachineAccountName},
+			{"Guid", djc.Guid},
+			{"DnsTreeName", djc.DnsTreeName},
+			{"DnsName", djc.DnsName},
+			{"NetBiosName", djc.NetBiosName},
+		}
+		for _, f := range fields {
+			if f.value == "" {
+				msgs = append(msgs, fmt.Sprintf("'DomainJoinConfig.%s' must not be empty", f.name))
+			}
+		}
+	}
+
+	if len(msgs) > 0 {
+		for _, m := range msgs {
+			logger.WithField("credentialSpecError", m).Error("error in credential spec")
+		}
+		return &CredentialSpecValidationError{Source: source, ErrorMessages: msgs}
+	}
+
+	return nil
+}
+
 func envValid(env string) bool {
 	items := strings.Split(env, "=")
 	if len(items) < 2 {
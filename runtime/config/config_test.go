@@ -38,9 +38,20 @@ var _ = Describe("Config", func() {
 		Context("given a valid bundle", func() {
 			var (
 				expectedSpec specs.Spec
+				topLayer     string
+				baseLayer    string
 			)
 
 			BeforeEach(func() {
+				topLayer = filepath.Join(bundlePath, "top-layer")
+				baseLayer = filepath.Join(bundlePath, "base-layer")
+				Expect(os.MkdirAll(topLayer, 0755)).To(Succeed())
+				Expect(os.MkdirAll(baseLayer, 0755)).To(Succeed())
+
+				chain, err := json.Marshal([]string{baseLayer})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(ioutil.WriteFile(filepath.Join(topLayer, "layerchain.json"), chain, 0666)).To(Succeed())
+
 				expectedSpec = specs.Spec{
 					Version: specs.Version,
 					Process: &specs.Process{
@@ -51,7 +62,7 @@ var _ = Describe("Config", func() {
 						Path: "some-volume-guid",
 					},
 					Windows: &specs.Windows{
-						LayerFolders: []string{"a layer", "another layer"},
+						LayerFolders: []string{topLayer, baseLayer},
 					},
 				}
 			})
@@ -79,6 +90,20 @@ var _ = Describe("Config", func() {
 					Expect(spec).To(Equal(&expectedSpec))
 				})
 			})
+
+			Context("when the layer folders do not form a consistent chain", func() {
+				BeforeEach(func() {
+					expectedSpec.Windows.LayerFolders = []string{baseLayer, topLayer, filepath.Join(bundlePath, "missing-layer")}
+				})
+
+				It("returns an error describing every problem", func() {
+					_, err := config.ValidateBundle(logger, bundlePath)
+					Expect(err).To(BeAssignableToTypeOf(&config.BundleConfigValidationError{}))
+					Expect(err.Error()).To(ContainSubstring("'Windows.LayerFolders[0]' %s has no readable layerchain.json", baseLayer))
+					Expect(err.Error()).To(ContainSubstring("'Windows.LayerFolders[1]' %s has a layerchain.json inconsistent with 'Windows.LayerFolders'", topLayer))
+					Expect(err.Error()).To(ContainSubstring("'Windows.LayerFolders[2]' %s does not exist", filepath.Join(bundlePath, "missing-layer")))
+				})
+			})
 		})
 
 		Context("when provided a nonexistent bundle directory", func() {
@@ -288,6 +313,140 @@ var _ = Describe("Config", func() {
 		})
 	})
 
+	Context("LayerBundle", func() {
+		var (
+			layerSpec    specs.Spec
+			layersDir    string
+			topLayer     string
+			baseLayer    string
+			sandboxLayer string
+		)
+
+		writeLayerChain := func(layer string, parents ...string) {
+			content, err := json.Marshal(parents)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ioutil.WriteFile(filepath.Join(layer, "layerchain.json"), content, 0666)).To(Succeed())
+		}
+
+		BeforeEach(func() {
+			var err error
+			layersDir, err = ioutil.TempDir("", "layers")
+			Expect(err).NotTo(HaveOccurred())
+
+			topLayer = filepath.Join(layersDir, "top-layer")
+			baseLayer = filepath.Join(layersDir, "base-layer")
+			sandboxLayer = filepath.Join(layersDir, "sandbox")
+			for _, l := range []string{topLayer, baseLayer, sandboxLayer} {
+				Expect(os.MkdirAll(l, 0755)).To(Succeed())
+			}
+
+			writeLayerChain(topLayer, baseLayer)
+			writeLayerChain(sandboxLayer, topLayer, baseLayer)
+			Expect(ioutil.WriteFile(filepath.Join(sandboxLayer, "sandbox.vhdx"), []byte{}, 0666)).To(Succeed())
+
+			layerSpec = specs.Spec{
+				Version: specs.Version,
+				Process: &specs.Process{
+					Args: []string{"powershell"},
+					Cwd:  "C:\\",
+				},
+				Windows: &specs.Windows{
+					LayerFolders: []string{topLayer, baseLayer, sandboxLayer},
+				},
+			}
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(layersDir)).To(Succeed())
+		})
+
+		JustBeforeEach(func() {
+			config, err := json.Marshal(&layerSpec)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ioutil.WriteFile(filepath.Join(bundlePath, "config.json"), config, 0666)).To(Succeed())
+		})
+
+		It("does not require a root volume", func() {
+			spec, err := config.ValidateLayerBundle(logger, bundlePath)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(spec).To(Equal(&layerSpec))
+		})
+
+		Context("when 'Windows.LayerFolders' has no sandbox layer", func() {
+			BeforeEach(func() {
+				layerSpec.Windows.LayerFolders = []string{baseLayer}
+			})
+
+			It("returns an error describing that it is missing", func() {
+				_, err := config.ValidateLayerBundle(logger, bundlePath)
+				Expect(err).To(BeAssignableToTypeOf(&config.BundleConfigValidationError{}))
+				Expect(err.Error()).To(ContainSubstring("'Windows.LayerFolders' must contain at least one image layer followed by a sandbox layer"))
+			})
+		})
+
+		Context("when a layer folder does not exist", func() {
+			BeforeEach(func() {
+				Expect(os.RemoveAll(topLayer)).To(Succeed())
+			})
+
+			It("returns an error describing that it is missing", func() {
+				_, err := config.ValidateLayerBundle(logger, bundlePath)
+				Expect(err).To(BeAssignableToTypeOf(&config.BundleConfigValidationError{}))
+				Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("'Windows.LayerFolders[0]' %s does not exist", topLayer)))
+			})
+		})
+
+		Context("when a layerchain.json does not match the order of the layer folders", func() {
+			BeforeEach(func() {
+				writeLayerChain(sandboxLayer, baseLayer, topLayer)
+			})
+
+			It("returns an error describing the inconsistency", func() {
+				_, err := config.ValidateLayerBundle(logger, bundlePath)
+				Expect(err).To(BeAssignableToTypeOf(&config.BundleConfigValidationError{}))
+				Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("'Windows.LayerFolders[2]' %s has a layerchain.json inconsistent with 'Windows.LayerFolders'", sandboxLayer)))
+			})
+		})
+
+		Context("when a layerchain.json is invalid", func() {
+			BeforeEach(func() {
+				Expect(ioutil.WriteFile(filepath.Join(topLayer, "layerchain.json"), []byte("{"), 0666)).To(Succeed())
+			})
+
+			It("returns an error describing that it is invalid", func() {
+				_, err := config.ValidateLayerBundle(logger, bundlePath)
+				Expect(err).To(BeAssignableToTypeOf(&config.BundleConfigValidationError{}))
+				Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("'Windows.LayerFolders[0]' %s has an invalid layerchain.json", topLayer)))
+			})
+		})
+
+		Context("when the last layer folder is not a sandbox", func() {
+			BeforeEach(func() {
+				Expect(os.Remove(filepath.Join(sandboxLayer, "sandbox.vhdx"))).To(Succeed())
+			})
+
+			It("returns an error describing that it is not writable", func() {
+				_, err := config.ValidateLayerBundle(logger, bundlePath)
+				Expect(err).To(BeAssignableToTypeOf(&config.BundleConfigValidationError{}))
+				Expect(err.Error()).To(ContainSubstring(fmt.Sprintf("'Windows.LayerFolders[2]' %s is not a writable sandbox layer: missing sandbox.vhdx", sandboxLayer)))
+			})
+		})
+
+		Context("when there are several problems with the layer folders", func() {
+			BeforeEach(func() {
+				Expect(os.RemoveAll(baseLayer)).To(Succeed())
+				Expect(os.Remove(filepath.Join(topLayer, "layerchain.json"))).To(Succeed())
+				Expect(os.Remove(filepath.Join(sandboxLayer, "sandbox.vhdx"))).To(Succeed())
+			})
+
+			It("reports every one of them", func() {
+				_, err := config.ValidateLayerBundle(logger, bundlePath)
+				Expect(err).To(BeAssignableToTypeOf(&config.BundleConfigValidationError{}))
+				Expect(err.(*config.BundleConfigValidationError).ErrorMessages).To(HaveLen(3))
+			})
+		})
+	})
+
 	Context("Process", func() {
 		var (
 			spec                   *specs.Process
@@ -524,4 +683,67 @@ var _ = Describe("Config", func() {
 			})
 		})
 	})
+
+	Context("CredentialSpec", func() {
+		var content string
+
+		BeforeEach(func() {
+			content = `{
+				"CmsPlugins": ["ActiveDirectory"],
+				"DomainJoinConfig": {
+					"Sid": "S-1-5-21-1234",
+					"MachineAccountName": "webapp01",
+					"Guid": "244818ae-87ca-4fcd-92ec-e79e5252348a",
+					"DnsTreeName": "contoso.com",
+					"DnsName": "contoso.com",
+					"NetBiosName": "CONTOSO"
+				}
+			}`
+		})
+
+		It("accepts a valid credential spec", func() {
+			Expect(config.ValidateCredentialSpec(logger, "some-source", []byte(content))).To(Succeed())
+		})
+
+		Context("when the credential spec is not valid JSON", func() {
+			BeforeEach(func() {
+				content = "{"
+			})
+
+			It("returns an error", func() {
+				err := config.ValidateCredentialSpec(logger, "some-source", []byte(content))
+				Expect(err).To(BeAssignableToTypeOf(&config.CredentialSpecInvalidJSONError{}))
+				Expect(err.Error()).To(ContainSubstring("credential spec contains invalid JSON: some-source"))
+			})
+		})
+
+		Context("when the credential spec is missing required fields", func() {
+			BeforeEach(func() {
+				content = `{"DomainJoinConfig": {"Sid": "S-1-5-21-1234", "DnsName": "contoso.com"}}`
+			})
+
+			It("returns an error describing every missing field", func() {
+				err := config.ValidateCredentialSpec(logger, "some-source", []byte(content))
+				Expect(err).To(BeAssignableToTypeOf(&config.CredentialSpecValidationError{}))
+				Expect(err.(*config.CredentialSpecValidationError).ErrorMessages).To(ConsistOf(
+					"'CmsPlugins' must not be empty",
+					"'DomainJoinConfig.MachineAccountName' must not be empty",
+					"'DomainJoinConfig.Guid' must not be empty",
+					"'DomainJoinConfig.DnsTreeName' must not be empty",
+					"'DomainJoinConfig.NetBiosName' must not be empty",
+				))
+			})
+		})
+
+		Context("when the credential spec has no DomainJoinConfig", func() {
+			BeforeEach(func() {
+				content = `{"CmsPlugins": ["ActiveDirectory"]}`
+			})
+
+			It("returns an error", func() {
+				err := config.ValidateCredentialSpec(logger, "some-source", []byte(content))
+				Expect(err).To(MatchError(ContainSubstring("'DomainJoinConfig' must be set")))
+			})
+		})
+	})
 })
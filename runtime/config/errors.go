@@ -88,3 +88,34 @@ func (e *ProcessConfigValidationError) Error() string {
 
 	return errorStr
 }
+
+type CredentialSpecInvalidEncodingError struct {
+	Source string
+}
+
+func (e *CredentialSpecInvalidEncodingError) Error() string {
+	return fmt.Sprintf("credential spec is not encoded in UTF-8: %s", e.Source)
+}
+
+type CredentialSpecInvalidJSONError struct {
+	Source        string
+	InternalError error
+}
+
+func (e *CredentialSpecInvalidJSONError) Error() string {
+	return fmt.Sprintf("credential spec contains invalid JSON: %s: %s", e.Source, e.InternalError)
+}
+
+type CredentialSpecValidationError struct {
+	Source        string
+	ErrorMessages []string
+}
+
+func (e *CredentialSpecValidationError) Error() string {
+	errorStr := fmt.Sprintf("credential spec is invalid: %s:", e.Source)
+	for _, m := range e.ErrorMessages {
+		errorStr += "\n\t" + m
+	}
+
+	return errorStr
+}
@@ -1,6 +1,7 @@
 package container
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -16,7 +17,14 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-const destroyTimeout = time.Minute
+const (
+	destroyTimeout = time.Minute
+
+	// credential specs given as "file://<path>" in the bundle's config.json
+	// are read from <path>, relative to the bundle directory
+	credentialSpecFilePrefix = "file://"
+	InlineCredentialSpec     = "inline"
+)
 
 type Manager struct {
 	logger    *logrus.Entry
@@ -65,16 +73,22 @@ func New(logger *logrus.Entry, hcsClient HCSClient, id string) *Manager {
 }
 
 func (m *Manager) Spec(bundlePath string) (*specs.Spec, error) {
-	if bundlePath == "" {
-		var err error
-		bundlePath, err = os.Getwd()
-		if err != nil {
-			return nil, err
-		}
+	return m.spec(bundlePath, config.ValidateBundle)
+}
+
+// LayerSpec loads a bundle whose sandbox is mounted by winc from its layer
+// folders, so root.path is not required
+func (m *Manager) LayerSpec(bundlePath string) (*specs.Spec, error) {
+	return m.spec(bundlePath, config.ValidateLayerBundle)
+}
+
+func (m *Manager) spec(bundlePath string, validateBundle func(*logrus.Entry, string) (*specs.Spec, error)) (*specs.Spec, error) {
+	bundlePath, err := resolveBundlePath(bundlePath)
+	if err != nil {
+		return nil, err
 	}
-	bundlePath = filepath.Clean(bundlePath)
 
-	spec, err := config.ValidateBundle(m.logger, bundlePath)
+	spec, err := validateBundle(m.logger, bundlePath)
 	if err != nil {
 		return nil, err
 	}
@@ -90,18 +104,83 @@ func (m *Manager) Spec(bundlePath string) (*specs.Spec, error) {
 	return spec, nil
 }
 
-func (m *Manager) CredentialSpec(credentialSpecPath string) (string, error) {
+// CredentialSpec returns the contents of the credential spec for the container
+// along with where it was loaded from. A credential spec in the bundle's
+// config.json takes precedence over the one at credentialSpecPath. Specs from
+// either source are validated before they are used.
+func (m *Manager) CredentialSpec(spec *specs.Spec, bundlePath, credentialSpecPath string) (string, string, error) {
+	if spec.Windows != nil && spec.Windows.CredentialSpec != nil {
+		return m.bundleCredentialSpec(spec.Windows.CredentialSpec, bundlePath)
+	}
+
 	if credentialSpecPath == "" {
-		return "", nil
+		return "", "", nil
 	}
 
 	credentialSpecPath = filepath.Clean(credentialSpecPath)
 	content, err := os.ReadFile(credentialSpecPath)
+	if err != nil {
+		return "", "", err
+	}
+
+	if err := config.ValidateCredentialSpec(m.logger, credentialSpecPath, content); err != nil {
+		return "", "", err
+	}
+
+	return string(content), credentialSpecPath, nil
+}
+
+func (m *Manager) bundleCredentialSpec(credentialSpec interface{}, bundlePath string) (string, string, error) {
+	var (
+		content []byte
+		source  string
+		err     error
+	)
+
+	switch cs := credentialSpec.(type) {
+	case string:
+		if strings.HasPrefix(cs, credentialSpecFilePrefix) {
+			source, err = m.bundleFilePath(bundlePath, strings.TrimPrefix(cs, credentialSpecFilePrefix))
+			if err != nil {
+				return "", "", err
+			}
+
+			content, err = os.ReadFile(source)
+			if err != nil {
+				return "", "", err
+			}
+		} else {
+			source = InlineCredentialSpec
+			content = []byte(cs)
+		}
+	default:
+		source = InlineCredentialSpec
+		content, err = json.Marshal(cs)
+		if err != nil {
+			return "", "", err
+		}
+	}
+
+	if err := config.ValidateCredentialSpec(m.logger, source, content); err != nil {
+		return "", "", err
+	}
+
+	return string(content), source, nil
+}
+
+func (m *Manager) bundleFilePath(bundlePath, path string) (string, error) {
+	bundlePath, err := resolveBundlePath(bundlePath)
 	if err != nil {
 		return "", err
 	}
 
-	return string(content), nil
+	fullPath := filepath.Join(bundlePath, path)
+	rel, err := filepath.Rel(bundlePath, fullPath)
+	if err != nil || filepath.IsAbs(path) || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", &InvalidCredentialSpecPathError{Id: m.id, Path: path}
+	}
+
+	return fullPath, nil
 }
 
 func (m *Manager) Create(spec *specs.Spec, credentialSpec string) error {
@@ -361,6 +440,17 @@ func (m *Manager) terminateContainer(container hcs.Container) error {
 	return nil
 }
 
+func resolveBundlePath(bundlePath string) (string, error) {
+	if bundlePath == "" {
+		var err error
+		bundlePath, err = os.Getwd()
+		if err != nil {
+			return "", err
+		}
+	}
+	return filepath.Clean(bundlePath), nil
+}
+
 func destToWindowsPath(input string) string {
 	vol := filepath.VolumeName(input)
 	if vol == "" {
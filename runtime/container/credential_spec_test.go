@@ -3,19 +3,38 @@ package container_test
 import (
 	"io/ioutil"
 	"os"
+	"path/filepath"
 
+	"code.cloudfoundry.org/winc/runtime/config"
 	"code.cloudfoundry.org/winc/runtime/container"
 	"code.cloudfoundry.org/winc/runtime/container/fakes"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/sirupsen/logrus"
 )
 
 var _ = Describe("CredentialSpec", func() {
-	const containerId = "container-id"
+	const (
+		containerId         = "container-id"
+		validCredentialSpec = `{
+			"CmsPlugins": ["ActiveDirectory"],
+			"DomainJoinConfig": {
+				"Sid": "S-1-5-21-1234",
+				"MachineAccountName": "webapp01",
+				"Guid": "244818ae-87ca-4fcd-92ec-e79e5252348a",
+				"DnsTreeName": "contoso.com",
+				"DnsName": "contoso.com",
+				"NetBiosName": "CONTOSO"
+			}
+		}`
+	)
+
 	var (
 		credentialSpecPath     string
 		credentialSpecContents string
+		bundlePath             string
+		spec                   *specs.Spec
 
 		hcsClient        *fakes.HCSClient
 		logger           *logrus.Entry
@@ -29,9 +48,14 @@ var _ = Describe("CredentialSpec", func() {
 
 		credentialSpecPath = credentialSpecFile.Name()
 
-		credentialSpecContents = "credential-spec-contents"
+		credentialSpecContents = validCredentialSpec
 		Expect(os.WriteFile(credentialSpecPath, []byte(credentialSpecContents), 0644)).To(Succeed())
 
+		bundlePath, err = ioutil.TempDir("", containerId)
+		Expect(err).ToNot(HaveOccurred())
+
+		spec = &specs.Spec{Windows: &specs.Windows{}}
+
 		hcsClient = &fakes.HCSClient{}
 		logger = (&logrus.Logger{
 			Out: ioutil.Discard,
@@ -40,15 +64,33 @@ var _ = Describe("CredentialSpec", func() {
 		containerManager = container.New(logger, hcsClient, containerId)
 	})
 
+	AfterEach(func() {
+		Expect(os.RemoveAll(credentialSpecPath)).To(Succeed())
+		Expect(os.RemoveAll(bundlePath)).To(Succeed())
+	})
+
 	It("loads the credential spec from the path", func() {
-		actual, err := containerManager.CredentialSpec(credentialSpecPath)
+		actual, source, err := containerManager.CredentialSpec(spec, bundlePath, credentialSpecPath)
 		Expect(err).NotTo(HaveOccurred())
 		Expect(actual).To(Equal(credentialSpecContents))
+		Expect(source).To(Equal(filepath.Clean(credentialSpecPath)))
+	})
+
+	Context("when the credential spec at the path is invalid", func() {
+		BeforeEach(func() {
+			Expect(os.WriteFile(credentialSpecPath, []byte("credential-spec-contents"), 0644)).To(Succeed())
+		})
+
+		It("returns an error naming the path", func() {
+			_, _, err := containerManager.CredentialSpec(spec, bundlePath, credentialSpecPath)
+			Expect(err).To(BeAssignableToTypeOf(&config.CredentialSpecInvalidJSONError{}))
+			Expect(err.(*config.CredentialSpecInvalidJSONError).Source).To(Equal(filepath.Clean(credentialSpecPath)))
+		})
 	})
 
 	Context("when the path is invalid", func() {
 		It("returns the error", func() {
-			_, err := containerManager.CredentialSpec("/not/a/valid/path")
+			_, _, err := containerManager.CredentialSpec(spec, bundlePath, "/not/a/valid/path")
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("The system cannot find the path specified"))
 		})
@@ -56,9 +98,94 @@ var _ = Describe("CredentialSpec", func() {
 
 	Context("when the path is empty", func() {
 		It("returns an empty string", func() {
-			actual, err := containerManager.CredentialSpec("")
+			actual, source, err := containerManager.CredentialSpec(spec, bundlePath, "")
 			Expect(err).NotTo(HaveOccurred())
 			Expect(actual).To(BeEmpty())
+			Expect(source).To(BeEmpty())
+		})
+	})
+
+	Context("when the bundle spec contains an inline credential spec object", func() {
+		BeforeEach(func() {
+			spec.Windows.CredentialSpec = map[string]interface{}{
+				"CmsPlugins": []string{"ActiveDirectory"},
+				"DomainJoinConfig": map[string]string{
+					"Sid":                "S-1-5-21-1234",
+					"MachineAccountName": "webapp01",
+					"Guid":               "244818ae-87ca-4fcd-92ec-e79e5252348a",
+					"DnsTreeName":        "contoso.com",
+					"DnsName":            "contoso.com",
+					"NetBiosName":        "CONTOSO",
+				},
+			}
+		})
+
+		It("uses it instead of the credential spec path", func() {
+			actual, source, err := containerManager.CredentialSpec(spec, bundlePath, credentialSpecPath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(actual).To(MatchJSON(validCredentialSpec))
+			Expect(source).To(Equal(container.InlineCredentialSpec))
+		})
+	})
+
+	Context("when the bundle spec contains an inline credential spec string", func() {
+		BeforeEach(func() {
+			spec.Windows.CredentialSpec = validCredentialSpec
+		})
+
+		It("uses it instead of the credential spec path", func() {
+			actual, source, err := containerManager.CredentialSpec(spec, bundlePath, credentialSpecPath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(actual).To(Equal(validCredentialSpec))
+			Expect(source).To(Equal(container.InlineCredentialSpec))
+		})
+	})
+
+	Context("when the bundle spec references a credential spec file in the bundle", func() {
+		BeforeEach(func() {
+			Expect(os.MkdirAll(filepath.Join(bundlePath, "specs"), 0755)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(bundlePath, "specs", "webapp01.json"), []byte(validCredentialSpec), 0644)).To(Succeed())
+			spec.Windows.CredentialSpec = "file://specs/webapp01.json"
+		})
+
+		It("loads it relative to the bundle", func() {
+			actual, source, err := containerManager.CredentialSpec(spec, bundlePath, credentialSpecPath)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(actual).To(Equal(validCredentialSpec))
+			Expect(source).To(Equal(filepath.Join(bundlePath, "specs", "webapp01.json")))
+		})
+
+		Context("when the file is outside the bundle", func() {
+			BeforeEach(func() {
+				spec.Windows.CredentialSpec = "file://../webapp01.json"
+			})
+
+			It("returns an error", func() {
+				_, _, err := containerManager.CredentialSpec(spec, bundlePath, credentialSpecPath)
+				Expect(err).To(MatchError(&container.InvalidCredentialSpecPathError{Id: containerId, Path: "../webapp01.json"}))
+			})
+		})
+
+		Context("when the file does not exist", func() {
+			BeforeEach(func() {
+				spec.Windows.CredentialSpec = "file://missing.json"
+			})
+
+			It("returns the error", func() {
+				_, _, err := containerManager.CredentialSpec(spec, bundlePath, credentialSpecPath)
+				Expect(os.IsNotExist(err)).To(BeTrue())
+			})
+		})
+	})
+
+	Context("when the bundle credential spec is invalid", func() {
+		BeforeEach(func() {
+			spec.Windows.CredentialSpec = `{"CmsPlugins": []}`
+		})
+
+		It("returns a validation error", func() {
+			_, _, err := containerManager.CredentialSpec(spec, bundlePath, credentialSpecPath)
+			Expect(err).To(BeAssignableToTypeOf(&config.CredentialSpecValidationError{}))
 		})
 	})
 })
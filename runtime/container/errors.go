@@ -43,3 +43,12 @@ type InvalidMountOptionsError struct {
 func (e *InvalidMountOptionsError) Error() string {
 	return fmt.Sprintf("invalid mount options for container %s: %+v", e.Id, e.Options)
 }
+
+type InvalidCredentialSpecPathError struct {
+	Id   string
+	Path string
+}
+
+func (e *InvalidCredentialSpecPathError) Error() string {
+	return fmt.Sprintf("credential spec path for container %s must be inside the bundle: %s", e.Id, e.Path)
+}
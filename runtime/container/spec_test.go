@@ -3,6 +3,7 @@ package container_test
 import (
 	"encoding/json"
 	"io/ioutil"
+	"os"
 	"path/filepath"
 
 	"code.cloudfoundry.org/winc/runtime/config"
@@ -37,10 +38,15 @@ var _ = Describe("Spec", func() {
 		containerId = filepath.Base(bundlePath)
 
 		layerFolders = []string{
-			"some-layer",
-			"some-other-layer",
-			"some-rootfs",
+			filepath.Join(bundlePath, "some-layer"),
+			filepath.Join(bundlePath, "some-other-layer"),
 		}
+		for _, layer := range layerFolders {
+			Expect(os.MkdirAll(layer, 0755)).To(Succeed())
+		}
+		chain, err := json.Marshal(layerFolders[1:])
+		Expect(err).ToNot(HaveOccurred())
+		Expect(ioutil.WriteFile(filepath.Join(layerFolders[0], "layerchain.json"), chain, 0644)).To(Succeed())
 
 		spec = &specs.Spec{
 			Version: specs.Version,
@@ -66,6 +72,10 @@ var _ = Describe("Spec", func() {
 		containerManager = container.New(logger, hcsClient, containerId)
 	})
 
+	AfterEach(func() {
+		Expect(os.RemoveAll(bundlePath)).To(Succeed())
+	})
+
 	It("loads and validates the spec from the bundle path", func() {
 		returnedSpec, err := containerManager.Spec(bundlePath)
 		Expect(err).NotTo(HaveOccurred())
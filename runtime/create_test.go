@@ -20,6 +20,8 @@ var _ = Describe("Create", func() {
 	)
 	var (
 		mounter          *fakes.Mounter
+		layerManager     *fakes.LayerManager
+		streamer         *fakes.Streamer
 		stateFactory     *fakes.StateFactory
 		sm               *fakes.StateManager
 		containerFactory *fakes.ContainerFactory
@@ -34,6 +36,8 @@ var _ = Describe("Create", func() {
 
 	BeforeEach(func() {
 		mounter = &fakes.Mounter{}
+		layerManager = &fakes.LayerManager{}
+		streamer = &fakes.Streamer{}
 		hcsQuery = &fakes.HCSQuery{}
 		stateFactory = &fakes.StateFactory{}
 		sm = &fakes.StateManager{}
@@ -46,9 +50,9 @@ var _ = Describe("Create", func() {
 		containerFactory.NewManagerReturns(cm)
 
 		cm.SpecReturns(spec, nil)
-		cm.CredentialSpecReturns("", nil)
+		cm.CredentialSpecReturns("", "", nil)
 
-		r = runtime.New(stateFactory, containerFactory, mounter, hcsQuery, processWrapper, rootDir, credentialSpecPath)
+		r = runtime.New(stateFactory, containerFactory, mounter, layerManager, streamer, hcsQuery, processWrapper, rootDir, credentialSpecPath, false)
 	})
 
 	It("loads the spec, creates the container, and intializes the state", func() {
@@ -70,18 +74,22 @@ var _ = Describe("Create", func() {
 		Expect(s).To(Equal(spec))
 		Expect(cs).To(Equal(""))
 
-		Expect(sm.InitializeArgsForCall(0)).To(Equal(bundlePath))
+		bp, csSource, _, _ := sm.InitializeArgsForCall(0)
+		Expect(bp).To(Equal(bundlePath))
+		Expect(csSource).To(Equal(""))
 	})
 
 	Context("when a non-empty credential spec path is provided", func() {
 		BeforeEach(func() {
 			credentialSpecPath = "/path/to/credential/spec"
-			r = runtime.New(stateFactory, containerFactory, mounter, hcsQuery, processWrapper, rootDir, credentialSpecPath)
+			r = runtime.New(stateFactory, containerFactory, mounter, layerManager, streamer, hcsQuery, processWrapper, rootDir, credentialSpecPath, false)
 
-			cm.CredentialSpecStub = func(path string) (string, error) {
+			cm.CredentialSpecStub = func(s *specs.Spec, bp, path string) (string, string, error) {
+				Expect(s).To(Equal(spec))
+				Expect(bp).To(Equal(bundlePath))
 				Expect(path).To(Equal(credentialSpecPath))
 
-				return "credential-spec-contents", nil
+				return "credential-spec-contents", "credential-spec-source", nil
 			}
 		})
 
@@ -104,12 +112,14 @@ var _ = Describe("Create", func() {
 			Expect(s).To(Equal(spec))
 			Expect(cs).To(Equal("credential-spec-contents"))
 
-			Expect(sm.InitializeArgsForCall(0)).To(Equal(bundlePath))
+			bp, csSource, _, _ := sm.InitializeArgsForCall(0)
+			Expect(bp).To(Equal(bundlePath))
+			Expect(csSource).To(Equal("credential-spec-source"))
 		})
 
 		Context("loading the credential spec fails", func() {
 			BeforeEach(func() {
-				cm.CredentialSpecReturns("", errors.New("bad credential spec"))
+				cm.CredentialSpecReturns("", "", errors.New("bad credential spec"))
 			})
 
 			It("returns the error", func() {
@@ -155,4 +165,72 @@ var _ = Describe("Create", func() {
 			Expect(force).To(Equal(false))
 		})
 	})
+
+	Context("when winc mounts the container layers", func() {
+		BeforeEach(func() {
+			spec = &specs.Spec{
+				Windows: &specs.Windows{
+					LayerFolders: []string{"C:\\layers\\base-layer", "C:\\sandboxes\\my-sandbox"},
+				},
+			}
+			cm.LayerSpecReturns(spec, nil)
+			layerManager.MountReturns("C:\\sandboxes\\my-sandbox", "some-volume-path", nil)
+
+			r = runtime.New(stateFactory, containerFactory, mounter, layerManager, streamer, hcsQuery, processWrapper, rootDir, credentialSpecPath, true)
+		})
+
+		It("mounts the sandbox and creates the container from its volume", func() {
+			Expect(r.Create(containerId, bundlePath)).To(Succeed())
+
+			Expect(cm.SpecCallCount()).To(Equal(0))
+			Expect(cm.LayerSpecArgsForCall(0)).To(Equal(bundlePath))
+			Expect(layerManager.MountArgsForCall(0)).To(Equal([]string{"C:\\layers\\base-layer", "C:\\sandboxes\\my-sandbox"}))
+
+			s, _ := cm.CreateArgsForCall(0)
+			Expect(s.Root).To(Equal(&specs.Root{Path: "some-volume-path"}))
+			Expect(s.Windows.LayerFolders).To(Equal([]string{"C:\\layers\\base-layer"}))
+
+			bp, _, sandbox, volumePath := sm.InitializeArgsForCall(0)
+			Expect(bp).To(Equal(bundlePath))
+			Expect(sandbox).To(Equal("C:\\sandboxes\\my-sandbox"))
+			Expect(volumePath).To(Equal("some-volume-path"))
+		})
+
+		Context("mounting the layers fails", func() {
+			BeforeEach(func() {
+				layerManager.MountReturns("", "", errors.New("couldn't mount"))
+			})
+
+			It("returns the error", func() {
+				err := r.Create(containerId, bundlePath)
+				Expect(err).To(MatchError("couldn't mount"))
+				Expect(cm.CreateCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("creating the container fails", func() {
+			BeforeEach(func() {
+				cm.CreateReturns(errors.New("hcsshim fell over"))
+			})
+
+			It("unmounts the sandbox", func() {
+				err := r.Create(containerId, bundlePath)
+				Expect(err).To(MatchError("hcsshim fell over"))
+				Expect(layerManager.UnmountArgsForCall(0)).To(Equal("C:\\sandboxes\\my-sandbox"))
+			})
+		})
+
+		Context("writing the state fails", func() {
+			BeforeEach(func() {
+				sm.InitializeReturns(errors.New("couldn't write state"))
+			})
+
+			It("deletes the container and unmounts the sandbox", func() {
+				err := r.Create(containerId, bundlePath)
+				Expect(err).To(MatchError("couldn't write state"))
+				Expect(cm.DeleteArgsForCall(0)).To(BeFalse())
+				Expect(layerManager.UnmountArgsForCall(0)).To(Equal("C:\\sandboxes\\my-sandbox"))
+			})
+		})
+	})
 })
@@ -24,6 +24,8 @@ var _ = Describe("Delete", func() {
 	)
 	var (
 		mounter            *fakes.Mounter
+		layerManager       *fakes.LayerManager
+		streamer           *fakes.Streamer
 		stateFactory       *fakes.StateFactory
 		sm                 *fakes.StateManager
 		containerFactory   *fakes.ContainerFactory
@@ -36,6 +38,8 @@ var _ = Describe("Delete", func() {
 
 	BeforeEach(func() {
 		mounter = &fakes.Mounter{}
+		layerManager = &fakes.LayerManager{}
+		streamer = &fakes.Streamer{}
 		hcsQuery = &fakes.HCSQuery{}
 		stateFactory = &fakes.StateFactory{}
 		sm = &fakes.StateManager{}
@@ -46,7 +50,7 @@ var _ = Describe("Delete", func() {
 		stateFactory.NewManagerReturns(sm)
 		containerFactory.NewManagerReturns(cm)
 
-		r = runtime.New(stateFactory, containerFactory, mounter, hcsQuery, processWrapper, rootDir, credentialSpecPath)
+		r = runtime.New(stateFactory, containerFactory, mounter, layerManager, streamer, hcsQuery, processWrapper, rootDir, credentialSpecPath, false)
 	})
 
 	BeforeEach(func() {
@@ -72,6 +76,38 @@ var _ = Describe("Delete", func() {
 		Expect(cm.DeleteArgsForCall(0)).To(BeTrue())
 	})
 
+	Context("when winc mounted the container layers", func() {
+		BeforeEach(func() {
+			state := &specs.State{
+				Status:      "stopped",
+				Bundle:      bundlePath,
+				Pid:         99,
+				Annotations: map[string]string{"winc.sandbox-layer": "C:\\sandboxes\\my-sandbox"},
+			}
+			sm.StateReturns(state, nil)
+		})
+
+		It("unmounts the sandbox after deleting the container, then deletes the state", func() {
+			Expect(r.Delete(containerId, true)).To(Succeed())
+			Expect(cm.DeleteCallCount()).To(Equal(1))
+			Expect(layerManager.UnmountArgsForCall(0)).To(Equal("C:\\sandboxes\\my-sandbox"))
+			Expect(sm.DeleteCallCount()).To(Equal(1))
+		})
+
+		Context("unmounting the sandbox fails", func() {
+			BeforeEach(func() {
+				layerManager.UnmountReturns(errors.New("couldn't unmount"))
+			})
+
+			It("returns the error and keeps the state recording the sandbox", func() {
+				err := r.Delete(containerId, true)
+				Expect(err).To(MatchError("couldn't unmount"))
+				Expect(cm.DeleteCallCount()).To(Equal(1))
+				Expect(sm.DeleteCallCount()).To(Equal(0))
+			})
+		})
+	})
+
 	Context("getting state fails", func() {
 		Context("force is true", func() {
 			Context("the error is hcs.NotFoundError", func() {
@@ -102,6 +138,32 @@ var _ = Describe("Delete", func() {
 					Expect(cm.DeleteArgsForCall(0)).To(BeTrue())
 				})
 			})
+
+			Context("the error is hcs.NotFoundError and winc mounted the container layers", func() {
+				BeforeEach(func() {
+					sm.StateReturns(nil, &hcs.NotFoundError{})
+					sm.LayersReturns("C:\\sandboxes\\my-sandbox", "some-volume-path", nil)
+				})
+
+				It("unmounts the sandbox and removes the state", func() {
+					Expect(r.Delete(containerId, true)).To(Succeed())
+
+					Expect(layerManager.UnmountArgsForCall(0)).To(Equal("C:\\sandboxes\\my-sandbox"))
+					Expect(sm.DeleteCallCount()).To(Equal(1))
+					Expect(cm.DeleteCallCount()).To(Equal(0))
+				})
+
+				Context("unmounting the sandbox fails", func() {
+					BeforeEach(func() {
+						layerManager.UnmountReturns(errors.New("couldn't unmount"))
+					})
+
+					It("returns the error and keeps the state", func() {
+						Expect(r.Delete(containerId, true)).To(MatchError("couldn't unmount"))
+						Expect(sm.DeleteCallCount()).To(Equal(0))
+					})
+				})
+			})
 		})
 
 		Context("force is false", func() {
@@ -189,12 +251,13 @@ var _ = Describe("Delete", func() {
 			cm.DeleteReturns(errors.New("couldn't delete container"))
 		})
 
-		It("returns an error", func() {
+		It("returns an error and keeps the state", func() {
 			err := r.Delete(containerId, true)
 			Expect(err).To(MatchError("couldn't delete container"))
 
 			Expect(mounter.UnmountCallCount()).To(Equal(1))
-			Expect(sm.DeleteCallCount()).To(Equal(1))
+			Expect(sm.DeleteCallCount()).To(Equal(0))
+			Expect(layerManager.UnmountCallCount()).To(Equal(0))
 			Expect(cm.DeleteArgsForCall(0)).To(BeTrue())
 		})
 	})
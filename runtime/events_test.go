@@ -20,6 +20,8 @@ var _ = Describe("Events", func() {
 	)
 	var (
 		mounter            *fakes.Mounter
+		layerManager       *fakes.LayerManager
+		streamer           *fakes.Streamer
 		stateFactory       *fakes.StateFactory
 		sm                 *fakes.StateManager
 		containerFactory   *fakes.ContainerFactory
@@ -33,6 +35,8 @@ var _ = Describe("Events", func() {
 
 	BeforeEach(func() {
 		mounter = &fakes.Mounter{}
+		layerManager = &fakes.LayerManager{}
+		streamer = &fakes.Streamer{}
 		hcsQuery = &fakes.HCSQuery{}
 		stateFactory = &fakes.StateFactory{}
 		sm = &fakes.StateManager{}
@@ -45,7 +49,7 @@ var _ = Describe("Events", func() {
 
 		output = gbytes.NewBuffer()
 
-		r = runtime.New(stateFactory, containerFactory, mounter, hcsQuery, processWrapper, rootDir, credentialSpecPath)
+		r = runtime.New(stateFactory, containerFactory, mounter, layerManager, streamer, hcsQuery, processWrapper, rootDir, credentialSpecPath, false)
 	})
 
 	Context("show stats is true", func() {
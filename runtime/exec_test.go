@@ -26,6 +26,8 @@ var _ = Describe("Exec", func() {
 	)
 	var (
 		mounter            *fakes.Mounter
+		layerManager       *fakes.LayerManager
+		streamer           *fakes.Streamer
 		stateFactory       *fakes.StateFactory
 		sm                 *fakes.StateManager
 		containerFactory   *fakes.ContainerFactory
@@ -46,6 +48,8 @@ var _ = Describe("Exec", func() {
 
 	BeforeEach(func() {
 		mounter = &fakes.Mounter{}
+		layerManager = &fakes.LayerManager{}
+		streamer = &fakes.Streamer{}
 		hcsQuery = &fakes.HCSQuery{}
 		stateFactory = &fakes.StateFactory{}
 		sm = &fakes.StateManager{}
@@ -62,7 +66,7 @@ var _ = Describe("Exec", func() {
 		Expect(err).NotTo(HaveOccurred())
 		processSpecFile = filepath.Join(processSpecDir, "process.json")
 
-		r = runtime.New(stateFactory, containerFactory, mounter, hcsQuery, processWrapper, rootDir, credentialSpecPath)
+		r = runtime.New(stateFactory, containerFactory, mounter, layerManager, streamer, hcsQuery, processWrapper, rootDir, credentialSpecPath, false)
 
 		processSpec := specs.Process{
 			User: specs.User{Username: "some-user"},
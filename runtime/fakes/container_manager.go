@@ -23,18 +23,22 @@ type ContainerManager struct {
 	createReturnsOnCall map[int]struct {
 		result1 error
 	}
-	CredentialSpecStub        func(string) (string, error)
+	CredentialSpecStub        func(*specs.Spec, string, string) (string, string, error)
 	credentialSpecMutex       sync.RWMutex
 	credentialSpecArgsForCall []struct {
-		arg1 string
+		arg1 *specs.Spec
+		arg2 string
+		arg3 string
 	}
 	credentialSpecReturns struct {
 		result1 string
-		result2 error
+		result2 string
+		result3 error
 	}
 	credentialSpecReturnsOnCall map[int]struct {
 		result1 string
-		result2 error
+		result2 string
+		result3 error
 	}
 	DeleteStub        func(bool) error
 	deleteMutex       sync.RWMutex
@@ -61,6 +65,19 @@ type ContainerManager struct {
 		result1 hcs.Process
 		result2 error
 	}
+	LayerSpecStub        func(string) (*specs.Spec, error)
+	layerSpecMutex       sync.RWMutex
+	layerSpecArgsForCall []struct {
+		arg1 string
+	}
+	layerSpecReturns struct {
+		result1 *specs.Spec
+		result2 error
+	}
+	layerSpecReturnsOnCall map[int]struct {
+		result1 *specs.Spec
+		result2 error
+	}
 	SpecStub        func(string) (*specs.Spec, error)
 	specMutex       sync.RWMutex
 	specArgsForCall []struct {
@@ -152,23 +169,25 @@ func (fake *ContainerManager) CreateReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *ContainerManager) CredentialSpec(arg1 string) (string, error) {
+func (fake *ContainerManager) CredentialSpec(arg1 *specs.Spec, arg2 string, arg3 string) (string, string, error) {
 	fake.credentialSpecMutex.Lock()
 	ret, specificReturn := fake.credentialSpecReturnsOnCall[len(fake.credentialSpecArgsForCall)]
 	fake.credentialSpecArgsForCall = append(fake.credentialSpecArgsForCall, struct {
-		arg1 string
-	}{arg1})
+		arg1 *specs.Spec
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
 	stub := fake.CredentialSpecStub
 	fakeReturns := fake.credentialSpecReturns
-	fake.recordInvocation("CredentialSpec", []interface{}{arg1})
+	fake.recordInvocation("CredentialSpec", []interface{}{arg1, arg2, arg3})
 	fake.credentialSpecMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
-		return ret.result1, ret.result2
+		return ret.result1, ret.result2, ret.result3
 	}
-	return fakeReturns.result1, fakeReturns.result2
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
 }
 
 func (fake *ContainerManager) CredentialSpecCallCount() int {
@@ -177,43 +196,46 @@ func (fake *ContainerManager) CredentialSpecCallCount() int {
 	return len(fake.credentialSpecArgsForCall)
 }
 
-func (fake *ContainerManager) CredentialSpecCalls(stub func(string) (string, error)) {
+func (fake *ContainerManager) CredentialSpecCalls(stub func(*specs.Spec, string, string) (string, string, error)) {
 	fake.credentialSpecMutex.Lock()
 	defer fake.credentialSpecMutex.Unlock()
 	fake.CredentialSpecStub = stub
 }
 
-func (fake *ContainerManager) CredentialSpecArgsForCall(i int) string {
+func (fake *ContainerManager) CredentialSpecArgsForCall(i int) (*specs.Spec, string, string) {
 	fake.credentialSpecMutex.RLock()
 	defer fake.credentialSpecMutex.RUnlock()
 	argsForCall := fake.credentialSpecArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
-func (fake *ContainerManager) CredentialSpecReturns(result1 string, result2 error) {
+func (fake *ContainerManager) CredentialSpecReturns(result1 string, result2 string, result3 error) {
 	fake.credentialSpecMutex.Lock()
 	defer fake.credentialSpecMutex.Unlock()
 	fake.CredentialSpecStub = nil
 	fake.credentialSpecReturns = struct {
 		result1 string
-		result2 error
-	}{result1, result2}
+		result2 string
+		result3 error
+	}{result1, result2, result3}
 }
 
-func (fake *ContainerManager) CredentialSpecReturnsOnCall(i int, result1 string, result2 error) {
+func (fake *ContainerManager) CredentialSpecReturnsOnCall(i int, result1 string, result2 string, result3 error) {
 	fake.credentialSpecMutex.Lock()
 	defer fake.credentialSpecMutex.Unlock()
 	fake.CredentialSpecStub = nil
 	if fake.credentialSpecReturnsOnCall == nil {
 		fake.credentialSpecReturnsOnCall = make(map[int]struct {
 			result1 string
-			result2 error
+			result2 string
+			result3 error
 		})
 	}
 	fake.credentialSpecReturnsOnCall[i] = struct {
 		result1 string
-		result2 error
-	}{result1, result2}
+		result2 string
+		result3 error
+	}{result1, result2, result3}
 }
 
 func (fake *ContainerManager) Delete(arg1 bool) error {
@@ -342,6 +364,70 @@ func (fake *ContainerManager) ExecReturnsOnCall(i int, result1 hcs.Process, resu
 	}{result1, result2}
 }
 
+func (fake *ContainerManager) LayerSpec(arg1 string) (*specs.Spec, error) {
+	fake.layerSpecMutex.Lock()
+	ret, specificReturn := fake.layerSpecReturnsOnCall[len(fake.layerSpecArgsForCall)]
+	fake.layerSpecArgsForCall = append(fake.layerSpecArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.LayerSpecStub
+	fakeReturns := fake.layerSpecReturns
+	fake.recordInvocation("LayerSpec", []interface{}{arg1})
+	fake.layerSpecMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *ContainerManager) LayerSpecCallCount() int {
+	fake.layerSpecMutex.RLock()
+	defer fake.layerSpecMutex.RUnlock()
+	return len(fake.layerSpecArgsForCall)
+}
+
+func (fake *ContainerManager) LayerSpecCalls(stub func(string) (*specs.Spec, error)) {
+	fake.layerSpecMutex.Lock()
+	defer fake.layerSpecMutex.Unlock()
+	fake.LayerSpecStub = stub
+}
+
+func (fake *ContainerManager) LayerSpecArgsForCall(i int) string {
+	fake.layerSpecMutex.RLock()
+	defer fake.layerSpecMutex.RUnlock()
+	argsForCall := fake.layerSpecArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *ContainerManager) LayerSpecReturns(result1 *specs.Spec, result2 error) {
+	fake.layerSpecMutex.Lock()
+	defer fake.layerSpecMutex.Unlock()
+	fake.LayerSpecStub = nil
+	fake.layerSpecReturns = struct {
+		result1 *specs.Spec
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ContainerManager) LayerSpecReturnsOnCall(i int, result1 *specs.Spec, result2 error) {
+	fake.layerSpecMutex.Lock()
+	defer fake.layerSpecMutex.Unlock()
+	fake.LayerSpecStub = nil
+	if fake.layerSpecReturnsOnCall == nil {
+		fake.layerSpecReturnsOnCall = make(map[int]struct {
+			result1 *specs.Spec
+			result2 error
+		})
+	}
+	fake.layerSpecReturnsOnCall[i] = struct {
+		result1 *specs.Spec
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *ContainerManager) Spec(arg1 string) (*specs.Spec, error) {
 	fake.specMutex.Lock()
 	ret, specificReturn := fake.specReturnsOnCall[len(fake.specArgsForCall)]
@@ -473,6 +559,8 @@ func (fake *ContainerManager) Invocations() map[string][][]interface{} {
 	defer fake.deleteMutex.RUnlock()
 	fake.execMutex.RLock()
 	defer fake.execMutex.RUnlock()
+	fake.layerSpecMutex.RLock()
+	defer fake.layerSpecMutex.RUnlock()
 	fake.specMutex.RLock()
 	defer fake.specMutex.RUnlock()
 	fake.statsMutex.RLock()
@@ -9,12 +9,12 @@ import (
 )
 
 type Mounter struct {
-	MountStub        func(pid int, volumePath string, logger *logrus.Entry) error
+	MountStub        func(int, string, *logrus.Entry) error
 	mountMutex       sync.RWMutex
 	mountArgsForCall []struct {
-		pid        int
-		volumePath string
-		logger     *logrus.Entry
+		arg1 int
+		arg2 string
+		arg3 *logrus.Entry
 	}
 	mountReturns struct {
 		result1 error
@@ -22,10 +22,44 @@ type Mounter struct {
 	mountReturnsOnCall map[int]struct {
 		result1 error
 	}
-	UnmountStub        func(pid int) error
+	MountPathStub        func(int) string
+	mountPathMutex       sync.RWMutex
+	mountPathArgsForCall []struct {
+		arg1 int
+	}
+	mountPathReturns struct {
+		result1 string
+	}
+	mountPathReturnsOnCall map[int]struct {
+		result1 string
+	}
+	PidsStub        func() ([]int, error)
+	pidsMutex       sync.RWMutex
+	pidsArgsForCall []struct {
+	}
+	pidsReturns struct {
+		result1 []int
+		result2 error
+	}
+	pidsReturnsOnCall map[int]struct {
+		result1 []int
+		result2 error
+	}
+	RootPathStub        func(int) string
+	rootPathMutex       sync.RWMutex
+	rootPathArgsForCall []struct {
+		arg1 int
+	}
+	rootPathReturns struct {
+		result1 string
+	}
+	rootPathReturnsOnCall map[int]struct {
+		result1 string
+	}
+	UnmountStub        func(int) error
 	unmountMutex       sync.RWMutex
 	unmountArgsForCall []struct {
-		pid int
+		arg1 int
 	}
 	unmountReturns struct {
 		result1 error
@@ -37,23 +71,25 @@ type Mounter struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *Mounter) Mount(pid int, volumePath string, logger *logrus.Entry) error {
+func (fake *Mounter) Mount(arg1 int, arg2 string, arg3 *logrus.Entry) error {
 	fake.mountMutex.Lock()
 	ret, specificReturn := fake.mountReturnsOnCall[len(fake.mountArgsForCall)]
 	fake.mountArgsForCall = append(fake.mountArgsForCall, struct {
-		pid        int
-		volumePath string
-		logger     *logrus.Entry
-	}{pid, volumePath, logger})
-	fake.recordInvocation("Mount", []interface{}{pid, volumePath, logger})
+		arg1 int
+		arg2 string
+		arg3 *logrus.Entry
+	}{arg1, arg2, arg3})
+	stub := fake.MountStub
+	fakeReturns := fake.mountReturns
+	fake.recordInvocation("Mount", []interface{}{arg1, arg2, arg3})
 	fake.mountMutex.Unlock()
-	if fake.MountStub != nil {
-		return fake.MountStub(pid, volumePath, logger)
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	return fake.mountReturns.result1
+	return fakeReturns.result1
 }
 
 func (fake *Mounter) MountCallCount() int {
@@ -62,13 +98,22 @@ func (fake *Mounter) MountCallCount() int {
 	return len(fake.mountArgsForCall)
 }
 
+func (fake *Mounter) MountCalls(stub func(int, string, *logrus.Entry) error) {
+	fake.mountMutex.Lock()
+	defer fake.mountMutex.Unlock()
+	fake.MountStub = stub
+}
+
 func (fake *Mounter) MountArgsForCall(i int) (int, string, *logrus.Entry) {
 	fake.mountMutex.RLock()
 	defer fake.mountMutex.RUnlock()
-	return fake.mountArgsForCall[i].pid, fake.mountArgsForCall[i].volumePath, fake.mountArgsForCall[i].logger
+	argsForCall := fake.mountArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *Mounter) MountReturns(result1 error) {
+	fake.mountMutex.Lock()
+	defer fake.mountMutex.Unlock()
 	fake.MountStub = nil
 	fake.mountReturns = struct {
 		result1 error
@@ -76,6 +121,8 @@ func (fake *Mounter) MountReturns(result1 error) {
 }
 
 func (fake *Mounter) MountReturnsOnCall(i int, result1 error) {
+	fake.mountMutex.Lock()
+	defer fake.mountMutex.Unlock()
 	fake.MountStub = nil
 	if fake.mountReturnsOnCall == nil {
 		fake.mountReturnsOnCall = make(map[int]struct {
@@ -87,21 +134,201 @@ func (fake *Mounter) MountReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *Mounter) Unmount(pid int) error {
+func (fake *Mounter) MountPath(arg1 int) string {
+	fake.mountPathMutex.Lock()
+	ret, specificReturn := fake.mountPathReturnsOnCall[len(fake.mountPathArgsForCall)]
+	fake.mountPathArgsForCall = append(fake.mountPathArgsForCall, struct {
+		arg1 int
+	}{arg1})
+	stub := fake.MountPathStub
+	fakeReturns := fake.mountPathReturns
+	fake.recordInvocation("MountPath", []interface{}{arg1})
+	fake.mountPathMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *Mounter) MountPathCallCount() int {
+	fake.mountPathMutex.RLock()
+	defer fake.mountPathMutex.RUnlock()
+	return len(fake.mountPathArgsForCall)
+}
+
+func (fake *Mounter) MountPathCalls(stub func(int) string) {
+	fake.mountPathMutex.Lock()
+	defer fake.mountPathMutex.Unlock()
+	fake.MountPathStub = stub
+}
+
+func (fake *Mounter) MountPathArgsForCall(i int) int {
+	fake.mountPathMutex.RLock()
+	defer fake.mountPathMutex.RUnlock()
+	argsForCall := fake.mountPathArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *Mounter) MountPathReturns(result1 string) {
+	fake.mountPathMutex.Lock()
+	defer fake.mountPathMutex.Unlock()
+	fake.MountPathStub = nil
+	fake.mountPathReturns = struct {
+		result1 string
+	}{result1}
+}
+
+func (fake *Mounter) MountPathReturnsOnCall(i int, result1 string) {
+	fake.mountPathMutex.Lock()
+	defer fake.mountPathMutex.Unlock()
+	fake.MountPathStub = nil
+	if fake.mountPathReturnsOnCall == nil {
+		fake.mountPathReturnsOnCall = make(map[int]struct {
+			result1 string
+		})
+	}
+	fake.mountPathReturnsOnCall[i] = struct {
+		result1 string
+	}{result1}
+}
+
+func (fake *Mounter) Pids() ([]int, error) {
+	fake.pidsMutex.Lock()
+	ret, specificReturn := fake.pidsReturnsOnCall[len(fake.pidsArgsForCall)]
+	fake.pidsArgsForCall = append(fake.pidsArgsForCall, struct {
+	}{})
+	stub := fake.PidsStub
+	fakeReturns := fake.pidsReturns
+	fake.recordInvocation("Pids", []interface{}{})
+	fake.pidsMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *Mounter) PidsCallCount() int {
+	fake.pidsMutex.RLock()
+	defer fake.pidsMutex.RUnlock()
+	return len(fake.pidsArgsForCall)
+}
+
+func (fake *Mounter) PidsCalls(stub func() ([]int, error)) {
+	fake.pidsMutex.Lock()
+	defer fake.pidsMutex.Unlock()
+	fake.PidsStub = stub
+}
+
+func (fake *Mounter) PidsReturns(result1 []int, result2 error) {
+	fake.pidsMutex.Lock()
+	defer fake.pidsMutex.Unlock()
+	fake.PidsStub = nil
+	fake.pidsReturns = struct {
+		result1 []int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Mounter) PidsReturnsOnCall(i int, result1 []int, result2 error) {
+	fake.pidsMutex.Lock()
+	defer fake.pidsMutex.Unlock()
+	fake.PidsStub = nil
+	if fake.pidsReturnsOnCall == nil {
+		fake.pidsReturnsOnCall = make(map[int]struct {
+			result1 []int
+			result2 error
+		})
+	}
+	fake.pidsReturnsOnCall[i] = struct {
+		result1 []int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Mounter) RootPath(arg1 int) string {
+	fake.rootPathMutex.Lock()
+	ret, specificReturn := fake.rootPathReturnsOnCall[len(fake.rootPathArgsForCall)]
+	fake.rootPathArgsForCall = append(fake.rootPathArgsForCall, struct {
+		arg1 int
+	}{arg1})
+	stub := fake.RootPathStub
+	fakeReturns := fake.rootPathReturns
+	fake.recordInvocation("RootPath", []interface{}{arg1})
+	fake.rootPathMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *Mounter) RootPathCallCount() int {
+	fake.rootPathMutex.RLock()
+	defer fake.rootPathMutex.RUnlock()
+	return len(fake.rootPathArgsForCall)
+}
+
+func (fake *Mounter) RootPathCalls(stub func(int) string) {
+	fake.rootPathMutex.Lock()
+	defer fake.rootPathMutex.Unlock()
+	fake.RootPathStub = stub
+}
+
+func (fake *Mounter) RootPathArgsForCall(i int) int {
+	fake.rootPathMutex.RLock()
+	defer fake.rootPathMutex.RUnlock()
+	argsForCall := fake.rootPathArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *Mounter) RootPathReturns(result1 string) {
+	fake.rootPathMutex.Lock()
+	defer fake.rootPathMutex.Unlock()
+	fake.RootPathStub = nil
+	fake.rootPathReturns = struct {
+		result1 string
+	}{result1}
+}
+
+func (fake *Mounter) RootPathReturnsOnCall(i int, result1 string) {
+	fake.rootPathMutex.Lock()
+	defer fake.rootPathMutex.Unlock()
+	fake.RootPathStub = nil
+	if fake.rootPathReturnsOnCall == nil {
+		fake.rootPathReturnsOnCall = make(map[int]struct {
+			result1 string
+		})
+	}
+	fake.rootPathReturnsOnCall[i] = struct {
+		result1 string
+	}{result1}
+}
+
+func (fake *Mounter) Unmount(arg1 int) error {
 	fake.unmountMutex.Lock()
 	ret, specificReturn := fake.unmountReturnsOnCall[len(fake.unmountArgsForCall)]
 	fake.unmountArgsForCall = append(fake.unmountArgsForCall, struct {
-		pid int
-	}{pid})
-	fake.recordInvocation("Unmount", []interface{}{pid})
+		arg1 int
+	}{arg1})
+	stub := fake.UnmountStub
+	fakeReturns := fake.unmountReturns
+	fake.recordInvocation("Unmount", []interface{}{arg1})
 	fake.unmountMutex.Unlock()
-	if fake.UnmountStub != nil {
-		return fake.UnmountStub(pid)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	return fake.unmountReturns.result1
+	return fakeReturns.result1
 }
 
 func (fake *Mounter) UnmountCallCount() int {
@@ -110,13 +337,22 @@ func (fake *Mounter) UnmountCallCount() int {
 	return len(fake.unmountArgsForCall)
 }
 
+func (fake *Mounter) UnmountCalls(stub func(int) error) {
+	fake.unmountMutex.Lock()
+	defer fake.unmountMutex.Unlock()
+	fake.UnmountStub = stub
+}
+
 func (fake *Mounter) UnmountArgsForCall(i int) int {
 	fake.unmountMutex.RLock()
 	defer fake.unmountMutex.RUnlock()
-	return fake.unmountArgsForCall[i].pid
+	argsForCall := fake.unmountArgsForCall[i]
+	return argsForCall.arg1
 }
 
 func (fake *Mounter) UnmountReturns(result1 error) {
+	fake.unmountMutex.Lock()
+	defer fake.unmountMutex.Unlock()
 	fake.UnmountStub = nil
 	fake.unmountReturns = struct {
 		result1 error
@@ -124,6 +360,8 @@ func (fake *Mounter) UnmountReturns(result1 error) {
 }
 
 func (fake *Mounter) UnmountReturnsOnCall(i int, result1 error) {
+	fake.unmountMutex.Lock()
+	defer fake.unmountMutex.Unlock()
 	fake.UnmountStub = nil
 	if fake.unmountReturnsOnCall == nil {
 		fake.unmountReturnsOnCall = make(map[int]struct {
@@ -140,9 +378,19 @@ func (fake *Mounter) Invocations() map[string][][]interface{} {
 	defer fake.invocationsMutex.RUnlock()
 	fake.mountMutex.RLock()
 	defer fake.mountMutex.RUnlock()
+	fake.mountPathMutex.RLock()
+	defer fake.mountPathMutex.RUnlock()
+	fake.pidsMutex.RLock()
+	defer fake.pidsMutex.RUnlock()
+	fake.rootPathMutex.RLock()
+	defer fake.rootPathMutex.RUnlock()
 	fake.unmountMutex.RLock()
 	defer fake.unmountMutex.RUnlock()
-	return fake.invocations
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
 }
 
 func (fake *Mounter) recordInvocation(key string, args []interface{}) {
@@ -10,10 +10,23 @@ import (
 )
 
 type StateManager struct {
-	InitializeStub        func(string) error
+	DeleteStub        func() error
+	deleteMutex       sync.RWMutex
+	deleteArgsForCall []struct {
+	}
+	deleteReturns struct {
+		result1 error
+	}
+	deleteReturnsOnCall map[int]struct {
+		result1 error
+	}
+	InitializeStub        func(string, string, string, string) error
 	initializeMutex       sync.RWMutex
 	initializeArgsForCall []struct {
 		arg1 string
+		arg2 string
+		arg3 string
+		arg4 string
 	}
 	initializeReturns struct {
 		result1 error
@@ -21,19 +34,25 @@ type StateManager struct {
 	initializeReturnsOnCall map[int]struct {
 		result1 error
 	}
-	DeleteStub        func() error
-	deleteMutex       sync.RWMutex
-	deleteArgsForCall []struct{}
-	deleteReturns     struct {
-		result1 error
+	LayersStub        func() (string, string, error)
+	layersMutex       sync.RWMutex
+	layersArgsForCall []struct {
 	}
-	deleteReturnsOnCall map[int]struct {
-		result1 error
+	layersReturns struct {
+		result1 string
+		result2 string
+		result3 error
+	}
+	layersReturnsOnCall map[int]struct {
+		result1 string
+		result2 string
+		result3 error
 	}
 	SetFailureStub        func() error
 	setFailureMutex       sync.RWMutex
-	setFailureArgsForCall []struct{}
-	setFailureReturns     struct {
+	setFailureArgsForCall []struct {
+	}
+	setFailureReturns struct {
 		result1 error
 	}
 	setFailureReturnsOnCall map[int]struct {
@@ -52,8 +71,9 @@ type StateManager struct {
 	}
 	StateStub        func() (*specs.State, error)
 	stateMutex       sync.RWMutex
-	stateArgsForCall []struct{}
-	stateReturns     struct {
+	stateArgsForCall []struct {
+	}
+	stateReturns struct {
 		result1 *specs.State
 		result2 error
 	}
@@ -65,21 +85,79 @@ type StateManager struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *StateManager) Initialize(arg1 string) error {
+func (fake *StateManager) Delete() error {
+	fake.deleteMutex.Lock()
+	ret, specificReturn := fake.deleteReturnsOnCall[len(fake.deleteArgsForCall)]
+	fake.deleteArgsForCall = append(fake.deleteArgsForCall, struct {
+	}{})
+	stub := fake.DeleteStub
+	fakeReturns := fake.deleteReturns
+	fake.recordInvocation("Delete", []interface{}{})
+	fake.deleteMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *StateManager) DeleteCallCount() int {
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	return len(fake.deleteArgsForCall)
+}
+
+func (fake *StateManager) DeleteCalls(stub func() error) {
+	fake.deleteMutex.Lock()
+	defer fake.deleteMutex.Unlock()
+	fake.DeleteStub = stub
+}
+
+func (fake *StateManager) DeleteReturns(result1 error) {
+	fake.deleteMutex.Lock()
+	defer fake.deleteMutex.Unlock()
+	fake.DeleteStub = nil
+	fake.deleteReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *StateManager) DeleteReturnsOnCall(i int, result1 error) {
+	fake.deleteMutex.Lock()
+	defer fake.deleteMutex.Unlock()
+	fake.DeleteStub = nil
+	if fake.deleteReturnsOnCall == nil {
+		fake.deleteReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *StateManager) Initialize(arg1 string, arg2 string, arg3 string, arg4 string) error {
 	fake.initializeMutex.Lock()
 	ret, specificReturn := fake.initializeReturnsOnCall[len(fake.initializeArgsForCall)]
 	fake.initializeArgsForCall = append(fake.initializeArgsForCall, struct {
 		arg1 string
-	}{arg1})
-	fake.recordInvocation("Initialize", []interface{}{arg1})
+		arg2 string
+		arg3 string
+		arg4 string
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.InitializeStub
+	fakeReturns := fake.initializeReturns
+	fake.recordInvocation("Initialize", []interface{}{arg1, arg2, arg3, arg4})
 	fake.initializeMutex.Unlock()
-	if fake.InitializeStub != nil {
-		return fake.InitializeStub(arg1)
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	return fake.initializeReturns.result1
+	return fakeReturns.result1
 }
 
 func (fake *StateManager) InitializeCallCount() int {
@@ -88,13 +166,22 @@ func (fake *StateManager) InitializeCallCount() int {
 	return len(fake.initializeArgsForCall)
 }
 
-func (fake *StateManager) InitializeArgsForCall(i int) string {
+func (fake *StateManager) InitializeCalls(stub func(string, string, string, string) error) {
+	fake.initializeMutex.Lock()
+	defer fake.initializeMutex.Unlock()
+	fake.InitializeStub = stub
+}
+
+func (fake *StateManager) InitializeArgsForCall(i int) (string, string, string, string) {
 	fake.initializeMutex.RLock()
 	defer fake.initializeMutex.RUnlock()
-	return fake.initializeArgsForCall[i].arg1
+	argsForCall := fake.initializeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
 func (fake *StateManager) InitializeReturns(result1 error) {
+	fake.initializeMutex.Lock()
+	defer fake.initializeMutex.Unlock()
 	fake.InitializeStub = nil
 	fake.initializeReturns = struct {
 		result1 error
@@ -102,6 +189,8 @@ func (fake *StateManager) InitializeReturns(result1 error) {
 }
 
 func (fake *StateManager) InitializeReturnsOnCall(i int, result1 error) {
+	fake.initializeMutex.Lock()
+	defer fake.initializeMutex.Unlock()
 	fake.InitializeStub = nil
 	if fake.initializeReturnsOnCall == nil {
 		fake.initializeReturnsOnCall = make(map[int]struct {
@@ -113,59 +202,81 @@ func (fake *StateManager) InitializeReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
-func (fake *StateManager) Delete() error {
-	fake.deleteMutex.Lock()
-	ret, specificReturn := fake.deleteReturnsOnCall[len(fake.deleteArgsForCall)]
-	fake.deleteArgsForCall = append(fake.deleteArgsForCall, struct{}{})
-	fake.recordInvocation("Delete", []interface{}{})
-	fake.deleteMutex.Unlock()
-	if fake.DeleteStub != nil {
-		return fake.DeleteStub()
+func (fake *StateManager) Layers() (string, string, error) {
+	fake.layersMutex.Lock()
+	ret, specificReturn := fake.layersReturnsOnCall[len(fake.layersArgsForCall)]
+	fake.layersArgsForCall = append(fake.layersArgsForCall, struct {
+	}{})
+	stub := fake.LayersStub
+	fakeReturns := fake.layersReturns
+	fake.recordInvocation("Layers", []interface{}{})
+	fake.layersMutex.Unlock()
+	if stub != nil {
+		return stub()
 	}
 	if specificReturn {
-		return ret.result1
+		return ret.result1, ret.result2, ret.result3
 	}
-	return fake.deleteReturns.result1
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
 }
 
-func (fake *StateManager) DeleteCallCount() int {
-	fake.deleteMutex.RLock()
-	defer fake.deleteMutex.RUnlock()
-	return len(fake.deleteArgsForCall)
+func (fake *StateManager) LayersCallCount() int {
+	fake.layersMutex.RLock()
+	defer fake.layersMutex.RUnlock()
+	return len(fake.layersArgsForCall)
 }
 
-func (fake *StateManager) DeleteReturns(result1 error) {
-	fake.DeleteStub = nil
-	fake.deleteReturns = struct {
-		result1 error
-	}{result1}
+func (fake *StateManager) LayersCalls(stub func() (string, string, error)) {
+	fake.layersMutex.Lock()
+	defer fake.layersMutex.Unlock()
+	fake.LayersStub = stub
 }
 
-func (fake *StateManager) DeleteReturnsOnCall(i int, result1 error) {
-	fake.DeleteStub = nil
-	if fake.deleteReturnsOnCall == nil {
-		fake.deleteReturnsOnCall = make(map[int]struct {
-			result1 error
+func (fake *StateManager) LayersReturns(result1 string, result2 string, result3 error) {
+	fake.layersMutex.Lock()
+	defer fake.layersMutex.Unlock()
+	fake.LayersStub = nil
+	fake.layersReturns = struct {
+		result1 string
+		result2 string
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *StateManager) LayersReturnsOnCall(i int, result1 string, result2 string, result3 error) {
+	fake.layersMutex.Lock()
+	defer fake.layersMutex.Unlock()
+	fake.LayersStub = nil
+	if fake.layersReturnsOnCall == nil {
+		fake.layersReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 string
+			result3 error
 		})
 	}
-	fake.deleteReturnsOnCall[i] = struct {
-		result1 error
-	}{result1}
+	fake.layersReturnsOnCall[i] = struct {
+		result1 string
+		result2 string
+		result3 error
+	}{result1, result2, result3}
 }
 
 func (fake *StateManager) SetFailure() error {
 	fake.setFailureMutex.Lock()
 	ret, specificReturn := fake.setFailureReturnsOnCall[len(fake.setFailureArgsForCall)]
-	fake.setFailureArgsForCall = append(fake.setFailureArgsForCall, struct{}{})
+	fake.setFailureArgsForCall = append(fake.setFailureArgsForCall, struct {
+	}{})
+	stub := fake.SetFailureStub
+	fakeReturns := fake.setFailureReturns
 	fake.recordInvocation("SetFailure", []interface{}{})
 	fake.setFailureMutex.Unlock()
-	if fake.SetFailureStub != nil {
-		return fake.SetFailureStub()
+	if stub != nil {
+		return stub()
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	return fake.setFailureReturns.result1
+	return fakeReturns.result1
 }
 
 func (fake *StateManager) SetFailureCallCount() int {
@@ -174,7 +285,15 @@ func (fake *StateManager) SetFailureCallCount() int {
 	return len(fake.setFailureArgsForCall)
 }
 
+func (fake *StateManager) SetFailureCalls(stub func() error) {
+	fake.setFailureMutex.Lock()
+	defer fake.setFailureMutex.Unlock()
+	fake.SetFailureStub = stub
+}
+
 func (fake *StateManager) SetFailureReturns(result1 error) {
+	fake.setFailureMutex.Lock()
+	defer fake.setFailureMutex.Unlock()
 	fake.SetFailureStub = nil
 	fake.setFailureReturns = struct {
 		result1 error
@@ -182,6 +301,8 @@ func (fake *StateManager) SetFailureReturns(result1 error) {
 }
 
 func (fake *StateManager) SetFailureReturnsOnCall(i int, result1 error) {
+	fake.setFailureMutex.Lock()
+	defer fake.setFailureMutex.Unlock()
 	fake.SetFailureStub = nil
 	if fake.setFailureReturnsOnCall == nil {
 		fake.setFailureReturnsOnCall = make(map[int]struct {
@@ -199,15 +320,17 @@ func (fake *StateManager) SetSuccess(arg1 hcs.Process) error {
 	fake.setSuccessArgsForCall = append(fake.setSuccessArgsForCall, struct {
 		arg1 hcs.Process
 	}{arg1})
+	stub := fake.SetSuccessStub
+	fakeReturns := fake.setSuccessReturns
 	fake.recordInvocation("SetSuccess", []interface{}{arg1})
 	fake.setSuccessMutex.Unlock()
-	if fake.SetSuccessStub != nil {
-		return fake.SetSuccessStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	return fake.setSuccessReturns.result1
+	return fakeReturns.result1
 }
 
 func (fake *StateManager) SetSuccessCallCount() int {
@@ -216,13 +339,22 @@ func (fake *StateManager) SetSuccessCallCount() int {
 	return len(fake.setSuccessArgsForCall)
 }
 
+func (fake *StateManager) SetSuccessCalls(stub func(hcs.Process) error) {
+	fake.setSuccessMutex.Lock()
+	defer fake.setSuccessMutex.Unlock()
+	fake.SetSuccessStub = stub
+}
+
 func (fake *StateManager) SetSuccessArgsForCall(i int) hcs.Process {
 	fake.setSuccessMutex.RLock()
 	defer fake.setSuccessMutex.RUnlock()
-	return fake.setSuccessArgsForCall[i].arg1
+	argsForCall := fake.setSuccessArgsForCall[i]
+	return argsForCall.arg1
 }
 
 func (fake *StateManager) SetSuccessReturns(result1 error) {
+	fake.setSuccessMutex.Lock()
+	defer fake.setSuccessMutex.Unlock()
 	fake.SetSuccessStub = nil
 	fake.setSuccessReturns = struct {
 		result1 error
@@ -230,6 +362,8 @@ func (fake *StateManager) SetSuccessReturns(result1 error) {
 }
 
 func (fake *StateManager) SetSuccessReturnsOnCall(i int, result1 error) {
+	fake.setSuccessMutex.Lock()
+	defer fake.setSuccessMutex.Unlock()
 	fake.SetSuccessStub = nil
 	if fake.setSuccessReturnsOnCall == nil {
 		fake.setSuccessReturnsOnCall = make(map[int]struct {
@@ -244,16 +378,19 @@ func (fake *StateManager) SetSuccessReturnsOnCall(i int, result1 error) {
 func (fake *StateManager) State() (*specs.State, error) {
 	fake.stateMutex.Lock()
 	ret, specificReturn := fake.stateReturnsOnCall[len(fake.stateArgsForCall)]
-	fake.stateArgsForCall = append(fake.stateArgsForCall, struct{}{})
+	fake.stateArgsForCall = append(fake.stateArgsForCall, struct {
+	}{})
+	stub := fake.StateStub
+	fakeReturns := fake.stateReturns
 	fake.recordInvocation("State", []interface{}{})
 	fake.stateMutex.Unlock()
-	if fake.StateStub != nil {
-		return fake.StateStub()
+	if stub != nil {
+		return stub()
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	return fake.stateReturns.result1, fake.stateReturns.result2
+	return fakeReturns.result1, fakeReturns.result2
 }
 
 func (fake *StateManager) StateCallCount() int {
@@ -262,7 +399,15 @@ func (fake *StateManager) StateCallCount() int {
 	return len(fake.stateArgsForCall)
 }
 
+func (fake *StateManager) StateCalls(stub func() (*specs.State, error)) {
+	fake.stateMutex.Lock()
+	defer fake.stateMutex.Unlock()
+	fake.StateStub = stub
+}
+
 func (fake *StateManager) StateReturns(result1 *specs.State, result2 error) {
+	fake.stateMutex.Lock()
+	defer fake.stateMutex.Unlock()
 	fake.StateStub = nil
 	fake.stateReturns = struct {
 		result1 *specs.State
@@ -271,6 +416,8 @@ func (fake *StateManager) StateReturns(result1 *specs.State, result2 error) {
 }
 
 func (fake *StateManager) StateReturnsOnCall(i int, result1 *specs.State, result2 error) {
+	fake.stateMutex.Lock()
+	defer fake.stateMutex.Unlock()
 	fake.StateStub = nil
 	if fake.stateReturnsOnCall == nil {
 		fake.stateReturnsOnCall = make(map[int]struct {
@@ -287,17 +434,23 @@ func (fake *StateManager) StateReturnsOnCall(i int, result1 *specs.State, result
 func (fake *StateManager) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
-	fake.initializeMutex.RLock()
-	defer fake.initializeMutex.RUnlock()
 	fake.deleteMutex.RLock()
 	defer fake.deleteMutex.RUnlock()
+	fake.initializeMutex.RLock()
+	defer fake.initializeMutex.RUnlock()
+	fake.layersMutex.RLock()
+	defer fake.layersMutex.RUnlock()
 	fake.setFailureMutex.RLock()
 	defer fake.setFailureMutex.RUnlock()
 	fake.setSuccessMutex.RLock()
 	defer fake.setSuccessMutex.RUnlock()
 	fake.stateMutex.RLock()
 	defer fake.stateMutex.RUnlock()
-	return fake.invocations
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
 }
 
 func (fake *StateManager) recordInvocation(key string, args []interface{}) {
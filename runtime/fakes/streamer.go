@@ -0,0 +1,350 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	"io"
+	"sync"
+
+	"code.cloudfoundry.org/winc/runtime"
+)
+
+type Streamer struct {
+	CopyInStub        func(string, string, string) error
+	copyInMutex       sync.RWMutex
+	copyInArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}
+	copyInReturns struct {
+		result1 error
+	}
+	copyInReturnsOnCall map[int]struct {
+		result1 error
+	}
+	CopyOutStub        func(string, string, string) error
+	copyOutMutex       sync.RWMutex
+	copyOutArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}
+	copyOutReturns struct {
+		result1 error
+	}
+	copyOutReturnsOnCall map[int]struct {
+		result1 error
+	}
+	StreamInStub        func(string, string, io.Reader) error
+	streamInMutex       sync.RWMutex
+	streamInArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 io.Reader
+	}
+	streamInReturns struct {
+		result1 error
+	}
+	streamInReturnsOnCall map[int]struct {
+		result1 error
+	}
+	StreamOutStub        func(string, string, io.Writer) error
+	streamOutMutex       sync.RWMutex
+	streamOutArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 io.Writer
+	}
+	streamOutReturns struct {
+		result1 error
+	}
+	streamOutReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *Streamer) CopyIn(arg1 string, arg2 string, arg3 string) error {
+	fake.copyInMutex.Lock()
+	ret, specificReturn := fake.copyInReturnsOnCall[len(fake.copyInArgsForCall)]
+	fake.copyInArgsForCall = append(fake.copyInArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.CopyInStub
+	fakeReturns := fake.copyInReturns
+	fake.recordInvocation("CopyIn", []interface{}{arg1, arg2, arg3})
+	fake.copyInMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *Streamer) CopyInCallCount() int {
+	fake.copyInMutex.RLock()
+	defer fake.copyInMutex.RUnlock()
+	return len(fake.copyInArgsForCall)
+}
+
+func (fake *Streamer) CopyInCalls(stub func(string, string, string) error) {
+	fake.copyInMutex.Lock()
+	defer fake.copyInMutex.Unlock()
+	fake.CopyInStub = stub
+}
+
+func (fake *Streamer) CopyInArgsForCall(i int) (string, string, string) {
+	fake.copyInMutex.RLock()
+	defer fake.copyInMutex.RUnlock()
+	argsForCall := fake.copyInArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *Streamer) CopyInReturns(result1 error) {
+	fake.copyInMutex.Lock()
+	defer fake.copyInMutex.Unlock()
+	fake.CopyInStub = nil
+	fake.copyInReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Streamer) CopyInReturnsOnCall(i int, result1 error) {
+	fake.copyInMutex.Lock()
+	defer fake.copyInMutex.Unlock()
+	fake.CopyInStub = nil
+	if fake.copyInReturnsOnCall == nil {
+		fake.copyInReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.copyInReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Streamer) CopyOut(arg1 string, arg2 string, arg3 string) error {
+	fake.copyOutMutex.Lock()
+	ret, specificReturn := fake.copyOutReturnsOnCall[len(fake.copyOutArgsForCall)]
+	fake.copyOutArgsForCall = append(fake.copyOutArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.CopyOutStub
+	fakeReturns := fake.copyOutReturns
+	fake.recordInvocation("CopyOut", []interface{}{arg1, arg2, arg3})
+	fake.copyOutMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *Streamer) CopyOutCallCount() int {
+	fake.copyOutMutex.RLock()
+	defer fake.copyOutMutex.RUnlock()
+	return len(fake.copyOutArgsForCall)
+}
+
+func (fake *Streamer) CopyOutCalls(stub func(string, string, string) error) {
+	fake.copyOutMutex.Lock()
+	defer fake.copyOutMutex.Unlock()
+	fake.CopyOutStub = stub
+}
+
+func (fake *Streamer) CopyOutArgsForCall(i int) (string, string, string) {
+	fake.copyOutMutex.RLock()
+	defer fake.copyOutMutex.RUnlock()
+	argsForCall := fake.copyOutArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *Streamer) CopyOutReturns(result1 error) {
+	fake.copyOutMutex.Lock()
+	defer fake.copyOutMutex.Unlock()
+	fake.CopyOutStub = nil
+	fake.copyOutReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Streamer) CopyOutReturnsOnCall(i int, result1 error) {
+	fake.copyOutMutex.Lock()
+	defer fake.copyOutMutex.Unlock()
+	fake.CopyOutStub = nil
+	if fake.copyOutReturnsOnCall == nil {
+		fake.copyOutReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.copyOutReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Streamer) StreamIn(arg1 string, arg2 string, arg3 io.Reader) error {
+	fake.streamInMutex.Lock()
+	ret, specificReturn := fake.streamInReturnsOnCall[len(fake.streamInArgsForCall)]
+	fake.streamInArgsForCall = append(fake.streamInArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 io.Reader
+	}{arg1, arg2, arg3})
+	stub := fake.StreamInStub
+	fakeReturns := fake.streamInReturns
+	fake.recordInvocation("StreamIn", []interface{}{arg1, arg2, arg3})
+	fake.streamInMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *Streamer) StreamInCallCount() int {
+	fake.streamInMutex.RLock()
+	defer fake.streamInMutex.RUnlock()
+	return len(fake.streamInArgsForCall)
+}
+
+func (fake *Streamer) StreamInCalls(stub func(string, string, io.Reader) error) {
+	fake.streamInMutex.Lock()
+	defer fake.streamInMutex.Unlock()
+	fake.StreamInStub = stub
+}
+
+func (fake *Streamer) StreamInArgsForCall(i int) (string, string, io.Reader) {
+	fake.streamInMutex.RLock()
+	defer fake.streamInMutex.RUnlock()
+	argsForCall := fake.streamInArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *Streamer) StreamInReturns(result1 error) {
+	fake.streamInMutex.Lock()
+	defer fake.streamInMutex.Unlock()
+	fake.StreamInStub = nil
+	fake.streamInReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Streamer) StreamInReturnsOnCall(i int, result1 error) {
+	fake.streamInMutex.Lock()
+	defer fake.streamInMutex.Unlock()
+	fake.StreamInStub = nil
+	if fake.streamInReturnsOnCall == nil {
+		fake.streamInReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.streamInReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Streamer) StreamOut(arg1 string, arg2 string, arg3 io.Writer) error {
+	fake.streamOutMutex.Lock()
+	ret, specificReturn := fake.streamOutReturnsOnCall[len(fake.streamOutArgsForCall)]
+	fake.streamOutArgsForCall = append(fake.streamOutArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 io.Writer
+	}{arg1, arg2, arg3})
+	stub := fake.StreamOutStub
+	fakeReturns := fake.streamOutReturns
+	fake.recordInvocation("StreamOut", []interface{}{arg1, arg2, arg3})
+	fake.streamOutMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *Streamer) StreamOutCallCount() int {
+	fake.streamOutMutex.RLock()
+	defer fake.streamOutMutex.RUnlock()
+	return len(fake.streamOutArgsForCall)
+}
+
+func (fake *Streamer) StreamOutCalls(stub func(string, string, io.Writer) error) {
+	fake.streamOutMutex.Lock()
+	defer fake.streamOutMutex.Unlock()
+	fake.StreamOutStub = stub
+}
+
+func (fake *Streamer) StreamOutArgsForCall(i int) (string, string, io.Writer) {
+	fake.streamOutMutex.RLock()
+	defer fake.streamOutMutex.RUnlock()
+	argsForCall := fake.streamOutArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *Streamer) StreamOutReturns(result1 error) {
+	fake.streamOutMutex.Lock()
+	defer fake.streamOutMutex.Unlock()
+	fake.StreamOutStub = nil
+	fake.streamOutReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Streamer) StreamOutReturnsOnCall(i int, result1 error) {
+	fake.streamOutMutex.Lock()
+	defer fake.streamOutMutex.Unlock()
+	fake.StreamOutStub = nil
+	if fake.streamOutReturnsOnCall == nil {
+		fake.streamOutReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.streamOutReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Streamer) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.copyInMutex.RLock()
+	defer fake.copyInMutex.RUnlock()
+	fake.copyOutMutex.RLock()
+	defer fake.copyOutMutex.RUnlock()
+	fake.streamInMutex.RLock()
+	defer fake.streamInMutex.RUnlock()
+	fake.streamOutMutex.RLock()
+	defer fake.streamOutMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *Streamer) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ runtime.Streamer = new(Streamer)
@@ -0,0 +1,240 @@
+package runtime_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"code.cloudfoundry.org/winc/hcs"
+	"code.cloudfoundry.org/winc/runtime"
+	"code.cloudfoundry.org/winc/runtime/fakes"
+	"code.cloudfoundry.org/winc/runtime/winsyscall"
+	"github.com/Microsoft/hcsshim"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/sirupsen/logrus"
+)
+
+var _ = Describe("GC", func() {
+	var (
+		rootDir            string
+		mounter            *fakes.Mounter
+		layerManager       *fakes.LayerManager
+		streamer           *fakes.Streamer
+		stateFactory       *fakes.StateFactory
+		liveSm             *fakes.StateManager
+		staleSm            *fakes.StateManager
+		containerFactory   *fakes.ContainerFactory
+		processWrapper     *fakes.ProcessWrapper
+		hcsQuery           *fakes.HCSQuery
+		credentialSpecPath string
+		r                  *runtime.Runtime
+		output             *gbytes.Buffer
+	)
+
+	BeforeEach(func() {
+		var err error
+		rootDir, err = ioutil.TempDir("", "gc.test")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(os.MkdirAll(filepath.Join(rootDir, "live-container"), 0755)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(rootDir, "stale-container"), 0755)).To(Succeed())
+
+		mounter = &fakes.Mounter{}
+		layerManager = &fakes.LayerManager{}
+		streamer = &fakes.Streamer{}
+		hcsQuery = &fakes.HCSQuery{}
+		stateFactory = &fakes.StateFactory{}
+		liveSm = &fakes.StateManager{}
+		staleSm = &fakes.StateManager{}
+		containerFactory = &fakes.ContainerFactory{}
+		processWrapper = &fakes.ProcessWrapper{}
+		output = gbytes.NewBuffer()
+
+		stateFactory.NewManagerStub = func(_ *logrus.Entry, _ *hcs.Client, _ *winsyscall.WinSyscall, id, _ string) runtime.StateManager {
+			if id == "live-container" {
+				return liveSm
+			}
+			return staleSm
+		}
+		liveSm.StateReturns(&specs.State{Status: "running", Pid: 100}, nil)
+
+		staleSm.LayersReturns("C:\\sandboxes\\stale-sandbox", "some-volume-path", nil)
+
+		hcsQuery.GetContainersReturns([]hcsshim.ContainerProperties{{ID: "live-container"}}, nil)
+		mounter.PidsReturns([]int{100, 200}, nil)
+		mounter.MountPathStub = func(pid int) string {
+			return fmt.Sprintf("C:\\proc\\%d", pid)
+		}
+
+		r = runtime.New(stateFactory, containerFactory, mounter, layerManager, streamer, hcsQuery, processWrapper, rootDir, credentialSpecPath, false)
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(rootDir)).To(Succeed())
+	})
+
+	report := func() runtime.GCReport {
+		var report runtime.GCReport
+		Expect(json.Unmarshal(output.Contents(), &report)).To(Succeed())
+		return report
+	}
+
+	It("removes the mount points, sandboxes and state of containers missing from HCS", func() {
+		Expect(r.GC(false, output)).To(Succeed())
+
+		Expect(hcsQuery.GetContainersArgsForCall(0)).To(Equal(hcsshim.ComputeSystemQuery{}))
+
+		Expect(mounter.UnmountCallCount()).To(Equal(1))
+		Expect(mounter.UnmountArgsForCall(0)).To(Equal(200))
+
+		Expect(layerManager.UnmountCallCount()).To(Equal(1))
+		Expect(layerManager.UnmountArgsForCall(0)).To(Equal("C:\\sandboxes\\stale-sandbox"))
+
+		Expect(liveSm.DeleteCallCount()).To(Equal(0))
+		Expect(staleSm.DeleteCallCount()).To(Equal(1))
+
+		Expect(report()).To(Equal(runtime.GCReport{
+			DryRun:            false,
+			MountPoints:       []string{"C:\\proc\\200"},
+			SandboxLayers:     []string{"C:\\sandboxes\\stale-sandbox"},
+			StateDirs:         []string{filepath.Join(rootDir, "stale-container")},
+			UnknownContainers: []string{},
+		}))
+	})
+
+	Context("when a container is created while HCS is queried", func() {
+		BeforeEach(func() {
+			hcsQuery.GetContainersStub = func(hcsshim.ComputeSystemQuery) ([]hcsshim.ContainerProperties, error) {
+				Expect(os.MkdirAll(filepath.Join(rootDir, "new-container"), 0755)).To(Succeed())
+				return []hcsshim.ContainerProperties{{ID: "live-container"}}, nil
+			}
+		})
+
+		It("leaves its state alone", func() {
+			Expect(r.GC(false, output)).To(Succeed())
+
+			for i := 0; i < stateFactory.NewManagerCallCount(); i++ {
+				_, _, _, id, _ := stateFactory.NewManagerArgsForCall(i)
+				Expect(id).NotTo(Equal("new-container"))
+			}
+			Expect(staleSm.DeleteCallCount()).To(Equal(1))
+			Expect(report().StateDirs).To(Equal([]string{filepath.Join(rootDir, "stale-container")}))
+		})
+	})
+
+	Context("when it is a dry run", func() {
+		It("only reports what would be removed", func() {
+			Expect(r.GC(true, output)).To(Succeed())
+
+			Expect(mounter.UnmountCallCount()).To(Equal(0))
+			Expect(layerManager.UnmountCallCount()).To(Equal(0))
+			Expect(staleSm.DeleteCallCount()).To(Equal(0))
+
+			Expect(report()).To(Equal(runtime.GCReport{
+				DryRun:            true,
+				MountPoints:       []string{"C:\\proc\\200"},
+				SandboxLayers:     []string{"C:\\sandboxes\\stale-sandbox"},
+				StateDirs:         []string{filepath.Join(rootDir, "stale-container")},
+				UnknownContainers: []string{},
+			}))
+		})
+	})
+
+	Context("when HCS reports containers without state in the root dir", func() {
+		BeforeEach(func() {
+			hcsQuery.GetContainersReturns([]hcsshim.ContainerProperties{{ID: "live-container"}, {ID: "sidecar"}}, nil)
+		})
+
+		It("leaves every mount point in place but removes stale state", func() {
+			Expect(r.GC(false, output)).To(Succeed())
+
+			Expect(mounter.PidsCallCount()).To(Equal(0))
+			Expect(mounter.UnmountCallCount()).To(Equal(0))
+			Expect(staleSm.DeleteCallCount()).To(Equal(1))
+
+			Expect(report().MountPoints).To(BeEmpty())
+			Expect(report().UnknownContainers).To(Equal([]string{"sidecar"}))
+		})
+	})
+
+	Context("when a stale container has no mounted sandbox", func() {
+		BeforeEach(func() {
+			staleSm.LayersReturns("", "", nil)
+		})
+
+		It("only removes its state", func() {
+			Expect(r.GC(false, output)).To(Succeed())
+
+			Expect(layerManager.UnmountCallCount()).To(Equal(0))
+			Expect(staleSm.DeleteCallCount()).To(Equal(1))
+			Expect(report().SandboxLayers).To(BeEmpty())
+		})
+	})
+
+	Context("when unmounting a stale sandbox fails", func() {
+		BeforeEach(func() {
+			layerManager.UnmountReturns(errors.New("couldn't unmount sandbox"))
+		})
+
+		It("keeps the state recording the sandbox and returns the error", func() {
+			err := r.GC(false, output)
+			Expect(err).To(MatchError("couldn't unmount sandbox"))
+
+			Expect(staleSm.DeleteCallCount()).To(Equal(0))
+			Expect(report().SandboxLayers).To(BeEmpty())
+			Expect(report().StateDirs).To(BeEmpty())
+		})
+	})
+
+	Context("when the state of a live container cannot be read", func() {
+		BeforeEach(func() {
+			liveSm.StateReturns(nil, errors.New("couldn't get state"))
+		})
+
+		It("removes nothing and returns the error", func() {
+			err := r.GC(false, output)
+			Expect(err).To(MatchError("couldn't get state"))
+			Expect(mounter.UnmountCallCount()).To(Equal(0))
+			Expect(staleSm.DeleteCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when listing HCS containers fails", func() {
+		BeforeEach(func() {
+			hcsQuery.GetContainersReturns(nil, errors.New("couldn't list containers"))
+		})
+
+		It("returns the error", func() {
+			err := r.GC(false, output)
+			Expect(err).To(MatchError("couldn't list containers"))
+			Expect(mounter.UnmountCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when removing a stale resource fails", func() {
+		BeforeEach(func() {
+			mounter.UnmountReturns(errors.New("couldn't unmount"))
+		})
+
+		It("removes the rest, reports what was removed and returns the error", func() {
+			err := r.GC(false, output)
+			Expect(err).To(MatchError("couldn't unmount"))
+			Expect(staleSm.DeleteCallCount()).To(Equal(1))
+
+			Expect(report().MountPoints).To(BeEmpty())
+			Expect(report().StateDirs).To(Equal([]string{filepath.Join(rootDir, "stale-container")}))
+		})
+	})
+
+	Context("provided output is nil", func() {
+		It("returns an error", func() {
+			err := r.GC(false, nil)
+			Expect(err).To(MatchError("provided output is nil"))
+		})
+	})
+})
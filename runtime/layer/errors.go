@@ -0,0 +1,19 @@
+package layer
+
+import "fmt"
+
+type MissingSandboxError struct {
+	LayerFolders []string
+}
+
+func (e *MissingSandboxError) Error() string {
+	return fmt.Sprintf("layer folders must contain at least one image layer followed by a sandbox layer: %+v", e.LayerFolders)
+}
+
+type MissingVolumePathError struct {
+	Sandbox string
+}
+
+func (e *MissingVolumePathError) Error() string {
+	return fmt.Sprintf("could not get volume path for sandbox layer: %s", e.Sandbox)
+}
@@ -0,0 +1,430 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fakes
+
+import (
+	"sync"
+
+	"code.cloudfoundry.org/winc/runtime/layer"
+	"github.com/Microsoft/hcsshim"
+)
+
+type HCSClient struct {
+	ActivateLayerStub        func(hcsshim.DriverInfo, string) error
+	activateLayerMutex       sync.RWMutex
+	activateLayerArgsForCall []struct {
+		arg1 hcsshim.DriverInfo
+		arg2 string
+	}
+	activateLayerReturns struct {
+		result1 error
+	}
+	activateLayerReturnsOnCall map[int]struct {
+		result1 error
+	}
+	DeactivateLayerStub        func(hcsshim.DriverInfo, string) error
+	deactivateLayerMutex       sync.RWMutex
+	deactivateLayerArgsForCall []struct {
+		arg1 hcsshim.DriverInfo
+		arg2 string
+	}
+	deactivateLayerReturns struct {
+		result1 error
+	}
+	deactivateLayerReturnsOnCall map[int]struct {
+		result1 error
+	}
+	GetLayerMountPathStub        func(hcsshim.DriverInfo, string) (string, error)
+	getLayerMountPathMutex       sync.RWMutex
+	getLayerMountPathArgsForCall []struct {
+		arg1 hcsshim.DriverInfo
+		arg2 string
+	}
+	getLayerMountPathReturns struct {
+		result1 string
+		result2 error
+	}
+	getLayerMountPathReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
+	PrepareLayerStub        func(hcsshim.DriverInfo, string, []string) error
+	prepareLayerMutex       sync.RWMutex
+	prepareLayerArgsForCall []struct {
+		arg1 hcsshim.DriverInfo
+		arg2 string
+		arg3 []string
+	}
+	prepareLayerReturns struct {
+		result1 error
+	}
+	prepareLayerReturnsOnCall map[int]struct {
+		result1 error
+	}
+	UnprepareLayerStub        func(hcsshim.DriverInfo, string) error
+	unprepareLayerMutex       sync.RWMutex
+	unprepareLayerArgsForCall []struct {
+		arg1 hcsshim.DriverInfo
+		arg2 string
+	}
+	unprepareLayerReturns struct {
+		result1 error
+	}
+	unprepareLayerReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *HCSClient) ActivateLayer(arg1 hcsshim.DriverInfo, arg2 string) error {
+	fake.activateLayerMutex.Lock()
+	ret, specificReturn := fake.activateLayerReturnsOnCall[len(fake.activateLayerArgsForCall)]
+	fake.activateLayerArgsForCall = append(fake.activateLayerArgsForCall, struct {
+		arg1 hcsshim.DriverInfo
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.ActivateLayerStub
+	fakeReturns := fake.activateLayerReturns
+	fake.recordInvocation("ActivateLayer", []interface{}{arg1, arg2})
+	fake.activateLayerMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *HCSClient) ActivateLayerCallCount() int {
+	fake.activateLayerMutex.RLock()
+	defer fake.activateLayerMutex.RUnlock()
+	return len(fake.activateLayerArgsForCall)
+}
+
+func (fake *HCSClient) ActivateLayerCalls(stub func(hcsshim.DriverInfo, string) error) {
+	fake.activateLayerMutex.Lock()
+	defer fake.activateLayerMutex.Unlock()
+	fake.ActivateLayerStub = stub
+}
+
+func (fake *HCSClient) ActivateLayerArgsForCall(i int) (hcsshim.DriverInfo, string) {
+	fake.activateLayerMutex.RLock()
+	defer fake.activateLayerMutex.RUnlock()
+	argsForCall := fake.activateLayerArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *HCSClient) ActivateLayerReturns(result1 error) {
+	fake.activateLayerMutex.Lock()
+	defer fake.activateLayerMutex.Unlock()
+	fake.ActivateLayerStub = nil
+	fake.activateLayerReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *HCSClient) ActivateLayerReturnsOnCall(i int, result1 error) {
+	fake.activateLayerMutex.Lock()
+	defer fake.activateLayerMutex.Unlock()
+	fake.ActivateLayerStub = nil
+	if fake.activateLayerReturnsOnCall == nil {
+		fake.activateLayerReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.activateLayerReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *HCSClient) DeactivateLayer(arg1 hcsshim.DriverInfo, arg2 string) error {
+	fake.deactivateLayerMutex.Lock()
+	ret, specificReturn := fake.deactivateLayerReturnsOnCall[len(fake.deactivateLayerArgsForCall)]
+	fake.deactivateLayerArgsForCall = append(fake.deactivateLayerArgsForCall, struct {
+		arg1 hcsshim.DriverInfo
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.DeactivateLayerStub
+	fakeReturns := fake.deactivateLayerReturns
+	fake.recordInvocation("DeactivateLayer", []interface{}{arg1, arg2})
+	fake.deactivateLayerMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *HCSClient) DeactivateLayerCallCount() int {
+	fake.deactivateLayerMutex.RLock()
+	defer fake.deactivateLayerMutex.RUnlock()
+	return len(fake.deactivateLayerArgsForCall)
+}
+
+func (fake *HCSClient) DeactivateLayerCalls(stub func(hcsshim.DriverInfo, string) error) {
+	fake.deactivateLayerMutex.Lock()
+	defer fake.deactivateLayerMutex.Unlock()
+	fake.DeactivateLayerStub = stub
+}
+
+func (fake *HCSClient) DeactivateLayerArgsForCall(i int) (hcsshim.DriverInfo, string) {
+	fake.deactivateLayerMutex.RLock()
+	defer fake.deactivateLayerMutex.RUnlock()
+	argsForCall := fake.deactivateLayerArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *HCSClient) DeactivateLayerReturns(result1 error) {
+	fake.deactivateLayerMutex.Lock()
+	defer fake.deactivateLayerMutex.Unlock()
+	fake.DeactivateLayerStub = nil
+	fake.deactivateLayerReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *HCSClient) DeactivateLayerReturnsOnCall(i int, result1 error) {
+	fake.deactivateLayerMutex.Lock()
+	defer fake.deactivateLayerMutex.Unlock()
+	fake.DeactivateLayerStub = nil
+	if fake.deactivateLayerReturnsOnCall == nil {
+		fake.deactivateLayerReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deactivateLayerReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *HCSClient) GetLayerMountPath(arg1 hcsshim.DriverInfo, arg2 string) (string, error) {
+	fake.getLayerMountPathMutex.Lock()
+	ret, specificReturn := fake.getLayerMountPathReturnsOnCall[len(fake.getLayerMountPathArgsForCall)]
+	fake.getLayerMountPathArgsForCall = append(fake.getLayerMountPathArgsForCall, struct {
+		arg1 hcsshim.DriverInfo
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.GetLayerMountPathStub
+	fakeReturns := fake.getLayerMountPathReturns
+	fake.recordInvocation("GetLayerMountPath", []interface{}{arg1, arg2})
+	fake.getLayerMountPathMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *HCSClient) GetLayerMountPathCallCount() int {
+	fake.getLayerMountPathMutex.RLock()
+	defer fake.getLayerMountPathMutex.RUnlock()
+	return len(fake.getLayerMountPathArgsForCall)
+}
+
+func (fake *HCSClient) GetLayerMountPathCalls(stub func(hcsshim.DriverInfo, string) (string, error)) {
+	fake.getLayerMountPathMutex.Lock()
+	defer fake.getLayerMountPathMutex.Unlock()
+	fake.GetLayerMountPathStub = stub
+}
+
+func (fake *HCSClient) GetLayerMountPathArgsForCall(i int) (hcsshim.DriverInfo, string) {
+	fake.getLayerMountPathMutex.RLock()
+	defer fake.getLayerMountPathMutex.RUnlock()
+	argsForCall := fake.getLayerMountPathArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *HCSClient) GetLayerMountPathReturns(result1 string, result2 error) {
+	fake.getLayerMountPathMutex.Lock()
+	defer fake.getLayerMountPathMutex.Unlock()
+	fake.GetLayerMountPathStub = nil
+	fake.getLayerMountPathReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *HCSClient) GetLayerMountPathReturnsOnCall(i int, result1 string, result2 error) {
+	fake.getLayerMountPathMutex.Lock()
+	defer fake.getLayerMountPathMutex.Unlock()
+	fake.GetLayerMountPathStub = nil
+	if fake.getLayerMountPathReturnsOnCall == nil {
+		fake.getLayerMountPathReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.getLayerMountPathReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *HCSClient) PrepareLayer(arg1 hcsshim.DriverInfo, arg2 string, arg3 []string) error {
+	var arg3Copy []string
+	if arg3 != nil {
+		arg3Copy = make([]string, len(arg3))
+		copy(arg3Copy, arg3)
+	}
+	fake.prepareLayerMutex.Lock()
+	ret, specificReturn := fake.prepareLayerReturnsOnCall[len(fake.prepareLayerArgsForCall)]
+	fake.prepareLayerArgsForCall = append(fake.prepareLayerArgsForCall, struct {
+		arg1 hcsshim.DriverInfo
+		arg2 string
+		arg3 []string
+	}{arg1, arg2, arg3Copy})
+	stub := fake.PrepareLayerStub
+	fakeReturns := fake.prepareLayerReturns
+	fake.recordInvocation("PrepareLayer", []interface{}{arg1, arg2, arg3Copy})
+	fake.prepareLayerMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *HCSClient) PrepareLayerCallCount() int {
+	fake.prepareLayerMutex.RLock()
+	defer fake.prepareLayerMutex.RUnlock()
+	return len(fake.prepareLayerArgsForCall)
+}
+
+func (fake *HCSClient) PrepareLayerCalls(stub func(hcsshim.DriverInfo, string, []string) error) {
+	fake.prepareLayerMutex.Lock()
+	defer fake.prepareLayerMutex.Unlock()
+	fake.PrepareLayerStub = stub
+}
+
+func (fake *HCSClient) PrepareLayerArgsForCall(i int) (hcsshim.DriverInfo, string, []string) {
+	fake.prepareLayerMutex.RLock()
+	defer fake.prepareLayerMutex.RUnlock()
+	argsForCall := fake.prepareLayerArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *HCSClient) PrepareLayerReturns(result1 error) {
+	fake.prepareLayerMutex.Lock()
+	defer fake.prepareLayerMutex.Unlock()
+	fake.PrepareLayerStub = nil
+	fake.prepareLayerReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *HCSClient) PrepareLayerReturnsOnCall(i int, result1 error) {
+	fake.prepareLayerMutex.Lock()
+	defer fake.prepareLayerMutex.Unlock()
+	fake.PrepareLayerStub = nil
+	if fake.prepareLayerReturnsOnCall == nil {
+		fake.prepareLayerReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.prepareLayerReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *HCSClient) UnprepareLayer(arg1 hcsshim.DriverInfo, arg2 string) error {
+	fake.unprepareLayerMutex.Lock()
+	ret, specificReturn := fake.unprepareLayerReturnsOnCall[len(fake.unprepareLayerArgsForCall)]
+	fake.unprepareLayerArgsForCall = append(fake.unprepareLayerArgsForCall, struct {
+		arg1 hcsshim.DriverInfo
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.UnprepareLayerStub
+	fakeReturns := fake.unprepareLayerReturns
+	fake.recordInvocation("UnprepareLayer", []interface{}{arg1, arg2})
+	fake.unprepareLayerMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *HCSClient) UnprepareLayerCallCount() int {
+	fake.unprepareLayerMutex.RLock()
+	defer fake.unprepareLayerMutex.RUnlock()
+	return len(fake.unprepareLayerArgsForCall)
+}
+
+func (fake *HCSClient) UnprepareLayerCalls(stub func(hcsshim.DriverInfo, string) error) {
+	fake.unprepareLayerMutex.Lock()
+	defer fake.unprepareLayerMutex.Unlock()
+	fake.UnprepareLayerStub = stub
+}
+
+func (fake *HCSClient) UnprepareLayerArgsForCall(i int) (hcsshim.DriverInfo, string) {
+	fake.unprepareLayerMutex.RLock()
+	defer fake.unprepareLayerMutex.RUnlock()
+	argsForCall := fake.unprepareLayerArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *HCSClient) UnprepareLayerReturns(result1 error) {
+	fake.unprepareLayerMutex.Lock()
+	defer fake.unprepareLayerMutex.Unlock()
+	fake.UnprepareLayerStub = nil
+	fake.unprepareLayerReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *HCSClient) UnprepareLayerReturnsOnCall(i int, result1 error) {
+	fake.unprepareLayerMutex.Lock()
+	defer fake.unprepareLayerMutex.Unlock()
+	fake.UnprepareLayerStub = nil
+	if fake.unprepareLayerReturnsOnCall == nil {
+		fake.unprepareLayerReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.unprepareLayerReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *HCSClient) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.activateLayerMutex.RLock()
+	defer fake.activateLayerMutex.RUnlock()
+	fake.deactivateLayerMutex.RLock()
+	defer fake.deactivateLayerMutex.RUnlock()
+	fake.getLayerMountPathMutex.RLock()
+	defer fake.getLayerMountPathMutex.RUnlock()
+	fake.prepareLayerMutex.RLock()
+	defer fake.prepareLayerMutex.RUnlock()
+	fake.unprepareLayerMutex.RLock()
+	defer fake.unprepareLayerMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *HCSClient) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ layer.HCSClient = new(HCSClient)
@@ -0,0 +1,90 @@
+package layer
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/Microsoft/hcsshim"
+)
+
+// filterDriver is the hcsshim.DriverInfo flavour for windowsfilter layers
+const filterDriver = 1
+
+//go:generate counterfeiter -o fakes/hcsclient.go --fake-name HCSClient . HCSClient
+type HCSClient interface {
+	ActivateLayer(hcsshim.DriverInfo, string) error
+	PrepareLayer(hcsshim.DriverInfo, string, []string) error
+	GetLayerMountPath(hcsshim.DriverInfo, string) (string, error)
+	UnprepareLayer(hcsshim.DriverInfo, string) error
+	DeactivateLayer(hcsshim.DriverInfo, string) error
+}
+
+type Manager struct {
+	hcsClient HCSClient
+}
+
+func New(hcsClient HCSClient) *Manager {
+	return &Manager{
+		hcsClient: hcsClient,
+	}
+}
+
+// Mount activates the sandbox layer, the last entry in layerFolders, prepares
+// it on top of the remaining read-only layers and returns its volume path.
+func (m *Manager) Mount(layerFolders []string) (string, string, error) {
+	if len(layerFolders) < 2 {
+		return "", "", &MissingSandboxError{LayerFolders: layerFolders}
+	}
+
+	sandbox := layerFolders[len(layerFolders)-1]
+	parents := layerFolders[:len(layerFolders)-1]
+	info, id := driverInfo(sandbox)
+
+	if err := m.hcsClient.ActivateLayer(info, id); err != nil {
+		return "", "", fmt.Errorf("activate layer %s: %s", sandbox, err.Error())
+	}
+
+	if err := m.hcsClient.PrepareLayer(info, id, parents); err != nil {
+		m.hcsClient.DeactivateLayer(info, id)
+		return "", "", fmt.Errorf("prepare layer %s: %s", sandbox, err.Error())
+	}
+
+	volumePath, err := m.hcsClient.GetLayerMountPath(info, id)
+	if err != nil {
+		m.Unmount(sandbox)
+		return "", "", fmt.Errorf("get layer mount path %s: %s", sandbox, err.Error())
+	}
+
+	if volumePath == "" {
+		m.Unmount(sandbox)
+		return "", "", &MissingVolumePathError{Sandbox: sandbox}
+	}
+
+	return sandbox, volumePath, nil
+}
+
+func (m *Manager) Unmount(sandbox string) error {
+	info, id := driverInfo(sandbox)
+
+	unprepareErr := m.hcsClient.UnprepareLayer(info, id)
+	deactivateErr := m.hcsClient.DeactivateLayer(info, id)
+
+	if unprepareErr != nil && deactivateErr != nil {
+		return fmt.Errorf("unprepare layer %s: %s, deactivate layer %s: %s", sandbox, unprepareErr.Error(), sandbox, deactivateErr.Error())
+	}
+	if unprepareErr != nil {
+		return fmt.Errorf("unprepare layer %s: %s", sandbox, unprepareErr.Error())
+	}
+	if deactivateErr != nil {
+		return fmt.Errorf("deactivate layer %s: %s", sandbox, deactivateErr.Error())
+	}
+
+	return nil
+}
+
+func driverInfo(layerPath string) (hcsshim.DriverInfo, string) {
+	return hcsshim.DriverInfo{
+		Flavour: filterDriver,
+		HomeDir: filepath.Dir(layerPath),
+	}, filepath.Base(layerPath)
+}
@@ -0,0 +1,145 @@
+package layer_test
+
+import (
+	"errors"
+
+	"code.cloudfoundry.org/winc/runtime/layer"
+	"code.cloudfoundry.org/winc/runtime/layer/fakes"
+	"github.com/Microsoft/hcsshim"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Layer", func() {
+	var (
+		hcsClient    *fakes.HCSClient
+		layerManager *layer.Manager
+		layerFolders []string
+		expectedInfo hcsshim.DriverInfo
+	)
+
+	BeforeEach(func() {
+		hcsClient = &fakes.HCSClient{}
+		layerManager = layer.New(hcsClient)
+
+		layerFolders = []string{
+			"C:\\layers\\top-layer",
+			"C:\\layers\\base-layer",
+			"C:\\sandboxes\\my-sandbox",
+		}
+		expectedInfo = hcsshim.DriverInfo{Flavour: 1, HomeDir: "C:\\sandboxes"}
+
+		hcsClient.GetLayerMountPathReturns("\\\\?\\Volume{some-guid}\\", nil)
+	})
+
+	Describe("Mount", func() {
+		It("activates and prepares the sandbox on top of the image layers", func() {
+			sandbox, volumePath, err := layerManager.Mount(layerFolders)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sandbox).To(Equal("C:\\sandboxes\\my-sandbox"))
+			Expect(volumePath).To(Equal("\\\\?\\Volume{some-guid}\\"))
+
+			Expect(hcsClient.ActivateLayerCallCount()).To(Equal(1))
+			info, id := hcsClient.ActivateLayerArgsForCall(0)
+			Expect(info).To(Equal(expectedInfo))
+			Expect(id).To(Equal("my-sandbox"))
+
+			Expect(hcsClient.PrepareLayerCallCount()).To(Equal(1))
+			info, id, parents := hcsClient.PrepareLayerArgsForCall(0)
+			Expect(info).To(Equal(expectedInfo))
+			Expect(id).To(Equal("my-sandbox"))
+			Expect(parents).To(Equal([]string{"C:\\layers\\top-layer", "C:\\layers\\base-layer"}))
+
+			Expect(hcsClient.GetLayerMountPathCallCount()).To(Equal(1))
+			info, id = hcsClient.GetLayerMountPathArgsForCall(0)
+			Expect(info).To(Equal(expectedInfo))
+			Expect(id).To(Equal("my-sandbox"))
+		})
+
+		Context("when there is no sandbox layer", func() {
+			It("returns an error", func() {
+				_, _, err := layerManager.Mount([]string{"C:\\layers\\base-layer"})
+				Expect(err).To(BeAssignableToTypeOf(&layer.MissingSandboxError{}))
+				Expect(hcsClient.ActivateLayerCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when activating the sandbox fails", func() {
+			BeforeEach(func() {
+				hcsClient.ActivateLayerReturns(errors.New("couldn't activate"))
+			})
+
+			It("returns the error", func() {
+				_, _, err := layerManager.Mount(layerFolders)
+				Expect(err).To(MatchError("activate layer C:\\sandboxes\\my-sandbox: couldn't activate"))
+				Expect(hcsClient.PrepareLayerCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when preparing the sandbox fails", func() {
+			BeforeEach(func() {
+				hcsClient.PrepareLayerReturns(errors.New("couldn't prepare"))
+			})
+
+			It("deactivates the sandbox and returns the error", func() {
+				_, _, err := layerManager.Mount(layerFolders)
+				Expect(err).To(MatchError("prepare layer C:\\sandboxes\\my-sandbox: couldn't prepare"))
+				Expect(hcsClient.DeactivateLayerCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when getting the mount path fails", func() {
+			BeforeEach(func() {
+				hcsClient.GetLayerMountPathReturns("", errors.New("couldn't get mount path"))
+			})
+
+			It("unprepares and deactivates the sandbox and returns the error", func() {
+				_, _, err := layerManager.Mount(layerFolders)
+				Expect(err).To(MatchError("get layer mount path C:\\sandboxes\\my-sandbox: couldn't get mount path"))
+				Expect(hcsClient.UnprepareLayerCallCount()).To(Equal(1))
+				Expect(hcsClient.DeactivateLayerCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when the mount path is empty", func() {
+			BeforeEach(func() {
+				hcsClient.GetLayerMountPathReturns("", nil)
+			})
+
+			It("unprepares and deactivates the sandbox and returns an error", func() {
+				_, _, err := layerManager.Mount(layerFolders)
+				Expect(err).To(MatchError(&layer.MissingVolumePathError{Sandbox: "C:\\sandboxes\\my-sandbox"}))
+				Expect(hcsClient.UnprepareLayerCallCount()).To(Equal(1))
+				Expect(hcsClient.DeactivateLayerCallCount()).To(Equal(1))
+			})
+		})
+	})
+
+	Describe("Unmount", func() {
+		It("unprepares and deactivates the sandbox", func() {
+			Expect(layerManager.Unmount("C:\\sandboxes\\my-sandbox")).To(Succeed())
+
+			Expect(hcsClient.UnprepareLayerCallCount()).To(Equal(1))
+			info, id := hcsClient.UnprepareLayerArgsForCall(0)
+			Expect(info).To(Equal(expectedInfo))
+			Expect(id).To(Equal("my-sandbox"))
+
+			Expect(hcsClient.DeactivateLayerCallCount()).To(Equal(1))
+			info, id = hcsClient.DeactivateLayerArgsForCall(0)
+			Expect(info).To(Equal(expectedInfo))
+			Expect(id).To(Equal("my-sandbox"))
+		})
+
+		Context("when unpreparing the sandbox fails", func() {
+			BeforeEach(func() {
+				hcsClient.UnprepareLayerReturns(errors.New("couldn't unprepare"))
+			})
+
+			It("still deactivates the sandbox but returns the error", func() {
+				err := layerManager.Unmount("C:\\sandboxes\\my-sandbox")
+				Expect(err).To(MatchError("unprepare layer C:\\sandboxes\\my-sandbox: couldn't unprepare"))
+				Expect(hcsClient.DeactivateLayerCallCount()).To(Equal(1))
+			})
+		})
+	})
+})
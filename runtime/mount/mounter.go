@@ -2,6 +2,7 @@ package mount
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -44,6 +45,46 @@ func (m *Mounter) Unmount(pid int) error {
 	return os.RemoveAll(mountPath(pid))
 }
 
+// Pids returns the pids that have a mount directory under c:\proc, whether
+// or not their volume is still mounted.
+func (m *Mounter) Pids() ([]int, error) {
+	entries, err := ioutil.ReadDir(procPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []int{}, nil
+		}
+		return nil, err
+	}
+
+	pids := []int{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+
+		pids = append(pids, pid)
+	}
+
+	return pids, nil
+}
+
+// RootPath returns the directory the volume of the container with init
+// process pid is mounted at.
+func (m *Mounter) RootPath(pid int) string {
+	return rootPath(pid)
+}
+
+// MountPath returns the directory created under c:\proc for the container
+// with init process pid, which Unmount removes.
+func (m *Mounter) MountPath(pid int) string {
+	return mountPath(pid)
+}
+
 func (m *Mounter) setPoint(mountPoint, volume string) error {
 	if err := setVolumeMountPointW.Find(); err != nil {
 		return err
@@ -90,8 +131,12 @@ func (m *Mounter) deletePoint(mountPoint string) error {
 	return nil
 }
 
+func procPath() string {
+	return filepath.Join("c:\\", "proc")
+}
+
 func mountPath(pid int) string {
-	return filepath.Join("c:\\", "proc", strconv.Itoa(pid))
+	return filepath.Join(procPath(), strconv.Itoa(pid))
 }
 
 func rootPath(pid int) string {
@@ -0,0 +1,30 @@
+package rootfs
+
+import "fmt"
+
+type PathEscapesRootError struct {
+	Root string
+	Path string
+}
+
+func (e *PathEscapesRootError) Error() string {
+	return fmt.Sprintf("path %s escapes container root %s", e.Path, e.Root)
+}
+
+type TooManySymlinksError struct {
+	Root string
+	Path string
+}
+
+func (e *TooManySymlinksError) Error() string {
+	return fmt.Sprintf("too many symlinks resolving %s in container root %s", e.Path, e.Root)
+}
+
+type UnsupportedEntryError struct {
+	Name     string
+	Typeflag byte
+}
+
+func (e *UnsupportedEntryError) Error() string {
+	return fmt.Sprintf("unsupported tar entry %s of type %q", e.Name, e.Typeflag)
+}
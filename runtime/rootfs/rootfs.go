@@ -0,0 +1,346 @@
+package rootfs
+
+import (
+	"archive/tar"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const maxSymlinks = 255
+
+// Streamer copies files in and out of a container root such as the volume
+// mounted at c:\proc\<pid>\root. Paths inside the container are resolved
+// relative to the root and may never escape it, even through symlinks.
+type Streamer struct{}
+
+// StreamIn extracts the tar stream in into the directory containerPath.
+func (s *Streamer) StreamIn(root, containerPath string, in io.Reader) error {
+	return extractTar(in, root, relative(containerPath))
+}
+
+// StreamOut writes a tar stream of containerPath to out. When containerPath
+// is the container root its contents are streamed rather than the root itself.
+func (s *Streamer) StreamOut(root, containerPath string, out io.Writer) error {
+	src, err := resolve(root, containerPath)
+	if err != nil {
+		return err
+	}
+
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+
+	return writeTar(out, src, baseName(containerPath), info)
+}
+
+// CopyIn copies hostPath into the container. If containerPath is an existing
+// directory hostPath is copied into it, otherwise it is copied to containerPath.
+func (s *Streamer) CopyIn(root, hostPath, containerPath string) error {
+	info, err := os.Stat(hostPath)
+	if err != nil {
+		return err
+	}
+
+	dst, err := resolve(root, containerPath)
+	if err != nil {
+		return err
+	}
+
+	destDir, name := relative(containerPath), filepath.Base(hostPath)
+	if fi, err := os.Stat(dst); err != nil || !fi.IsDir() {
+		destDir, name = filepath.Dir(destDir), baseName(containerPath)
+	}
+
+	return pipeTar(hostPath, name, info, func(r io.Reader) error {
+		return extractTar(r, root, destDir)
+	})
+}
+
+// CopyOut copies containerPath to the host. If hostPath is an existing
+// directory containerPath is copied into it, otherwise it is copied to hostPath.
+func (s *Streamer) CopyOut(root, containerPath, hostPath string) error {
+	src, err := resolve(root, containerPath)
+	if err != nil {
+		return err
+	}
+
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+
+	destDir, name := hostPath, baseName(containerPath)
+	if fi, err := os.Stat(hostPath); err != nil || !fi.IsDir() {
+		destDir, name = filepath.Dir(hostPath), filepath.Base(hostPath)
+	}
+
+	return pipeTar(src, name, info, func(r io.Reader) error {
+		return extractTar(r, destDir, ".")
+	})
+}
+
+func pipeTar(src, name string, info os.FileInfo, extract func(io.Reader) error) error {
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(writeTar(pw, src, name, info))
+	}()
+
+	err := extract(pr)
+	pr.Close()
+	return err
+}
+
+func writeTar(w io.Writer, src, name string, info os.FileInfo) error {
+	tw := tar.NewWriter(w)
+	if err := addToTar(tw, src, name, info); err != nil {
+		return err
+	}
+
+	return tw.Close()
+}
+
+func addToTar(tw *tar.Writer, path, name string, info os.FileInfo) error {
+	// an empty name streams the contents of a directory without the directory
+	// itself
+	if name != "" {
+		var link string
+		if info.Mode()&os.ModeSymlink != 0 {
+			var err error
+			if link, err = os.Readlink(path); err != nil {
+				return err
+			}
+		}
+
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+
+		header.Name = filepath.ToSlash(name)
+		if info.IsDir() {
+			header.Name += "/"
+		}
+
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+	}
+
+	if info.Mode().IsRegular() {
+		return copyFileTo(tw, path)
+	}
+
+	if !info.IsDir() {
+		return nil
+	}
+
+	entries, err := ioutil.ReadDir(path)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if err := addToTar(tw, filepath.Join(path, entry.Name()), filepath.Join(name, entry.Name()), entry); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func copyFileTo(w io.Writer, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(w, f)
+	return err
+}
+
+func extractTar(r io.Reader, root, destDir string) error {
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		name := filepath.Clean(filepath.FromSlash(header.Name))
+		if name == "." {
+			continue
+		}
+		if name == ".." || strings.HasPrefix(name, ".."+string(filepath.Separator)) || filepath.IsAbs(name) {
+			return &PathEscapesRootError{Root: root, Path: header.Name}
+		}
+
+		entryPath := filepath.Join(destDir, name)
+
+		// resolve only the parent so that an entry replaces rather than follows
+		// an existing symlink at its path
+		parent, err := resolve(root, filepath.Dir(entryPath))
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(parent, filepath.Base(entryPath))
+
+		if err := extractEntry(tr, header, parent, target); err != nil {
+			return err
+		}
+	}
+}
+
+func extractEntry(tr *tar.Reader, header *tar.Header, parent, target string) error {
+	if err := os.MkdirAll(parent, 0755); err != nil {
+		return err
+	}
+
+	if header.Typeflag != tar.TypeDir {
+		if err := removeSymlink(target); err != nil {
+			return err
+		}
+	}
+
+	switch header.Typeflag {
+	case tar.TypeDir:
+		return os.MkdirAll(target, os.FileMode(header.Mode).Perm())
+	case tar.TypeReg, tar.TypeRegA:
+		f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(header.Mode).Perm())
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		_, err = io.Copy(f, tr)
+		return err
+	case tar.TypeSymlink:
+		return os.Symlink(header.Linkname, target)
+	default:
+		return &UnsupportedEntryError{Name: header.Name, Typeflag: header.Typeflag}
+	}
+}
+
+func removeSymlink(path string) error {
+	info, err := os.Lstat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	if info.Mode()&os.ModeSymlink != 0 {
+		return os.Remove(path)
+	}
+
+	return nil
+}
+
+// resolve returns the host path of containerPath inside root, following
+// symlinks as the container would see them. Absolute symlink targets are
+// resolved from the container root; anything leading out of the root is an
+// error.
+func resolve(root, containerPath string) (string, error) {
+	current := ""
+	remaining := components(containerPath)
+	symlinks := 0
+
+	for len(remaining) > 0 {
+		component := remaining[0]
+		remaining = remaining[1:]
+
+		if component == "." {
+			continue
+		}
+
+		if component == ".." {
+			if current == "" {
+				return "", &PathEscapesRootError{Root: root, Path: containerPath}
+			}
+
+			current = filepath.Dir(current)
+			if current == "." {
+				current = ""
+			}
+			continue
+		}
+
+		next := filepath.Join(current, component)
+		info, err := os.Lstat(filepath.Join(root, next))
+		if err != nil {
+			if os.IsNotExist(err) {
+				current = next
+				continue
+			}
+			return "", err
+		}
+
+		if info.Mode()&os.ModeSymlink == 0 {
+			current = next
+			continue
+		}
+
+		symlinks++
+		if symlinks > maxSymlinks {
+			return "", &TooManySymlinksError{Root: root, Path: containerPath}
+		}
+
+		link, err := os.Readlink(filepath.Join(root, next))
+		if err != nil {
+			return "", err
+		}
+
+		if volume := filepath.VolumeName(link); volume != "" && !isDriveLetter(volume) {
+			return "", &PathEscapesRootError{Root: root, Path: containerPath}
+		}
+
+		if filepath.VolumeName(link) != "" || strings.HasPrefix(link, "/") || strings.HasPrefix(link, string(filepath.Separator)) {
+			current = ""
+		}
+
+		remaining = append(components(link), remaining...)
+	}
+
+	return filepath.Join(root, current), nil
+}
+
+// components splits a container path into its elements, dropping any drive
+// letter since every path is relative to the container root.
+func components(path string) []string {
+	path = path[len(filepath.VolumeName(path)):]
+	return strings.FieldsFunc(path, func(r rune) bool {
+		return r == '/' || r == filepath.Separator
+	})
+}
+
+// relative returns containerPath relative to the container root.
+func relative(containerPath string) string {
+	path := filepath.Clean(string(filepath.Separator) + filepath.Join(components(containerPath)...))
+	rel := strings.TrimPrefix(path, string(filepath.Separator))
+	if rel == "" {
+		return "."
+	}
+
+	return rel
+}
+
+func baseName(containerPath string) string {
+	rel := relative(containerPath)
+	if rel == "." {
+		return ""
+	}
+
+	return filepath.Base(rel)
+}
+
+func isDriveLetter(volume string) bool {
+	return len(volume) == 2 && volume[1] == ':'
+}
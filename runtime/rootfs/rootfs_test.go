@@ -0,0 +1,253 @@
+package rootfs_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"code.cloudfoundry.org/winc/runtime/rootfs"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Streamer", func() {
+	var (
+		root     string
+		hostDir  string
+		outside  string
+		streamer *rootfs.Streamer
+	)
+
+	BeforeEach(func() {
+		var err error
+		root, err = ioutil.TempDir("", "rootfs")
+		Expect(err).NotTo(HaveOccurred())
+		hostDir, err = ioutil.TempDir("", "host")
+		Expect(err).NotTo(HaveOccurred())
+		outside, err = ioutil.TempDir("", "outside")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(os.MkdirAll(filepath.Join(root, "app", "logs"), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(root, "app", "logs", "app.log"), []byte("some-log"), 0644)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(outside, "secret"), []byte("secret"), 0644)).To(Succeed())
+
+		streamer = &rootfs.Streamer{}
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(root)).To(Succeed())
+		Expect(os.RemoveAll(hostDir)).To(Succeed())
+		Expect(os.RemoveAll(outside)).To(Succeed())
+	})
+
+	readTar := func(r io.Reader) map[string]string {
+		entries := map[string]string{}
+		tr := tar.NewReader(r)
+		for {
+			header, err := tr.Next()
+			if err == io.EOF {
+				return entries
+			}
+			Expect(err).NotTo(HaveOccurred())
+
+			content, err := ioutil.ReadAll(tr)
+			Expect(err).NotTo(HaveOccurred())
+			entries[header.Name] = string(content)
+		}
+	}
+
+	writeTar := func(entries ...*tar.Header) *bytes.Buffer {
+		buf := &bytes.Buffer{}
+		tw := tar.NewWriter(buf)
+		for _, header := range entries {
+			content := []byte("content of " + header.Name)
+			if header.Typeflag == tar.TypeReg {
+				header.Size = int64(len(content))
+			}
+			if header.Mode == 0 {
+				header.Mode = 0644
+			}
+			Expect(tw.WriteHeader(header)).To(Succeed())
+			if header.Typeflag == tar.TypeReg {
+				_, err := tw.Write(content)
+				Expect(err).NotTo(HaveOccurred())
+			}
+		}
+		Expect(tw.Close()).To(Succeed())
+		return buf
+	}
+
+	Describe("StreamOut", func() {
+		It("streams the directory and its contents", func() {
+			out := &bytes.Buffer{}
+			Expect(streamer.StreamOut(root, "/app/logs", out)).To(Succeed())
+			Expect(readTar(out)).To(Equal(map[string]string{
+				"logs/":        "",
+				"logs/app.log": "some-log",
+			}))
+		})
+
+		It("streams the contents of the root", func() {
+			out := &bytes.Buffer{}
+			Expect(streamer.StreamOut(root, "/", out)).To(Succeed())
+			Expect(readTar(out)).To(HaveKey("app/logs/app.log"))
+			Expect(readTar(bytes.NewReader(out.Bytes()))).NotTo(HaveKey("/"))
+		})
+
+		Context("when a symlink points outside the root", func() {
+			BeforeEach(func() {
+				Expect(os.Symlink("../../..", filepath.Join(root, "app", "escape"))).To(Succeed())
+			})
+
+			It("returns an error", func() {
+				err := streamer.StreamOut(root, "/app/escape/secret", &bytes.Buffer{})
+				Expect(err).To(BeAssignableToTypeOf(&rootfs.PathEscapesRootError{}))
+			})
+		})
+
+		Context("when a symlink is absolute", func() {
+			BeforeEach(func() {
+				Expect(os.Symlink(outside, filepath.Join(root, "app", "abs"))).To(Succeed())
+				Expect(os.MkdirAll(filepath.Join(root, outside), 0755)).To(Succeed())
+				Expect(ioutil.WriteFile(filepath.Join(root, outside, "secret"), []byte("in-container"), 0644)).To(Succeed())
+			})
+
+			It("resolves it from the container root", func() {
+				out := &bytes.Buffer{}
+				Expect(streamer.StreamOut(root, "/app/abs/secret", out)).To(Succeed())
+				Expect(readTar(out)).To(Equal(map[string]string{"secret": "in-container"}))
+			})
+		})
+	})
+
+	Describe("StreamIn", func() {
+		It("extracts the stream into the directory, creating it", func() {
+			in := writeTar(
+				&tar.Header{Name: "bin/", Typeflag: tar.TypeDir, Mode: 0755},
+				&tar.Header{Name: "bin/app.exe", Typeflag: tar.TypeReg},
+			)
+			Expect(streamer.StreamIn(root, "/new/dir", in)).To(Succeed())
+
+			content, err := ioutil.ReadFile(filepath.Join(root, "new", "dir", "bin", "app.exe"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal("content of bin/app.exe"))
+		})
+
+		Context("when an entry escapes the destination", func() {
+			It("returns an error", func() {
+				in := writeTar(&tar.Header{Name: "../../evil", Typeflag: tar.TypeReg})
+				err := streamer.StreamIn(root, "/app", in)
+				Expect(err).To(BeAssignableToTypeOf(&rootfs.PathEscapesRootError{}))
+			})
+		})
+
+		Context("when the stream writes through a symlink it created", func() {
+			It("does not write outside the root", func() {
+				in := writeTar(
+					&tar.Header{Name: "link", Typeflag: tar.TypeSymlink, Linkname: outside},
+					&tar.Header{Name: "link/secret", Typeflag: tar.TypeReg},
+				)
+				Expect(streamer.StreamIn(root, "/app", in)).To(Succeed())
+
+				content, err := ioutil.ReadFile(filepath.Join(outside, "secret"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(content)).To(Equal("secret"))
+
+				content, err = ioutil.ReadFile(filepath.Join(root, outside, "secret"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(content)).To(Equal("content of link/secret"))
+			})
+		})
+
+		Context("when a file replaces an existing symlink", func() {
+			BeforeEach(func() {
+				Expect(os.Symlink(filepath.Join(outside, "secret"), filepath.Join(root, "app", "config"))).To(Succeed())
+			})
+
+			It("replaces the symlink instead of following it", func() {
+				in := writeTar(&tar.Header{Name: "config", Typeflag: tar.TypeReg})
+				Expect(streamer.StreamIn(root, "/app", in)).To(Succeed())
+
+				content, err := ioutil.ReadFile(filepath.Join(outside, "secret"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(content)).To(Equal("secret"))
+
+				info, err := os.Lstat(filepath.Join(root, "app", "config"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(info.Mode().IsRegular()).To(BeTrue())
+			})
+		})
+
+		Context("when the stream contains an unsupported entry", func() {
+			It("returns an error", func() {
+				in := writeTar(&tar.Header{Name: "fifo", Typeflag: tar.TypeFifo})
+				err := streamer.StreamIn(root, "/app", in)
+				Expect(err).To(MatchError(&rootfs.UnsupportedEntryError{Name: "fifo", Typeflag: tar.TypeFifo}))
+			})
+		})
+	})
+
+	Describe("CopyOut", func() {
+		It("copies a file to the host path", func() {
+			dst := filepath.Join(hostDir, "copied.log")
+			Expect(streamer.CopyOut(root, "/app/logs/app.log", dst)).To(Succeed())
+
+			content, err := ioutil.ReadFile(dst)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal("some-log"))
+		})
+
+		It("copies a directory into an existing host directory", func() {
+			Expect(streamer.CopyOut(root, "/app/logs", hostDir)).To(Succeed())
+
+			content, err := ioutil.ReadFile(filepath.Join(hostDir, "logs", "app.log"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal("some-log"))
+		})
+
+		Context("when the path does not exist", func() {
+			It("returns an error", func() {
+				err := streamer.CopyOut(root, "/app/missing", hostDir)
+				Expect(os.IsNotExist(err)).To(BeTrue())
+			})
+		})
+	})
+
+	Describe("CopyIn", func() {
+		BeforeEach(func() {
+			Expect(os.MkdirAll(filepath.Join(hostDir, "config"), 0755)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(hostDir, "config", "app.ini"), []byte("some-config"), 0644)).To(Succeed())
+		})
+
+		It("copies a directory into an existing container directory", func() {
+			Expect(streamer.CopyIn(root, filepath.Join(hostDir, "config"), "/app")).To(Succeed())
+
+			content, err := ioutil.ReadFile(filepath.Join(root, "app", "config", "app.ini"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal("some-config"))
+		})
+
+		It("copies a file to the container path", func() {
+			Expect(streamer.CopyIn(root, filepath.Join(hostDir, "config", "app.ini"), "/app/renamed.ini")).To(Succeed())
+
+			content, err := ioutil.ReadFile(filepath.Join(root, "app", "renamed.ini"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(content)).To(Equal("some-config"))
+		})
+
+		Context("when the container path escapes the root through a symlink", func() {
+			BeforeEach(func() {
+				Expect(os.Symlink("../..", filepath.Join(root, "app", "up"))).To(Succeed())
+			})
+
+			It("returns an error", func() {
+				err := streamer.CopyIn(root, filepath.Join(hostDir, "config"), "/app/up/up")
+				Expect(err).To(BeAssignableToTypeOf(&rootfs.PathEscapesRootError{}))
+				Expect(filepath.Join(outside, "config")).NotTo(BeADirectory())
+			})
+		})
+	})
+})
@@ -26,6 +26,8 @@ var _ = Describe("Run", func() {
 	)
 	var (
 		mounter            *fakes.Mounter
+		layerManager       *fakes.LayerManager
+		streamer           *fakes.Streamer
 		stateFactory       *fakes.StateFactory
 		sm                 *fakes.StateManager
 		containerFactory   *fakes.ContainerFactory
@@ -45,6 +47,8 @@ var _ = Describe("Run", func() {
 
 	BeforeEach(func() {
 		mounter = &fakes.Mounter{}
+		layerManager = &fakes.LayerManager{}
+		streamer = &fakes.Streamer{}
 		hcsQuery = &fakes.HCSQuery{}
 		stateFactory = &fakes.StateFactory{}
 		sm = &fakes.StateManager{}
@@ -66,7 +70,7 @@ var _ = Describe("Run", func() {
 		stateFactory.NewManagerReturns(sm)
 		containerFactory.NewManagerReturns(cm)
 
-		r = runtime.New(stateFactory, containerFactory, mounter, hcsQuery, processWrapper, rootDir, credentialSpecPath)
+		r = runtime.New(stateFactory, containerFactory, mounter, layerManager, streamer, hcsQuery, processWrapper, rootDir, credentialSpecPath, false)
 
 		stdin = gbytes.NewBuffer()
 		stdout = gbytes.NewBuffer()
@@ -102,7 +106,8 @@ var _ = Describe("Run", func() {
 
 			Expect(cm.SpecArgsForCall(0)).To(Equal(bundlePath))
 			Expect(cm.CreateArgsForCall(0)).To(Equal(spec))
-			Expect(sm.InitializeArgsForCall(0)).To(Equal(bundlePath))
+			bp, _, _, _ := sm.InitializeArgsForCall(0)
+			Expect(bp).To(Equal(bundlePath))
 
 			p, attach := cm.ExecArgsForCall(0)
 			Expect(p).To(Equal(spec.Process))
@@ -156,7 +161,8 @@ var _ = Describe("Run", func() {
 
 			Expect(cm.SpecArgsForCall(0)).To(Equal(bundlePath))
 			Expect(cm.CreateArgsForCall(0)).To(Equal(spec))
-			Expect(sm.InitializeArgsForCall(0)).To(Equal(bundlePath))
+			bp, _, _, _ := sm.InitializeArgsForCall(0)
+			Expect(bp).To(Equal(bundlePath))
 
 			p, attach := cm.ExecArgsForCall(0)
 			Expect(p).To(Equal(spec.Process))
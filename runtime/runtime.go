@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -12,6 +14,7 @@ import (
 	"code.cloudfoundry.org/winc/hcs"
 	"code.cloudfoundry.org/winc/runtime/config"
 	"code.cloudfoundry.org/winc/runtime/container"
+	"code.cloudfoundry.org/winc/runtime/state"
 	"code.cloudfoundry.org/winc/runtime/winsyscall"
 	"github.com/Microsoft/hcsshim"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
@@ -22,6 +25,23 @@ import (
 type Mounter interface {
 	Mount(pid int, volumePath string, logger *logrus.Entry) error
 	Unmount(pid int) error
+	RootPath(pid int) string
+	MountPath(pid int) string
+	Pids() ([]int, error)
+}
+
+//go:generate counterfeiter -o fakes/streamer.go --fake-name Streamer . Streamer
+type Streamer interface {
+	CopyIn(root, hostPath, containerPath string) error
+	CopyOut(root, containerPath, hostPath string) error
+	StreamIn(root, containerPath string, in io.Reader) error
+	StreamOut(root, containerPath string, out io.Writer) error
+}
+
+//go:generate counterfeiter -o fakes/layer_manager.go --fake-name LayerManager . LayerManager
+type LayerManager interface {
+	Mount([]string) (string, string, error)
+	Unmount(string) error
 }
 
 //go:generate counterfeiter -o fakes/state_factory.go --fake-name StateFactory . StateFactory
@@ -31,7 +51,8 @@ type StateFactory interface {
 
 //go:generate counterfeiter -o fakes/state_manager.go --fake-name StateManager . StateManager
 type StateManager interface {
-	Initialize(string) error
+	Initialize(string, string, string, string) error
+	Layers() (string, string, error)
 	Delete() error
 	SetFailure() error
 	SetSuccess(hcs.Process) error
@@ -46,7 +67,8 @@ type ContainerFactory interface {
 //go:generate counterfeiter -o fakes/container_manager.go --fake-name ContainerManager . ContainerManager
 type ContainerManager interface {
 	Spec(string) (*specs.Spec, error)
-	CredentialSpec(string) (string, error)
+	LayerSpec(string) (*specs.Spec, error)
+	CredentialSpec(*specs.Spec, string, string) (string, string, error)
 	Create(*specs.Spec, string) error
 	Exec(*specs.Process, bool) (hcs.Process, error)
 	Stats() (container.Statistics, error)
@@ -70,6 +92,18 @@ type HCSQuery interface {
 	GetContainers(hcsshim.ComputeSystemQuery) ([]hcsshim.ContainerProperties, error)
 }
 
+// GCReport lists the stale mount points, sandbox layers and state directories
+// removed by GC, or that would be removed on a dry run. Mount points are left
+// alone while HCS reports containers that have no state in the root dir,
+// since any of them may own one; those containers are listed as unknown.
+type GCReport struct {
+	DryRun            bool     `json:"dryRun"`
+	MountPoints       []string `json:"mountPoints"`
+	SandboxLayers     []string `json:"sandboxLayers"`
+	StateDirs         []string `json:"stateDirs"`
+	UnknownContainers []string `json:"unknownContainers"`
+}
+
 type IO struct {
 	Stdin  io.Reader
 	Stdout io.Writer
@@ -80,21 +114,27 @@ type Runtime struct {
 	stateFactory       StateFactory
 	containerFactory   ContainerFactory
 	mounter            Mounter
+	layerManager       LayerManager
+	streamer           Streamer
 	hcsQuery           HCSQuery
 	processWrapper     ProcessWrapper
 	rootDir            string
 	credentialSpecPath string
+	mountLayers        bool
 }
 
-func New(s StateFactory, c ContainerFactory, m Mounter, h HCSQuery, p ProcessWrapper, rootDir, credentialSpecPath string) *Runtime {
+func New(s StateFactory, c ContainerFactory, m Mounter, l LayerManager, f Streamer, h HCSQuery, p ProcessWrapper, rootDir, credentialSpecPath string, mountLayers bool) *Runtime {
 	return &Runtime{
 		stateFactory:       s,
 		containerFactory:   c,
 		mounter:            m,
+		layerManager:       l,
+		streamer:           f,
 		hcsQuery:           h,
 		processWrapper:     p,
 		rootDir:            rootDir,
 		credentialSpecPath: credentialSpecPath,
+		mountLayers:        mountLayers,
 	}
 }
 
@@ -302,7 +342,7 @@ func (r *Runtime) Start(containerId, pidFile string) error {
 		return fmt.Errorf("cannot start a container in the %s state", ociState.Status)
 	}
 
-	spec, err := cm.Spec(ociState.Bundle)
+	spec, err := r.loadSpec(cm, ociState)
 	if err != nil {
 		return err
 	}
@@ -356,26 +396,315 @@ func (r *Runtime) State(containerId string, output io.Writer) error {
 	return err
 }
 
+func (r *Runtime) CopyIn(containerId, hostPath, containerPath string) error {
+	root, err := r.containerRoot(containerId)
+	if err != nil {
+		return err
+	}
+
+	return r.streamer.CopyIn(root, hostPath, containerPath)
+}
+
+func (r *Runtime) CopyOut(containerId, containerPath, hostPath string) error {
+	root, err := r.containerRoot(containerId)
+	if err != nil {
+		return err
+	}
+
+	return r.streamer.CopyOut(root, containerPath, hostPath)
+}
+
+func (r *Runtime) StreamIn(containerId, containerPath string, input io.Reader) error {
+	root, err := r.containerRoot(containerId)
+	if err != nil {
+		return err
+	}
+
+	return r.streamer.StreamIn(root, containerPath, input)
+}
+
+func (r *Runtime) StreamOut(containerId, containerPath string, output io.Writer) error {
+	if output == nil {
+		return errors.New("provided output is nil")
+	}
+
+	root, err := r.containerRoot(containerId)
+	if err != nil {
+		return err
+	}
+
+	return r.streamer.StreamOut(root, containerPath, output)
+}
+
+// containerRoot returns the host path of the container's root volume. Once the
+// init process has started the volume is mounted at c:\proc\<pid>\root,
+// before that it is only reachable through its volume path.
+func (r *Runtime) containerRoot(containerId string) (string, error) {
+	logger := logrus.WithFields(logrus.Fields{
+		"containerId": containerId,
+	})
+	logger.Debug("resolving container root")
+
+	client := hcs.Client{}
+	cm := r.containerFactory.NewManager(logger, &client, containerId)
+
+	wsc := winsyscall.WinSyscall{}
+	sm := r.stateFactory.NewManager(logger, &client, &wsc, containerId, r.rootDir)
+
+	ociState, err := sm.State()
+	if err != nil {
+		return "", err
+	}
+
+	if ociState.Pid != 0 {
+		return r.mounter.RootPath(ociState.Pid), nil
+	}
+
+	spec, err := r.loadSpec(cm, ociState)
+	if err != nil {
+		return "", err
+	}
+
+	return spec.Root.Path, nil
+}
+
+// GC removes the c:\proc mount points, sandbox layers and state directories
+// left behind by containers that no longer exist in HCS, and writes a report
+// of them to output.
+func (r *Runtime) GC(dryRun bool, output io.Writer) error {
+	logger := logrus.WithFields(logrus.Fields{
+		"dryRun": dryRun,
+	})
+	logger.Debug("collecting stale container resources")
+
+	if output == nil {
+		return errors.New("provided output is nil")
+	}
+
+	client := hcs.Client{}
+	wsc := winsyscall.WinSyscall{}
+
+	// state is written after a container is created in HCS, so reading the
+	// state first keeps a container created in between from looking stale
+	containerIds, err := r.stateDirs()
+	if err != nil {
+		return err
+	}
+
+	liveContainers, err := r.hcsQuery.GetContainers(hcsshim.ComputeSystemQuery{})
+	if err != nil {
+		return err
+	}
+
+	hasState := map[string]bool{}
+	for _, containerId := range containerIds {
+		hasState[containerId] = true
+	}
+
+	report := GCReport{
+		DryRun:            dryRun,
+		MountPoints:       []string{},
+		SandboxLayers:     []string{},
+		StateDirs:         []string{},
+		UnknownContainers: []string{},
+	}
+
+	live := map[string]bool{}
+	for _, c := range liveContainers {
+		live[c.ID] = true
+		if !hasState[c.ID] {
+			report.UnknownContainers = append(report.UnknownContainers, c.ID)
+		}
+	}
+
+	livePids := map[int]bool{}
+	staleIds := []string{}
+	for _, containerId := range containerIds {
+		if !live[containerId] {
+			staleIds = append(staleIds, containerId)
+			continue
+		}
+
+		sm := r.stateFactory.NewManager(logger, &client, &wsc, containerId, r.rootDir)
+		ociState, err := sm.State()
+		if err != nil {
+			// without the pid of a live container none of the mount points
+			// can safely be considered stale
+			return err
+		}
+		livePids[ociState.Pid] = true
+	}
+
+	var errs []string
+
+	// the pid of a container without state here, e.g. one created with a
+	// different root dir, is unknown, so it could own any of the mount points
+	if len(report.UnknownContainers) == 0 {
+		pids, err := r.mounter.Pids()
+		if err != nil {
+			return err
+		}
+
+		for _, pid := range pids {
+			if livePids[pid] {
+				continue
+			}
+
+			if !dryRun {
+				if err := r.mounter.Unmount(pid); err != nil {
+					logger.Error(err)
+					errs = append(errs, err.Error())
+					continue
+				}
+			}
+			report.MountPoints = append(report.MountPoints, r.mounter.MountPath(pid))
+		}
+	} else {
+		logger.WithField("unknownContainers", report.UnknownContainers).Warn("leaving mount points in place")
+	}
+
+	for _, containerId := range staleIds {
+		sm := r.stateFactory.NewManager(logger, &client, &wsc, containerId, r.rootDir)
+
+		// the state is the only record of the sandbox, so it is kept until
+		// the sandbox has been released
+		sandbox, err := recordedSandbox(sm)
+		if err != nil {
+			logger.Error(err)
+			errs = append(errs, err.Error())
+			continue
+		}
+
+		if sandbox != "" {
+			if !dryRun {
+				if err := r.layerManager.Unmount(sandbox); err != nil {
+					logger.Error(err)
+					errs = append(errs, err.Error())
+					continue
+				}
+			}
+			report.SandboxLayers = append(report.SandboxLayers, sandbox)
+		}
+
+		if !dryRun {
+			if err := sm.Delete(); err != nil {
+				logger.Error(err)
+				errs = append(errs, err.Error())
+				continue
+			}
+		}
+		report.StateDirs = append(report.StateDirs, filepath.Join(r.rootDir, containerId))
+	}
+
+	reportJson, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if _, err := output.Write(reportJson); err != nil {
+		return err
+	}
+
+	if len(errs) != 0 {
+		return errors.New(strings.Join(errs, "\n"))
+	}
+
+	return nil
+}
+
+// stateDirs returns the ids of all containers with a state directory.
+func (r *Runtime) stateDirs() ([]string, error) {
+	entries, err := ioutil.ReadDir(r.rootDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []string{}, nil
+		}
+		return nil, err
+	}
+
+	containerIds := []string{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			containerIds = append(containerIds, entry.Name())
+		}
+	}
+
+	return containerIds, nil
+}
+
 func (r *Runtime) createContainer(cm ContainerManager, sm StateManager, bundlePath string) (*specs.Spec, error) {
+	if r.mountLayers {
+		return r.createLayerContainer(cm, sm, bundlePath)
+	}
+
 	spec, err := cm.Spec(bundlePath)
 	if err != nil {
 		return nil, err
 	}
 
-	credentialSpec, err := cm.CredentialSpec(r.credentialSpecPath)
+	if err := r.createAndInitialize(cm, sm, spec, bundlePath, "", ""); err != nil {
+		return nil, err
+	}
+
+	return spec, nil
+}
+
+func (r *Runtime) createLayerContainer(cm ContainerManager, sm StateManager, bundlePath string) (*specs.Spec, error) {
+	spec, err := cm.LayerSpec(bundlePath)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := cm.Create(spec, credentialSpec); err != nil {
+	sandbox, volumePath, err := r.layerManager.Mount(spec.Windows.LayerFolders)
+	if err != nil {
+		return nil, err
+	}
+
+	// the container is created from the volume that the sandbox is mounted
+	// at, so only the read-only layers are passed through to HCS
+	spec.Root = &specs.Root{Path: volumePath}
+	spec.Windows.LayerFolders = spec.Windows.LayerFolders[:len(spec.Windows.LayerFolders)-1]
+
+	if err := r.createAndInitialize(cm, sm, spec, bundlePath, sandbox, volumePath); err != nil {
+		r.layerManager.Unmount(sandbox)
 		return nil, err
 	}
 
-	if err := sm.Initialize(bundlePath); err != nil {
+	return spec, nil
+}
+
+func (r *Runtime) createAndInitialize(cm ContainerManager, sm StateManager, spec *specs.Spec, bundlePath, sandbox, volumePath string) error {
+	credentialSpec, credentialSpecSource, err := cm.CredentialSpec(spec, bundlePath, r.credentialSpecPath)
+	if err != nil {
+		return err
+	}
+
+	if err := cm.Create(spec, credentialSpec); err != nil {
+		return err
+	}
+
+	if err := sm.Initialize(bundlePath, credentialSpecSource, sandbox, volumePath); err != nil {
 		cm.Delete(false)
+		return err
+	}
+
+	return nil
+}
+
+// loadSpec loads the spec of an already created container. Containers whose
+// sandbox was mounted by winc are started from the recorded volume path.
+func (r *Runtime) loadSpec(cm ContainerManager, ociState *specs.State) (*specs.Spec, error) {
+	volumePath, ok := ociState.Annotations[state.VolumePathAnnotation]
+	if !ok {
+		return cm.Spec(ociState.Bundle)
+	}
+
+	spec, err := cm.LayerSpec(ociState.Bundle)
+	if err != nil {
 		return nil, err
 	}
 
+	spec.Root = &specs.Root{Path: volumePath}
 	return spec, nil
 }
 
@@ -387,6 +716,13 @@ func (r *Runtime) deleteContainer(cm ContainerManager, sm StateManager, force bo
 		logger.Error(err)
 
 		if _, ok := err.(*hcs.NotFoundError); ok {
+			// the sandbox outlives the container, so it is released even
+			// though there is nothing left to delete in HCS
+			if err := r.releaseRecordedSandbox(sm); err != nil {
+				logger.Error(err)
+				return err
+			}
+
 			if force {
 				return nil
 			}
@@ -401,12 +737,12 @@ func (r *Runtime) deleteContainer(cm ContainerManager, sm StateManager, force bo
 		}
 	}
 
-	if err := sm.Delete(); err != nil {
+	// the state records the sandbox, so it is only removed once the container
+	// is gone and its sandbox is released
+	if err := r.deleteAndReleaseSandbox(cm, ociState, force); err != nil {
 		logger.Error(err)
 		errs = append(errs, err.Error())
-	}
-
-	if err := cm.Delete(force); err != nil {
+	} else if err := sm.Delete(); err != nil {
 		logger.Error(err)
 		errs = append(errs, err.Error())
 	}
@@ -418,6 +754,55 @@ func (r *Runtime) deleteContainer(cm ContainerManager, sm StateManager, force bo
 	return nil
 }
 
+// deleteAndReleaseSandbox deletes the container from HCS, then unmounts the
+// sandbox winc mounted for it
+func (r *Runtime) deleteAndReleaseSandbox(cm ContainerManager, ociState *specs.State, force bool) error {
+	if err := cm.Delete(force); err != nil {
+		return err
+	}
+
+	if ociState == nil {
+		return nil
+	}
+
+	sandbox, ok := ociState.Annotations[state.SandboxLayerAnnotation]
+	if !ok {
+		return nil
+	}
+
+	return r.layerManager.Unmount(sandbox)
+}
+
+// releaseRecordedSandbox unmounts the sandbox recorded in the state of a
+// container that no longer exists in HCS, then removes the state so the
+// sandbox is not released twice.
+func (r *Runtime) releaseRecordedSandbox(sm StateManager) error {
+	sandbox, err := recordedSandbox(sm)
+	if err != nil || sandbox == "" {
+		return err
+	}
+
+	if err := r.layerManager.Unmount(sandbox); err != nil {
+		return err
+	}
+
+	return sm.Delete()
+}
+
+// recordedSandbox returns the sandbox layer recorded in the container's state,
+// if it has any state at all.
+func recordedSandbox(sm StateManager) (string, error) {
+	sandbox, _, err := sm.Layers()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	return sandbox, nil
+}
+
 func (r *Runtime) startProcess(cm ContainerManager, sm StateManager, spec *specs.Spec, pidFile string, detach bool, logger *logrus.Entry) (hcs.Process, error) {
 	process, err := cm.Exec(spec.Process, !detach)
 	if err != nil {
@@ -22,6 +22,8 @@ var _ = Describe("Start", func() {
 	)
 	var (
 		mounter            *fakes.Mounter
+		layerManager       *fakes.LayerManager
+		streamer           *fakes.Streamer
 		stateFactory       *fakes.StateFactory
 		sm                 *fakes.StateManager
 		containerFactory   *fakes.ContainerFactory
@@ -37,6 +39,8 @@ var _ = Describe("Start", func() {
 
 	BeforeEach(func() {
 		mounter = &fakes.Mounter{}
+		layerManager = &fakes.LayerManager{}
+		streamer = &fakes.Streamer{}
 		hcsQuery = &fakes.HCSQuery{}
 		stateFactory = &fakes.StateFactory{}
 		sm = &fakes.StateManager{}
@@ -58,7 +62,7 @@ var _ = Describe("Start", func() {
 		stateFactory.NewManagerReturns(sm)
 		containerFactory.NewManagerReturns(cm)
 
-		r = runtime.New(stateFactory, containerFactory, mounter, hcsQuery, processWrapper, rootDir, credentialSpecPath)
+		r = runtime.New(stateFactory, containerFactory, mounter, layerManager, streamer, hcsQuery, processWrapper, rootDir, credentialSpecPath, false)
 	})
 
 	Context("starting the container succeeds", func() {
@@ -110,6 +114,33 @@ var _ = Describe("Start", func() {
 		})
 	})
 
+	Context("when winc mounted the container layers", func() {
+		BeforeEach(func() {
+			state := &specs.State{
+				Status:      "created",
+				Bundle:      bundlePath,
+				Annotations: map[string]string{"winc.volume-path": "some-volume-path"},
+			}
+			sm.StateReturns(state, nil)
+
+			cm.LayerSpecReturns(spec, nil)
+			cm.ExecReturns(unwrappedProcess, nil)
+			unwrappedProcess.PidReturns(99)
+			processWrapper.WrapReturns(wrappedProcess)
+		})
+
+		It("mounts the recorded sandbox volume", func() {
+			Expect(r.Start(containerId, pidFile)).To(Succeed())
+
+			Expect(cm.SpecCallCount()).To(Equal(0))
+			Expect(cm.LayerSpecArgsForCall(0)).To(Equal(bundlePath))
+
+			pid, path, _ := mounter.MountArgsForCall(0)
+			Expect(pid).To(Equal(99))
+			Expect(path).To(Equal("some-volume-path"))
+		})
+	})
+
 	Context("the state of the container is not 'created'", func() {
 		BeforeEach(func() {
 			state := &specs.State{Status: "running", Bundle: bundlePath}
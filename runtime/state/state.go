@@ -25,11 +25,20 @@ type Manager struct {
 	rootDir     string
 }
 
+const (
+	CredentialSpecAnnotation = "winc.credential-spec"
+	SandboxLayerAnnotation   = "winc.sandbox-layer"
+	VolumePathAnnotation     = "winc.volume-path"
+)
+
 type State struct {
-	Bundle     string           `json:"bundle"`
-	PID        int              `json:"pid"`
-	StartTime  syscall.Filetime `json:"start_time"`
-	ExecFailed bool             `json:"exec_failed"`
+	Bundle         string           `json:"bundle"`
+	PID            int              `json:"pid"`
+	StartTime      syscall.Filetime `json:"start_time"`
+	ExecFailed     bool             `json:"exec_failed"`
+	CredentialSpec string           `json:"credential_spec,omitempty"`
+	SandboxLayer   string           `json:"sandbox_layer,omitempty"`
+	VolumePath     string           `json:"volume_path,omitempty"`
 }
 
 //go:generate counterfeiter -o fakes/hcsclient.go --fake-name HCSClient . HCSClient
@@ -55,12 +64,20 @@ func New(logger *logrus.Entry, hcsClient HCSClient, winSyscall WinSyscall, id, r
 	}
 }
 
-func (m *Manager) Initialize(bundlePath string) error {
+// Initialize writes the initial state of the container. The sandbox layer
+// mounted by winc for the container, and the volume it was mounted at, are
+// recorded along with it so they can be released on delete.
+func (m *Manager) Initialize(bundlePath, credentialSpec, sandboxLayer, volumePath string) error {
 	if err := os.MkdirAll(m.stateDir(), 0755); err != nil {
 		return err
 	}
 
-	state := State{Bundle: bundlePath}
+	state := State{
+		Bundle:         bundlePath,
+		CredentialSpec: credentialSpec,
+		SandboxLayer:   sandboxLayer,
+		VolumePath:     volumePath,
+	}
 	return m.writeState(state)
 }
 
@@ -68,6 +85,18 @@ func (m *Manager) Delete() error {
 	return os.RemoveAll(m.stateDir())
 }
 
+// Layers returns the sandbox layer and volume path recorded for the
+// container. Unlike State it does not consult HCS, so the layers of a
+// container that no longer exists can still be released.
+func (m *Manager) Layers() (string, string, error) {
+	state, err := m.loadState()
+	if err != nil {
+		return "", "", err
+	}
+
+	return state.SandboxLayer, state.VolumePath, nil
+}
+
 func (m *Manager) SetFailure() error {
 	state, err := m.loadState()
 	if err != nil {
@@ -146,13 +175,27 @@ func (m *Manager) State() (*specs.State, error) {
 		}
 	}
 
-	return &specs.State{
+	ociState := &specs.State{
 		Version: specs.Version,
 		ID:      m.containerId,
 		Status:  status,
 		Bundle:  state.Bundle,
 		Pid:     state.PID,
-	}, nil
+	}
+
+	annotations := map[string]string{}
+	if state.CredentialSpec != "" {
+		annotations[CredentialSpecAnnotation] = state.CredentialSpec
+	}
+	if state.SandboxLayer != "" {
+		annotations[SandboxLayerAnnotation] = state.SandboxLayer
+		annotations[VolumePathAnnotation] = state.VolumePath
+	}
+	if len(annotations) > 0 {
+		ociState.Annotations = annotations
+	}
+
+	return ociState, nil
 }
 
 func (m *Manager) userProgramStatus(state State) (string, error) {
@@ -53,7 +53,7 @@ var _ = Describe("State", func() {
 
 	Describe("Initialize", func() {
 		It("writes the bundle path to state.json in <rootDir>/<containerId>/", func() {
-			Expect(sm.Initialize(bundlePath)).To(Succeed())
+			Expect(sm.Initialize(bundlePath, "", "", "")).To(Succeed())
 
 			var state state.State
 			contents, err := ioutil.ReadFile(stateFile)
@@ -64,12 +64,26 @@ var _ = Describe("State", func() {
 			Expect(state.PID).To(Equal(0))
 			Expect(state.StartTime).To(Equal(syscall.Filetime{}))
 			Expect(state.ExecFailed).To(Equal(false))
+			Expect(state.CredentialSpec).To(BeEmpty())
+		})
+
+		Context("when a credential spec was used", func() {
+			It("records it in state.json", func() {
+				Expect(sm.Initialize(bundlePath, "some-credential-spec.json", "", "")).To(Succeed())
+
+				var state state.State
+				contents, err := ioutil.ReadFile(stateFile)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(json.Unmarshal(contents, &state)).To(Succeed())
+
+				Expect(state.CredentialSpec).To(Equal("some-credential-spec.json"))
+			})
 		})
 	})
 
 	Describe("Delete", func() {
 		BeforeEach(func() {
-			Expect(sm.Initialize(bundlePath)).To(Succeed())
+			Expect(sm.Initialize(bundlePath, "", "", "")).To(Succeed())
 			Expect(stateFile).To(BeAnExistingFile())
 		})
 
@@ -80,9 +94,35 @@ var _ = Describe("State", func() {
 		})
 	})
 
+	Describe("Layers", func() {
+		It("returns the layers recorded when the state was initialized", func() {
+			Expect(sm.Initialize(bundlePath, "", "C:\\sandboxes\\my-sandbox", "some-volume-path")).To(Succeed())
+
+			var st state.State
+			contents, err := ioutil.ReadFile(stateFile)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(json.Unmarshal(contents, &st)).To(Succeed())
+			Expect(st.Bundle).To(Equal(bundlePath))
+			Expect(st.SandboxLayer).To(Equal("C:\\sandboxes\\my-sandbox"))
+
+			sandbox, volumePath, err := sm.Layers()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sandbox).To(Equal("C:\\sandboxes\\my-sandbox"))
+			Expect(volumePath).To(Equal("some-volume-path"))
+			Expect(hcsClient.GetContainerPropertiesCallCount()).To(Equal(0))
+		})
+
+		Context("there is no state", func() {
+			It("returns a not exist error", func() {
+				_, _, err := sm.Layers()
+				Expect(os.IsNotExist(err)).To(BeTrue())
+			})
+		})
+	})
+
 	Describe("SetFailure", func() {
 		BeforeEach(func() {
-			Expect(sm.Initialize(bundlePath)).To(Succeed())
+			Expect(sm.Initialize(bundlePath, "", "", "")).To(Succeed())
 			Expect(stateFile).To(BeAnExistingFile())
 		})
 
@@ -108,7 +148,7 @@ var _ = Describe("State", func() {
 		)
 
 		BeforeEach(func() {
-			Expect(sm.Initialize(bundlePath)).To(Succeed())
+			Expect(sm.Initialize(bundlePath, "", "", "")).To(Succeed())
 			Expect(stateFile).To(BeAnExistingFile())
 
 			proc = &hcsfakes.Process{}
@@ -228,6 +268,41 @@ var _ = Describe("State", func() {
 			Expect(ociState.Pid).To(Equal(1234))
 			Expect(ociState.ID).To(Equal(containerId))
 			Expect(ociState.Version).To(Equal(specs.Version))
+			Expect(ociState.Annotations).To(BeEmpty())
+		})
+
+		Context("state.json records a credential spec", func() {
+			BeforeEach(func() {
+				s.CredentialSpec = "some-credential-spec.json"
+				c, err := json.Marshal(s)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(ioutil.WriteFile(stateFile, c, 0644)).To(Succeed())
+			})
+
+			It("reports it as an annotation", func() {
+				ociState, err := sm.State()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(ociState.Annotations).To(Equal(map[string]string{state.CredentialSpecAnnotation: "some-credential-spec.json"}))
+			})
+		})
+
+		Context("state.json records mounted layers", func() {
+			BeforeEach(func() {
+				s.SandboxLayer = "C:\\sandboxes\\my-sandbox"
+				s.VolumePath = "some-volume-path"
+				c, err := json.Marshal(s)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(ioutil.WriteFile(stateFile, c, 0644)).To(Succeed())
+			})
+
+			It("reports them as annotations", func() {
+				ociState, err := sm.State()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(ociState.Annotations).To(Equal(map[string]string{
+					state.SandboxLayerAnnotation: "C:\\sandboxes\\my-sandbox",
+					state.VolumePathAnnotation:   "some-volume-path",
+				}))
+			})
 		})
 
 		Context("hcsshim reports the container as stopped", func() {
@@ -0,0 +1,173 @@
+package runtime_test
+
+import (
+	"errors"
+
+	"code.cloudfoundry.org/winc/runtime"
+	"code.cloudfoundry.org/winc/runtime/fakes"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+var _ = Describe("Stream", func() {
+	const (
+		bundlePath  = "some/dir"
+		rootDir     = "dir-for-state-and-things"
+		containerId = "container-for-stream"
+	)
+	var (
+		mounter            *fakes.Mounter
+		layerManager       *fakes.LayerManager
+		streamer           *fakes.Streamer
+		stateFactory       *fakes.StateFactory
+		sm                 *fakes.StateManager
+		containerFactory   *fakes.ContainerFactory
+		cm                 *fakes.ContainerManager
+		processWrapper     *fakes.ProcessWrapper
+		hcsQuery           *fakes.HCSQuery
+		credentialSpecPath string
+		r                  *runtime.Runtime
+	)
+
+	BeforeEach(func() {
+		mounter = &fakes.Mounter{}
+		layerManager = &fakes.LayerManager{}
+		streamer = &fakes.Streamer{}
+		hcsQuery = &fakes.HCSQuery{}
+		stateFactory = &fakes.StateFactory{}
+		sm = &fakes.StateManager{}
+		containerFactory = &fakes.ContainerFactory{}
+		cm = &fakes.ContainerManager{}
+		processWrapper = &fakes.ProcessWrapper{}
+
+		stateFactory.NewManagerReturns(sm)
+		containerFactory.NewManagerReturns(cm)
+
+		mounter.RootPathReturns("C:\\proc\\99\\root")
+
+		r = runtime.New(stateFactory, containerFactory, mounter, layerManager, streamer, hcsQuery, processWrapper, rootDir, credentialSpecPath, false)
+	})
+
+	Context("the container is running", func() {
+		BeforeEach(func() {
+			sm.StateReturns(&specs.State{Status: "running", Bundle: bundlePath, Pid: 99}, nil)
+		})
+
+		It("copies in through the mounted root of the init process", func() {
+			Expect(r.CopyIn(containerId, "C:\\host\\file", "C:\\app\\file")).To(Succeed())
+
+			Expect(mounter.RootPathArgsForCall(0)).To(Equal(99))
+			root, hostPath, containerPath := streamer.CopyInArgsForCall(0)
+			Expect(root).To(Equal("C:\\proc\\99\\root"))
+			Expect(hostPath).To(Equal("C:\\host\\file"))
+			Expect(containerPath).To(Equal("C:\\app\\file"))
+		})
+
+		It("copies out through the mounted root of the init process", func() {
+			Expect(r.CopyOut(containerId, "C:\\app\\file", "C:\\host\\file")).To(Succeed())
+
+			root, containerPath, hostPath := streamer.CopyOutArgsForCall(0)
+			Expect(root).To(Equal("C:\\proc\\99\\root"))
+			Expect(containerPath).To(Equal("C:\\app\\file"))
+			Expect(hostPath).To(Equal("C:\\host\\file"))
+		})
+
+		It("streams in through the mounted root of the init process", func() {
+			input := gbytes.BufferWithBytes([]byte("some-tar"))
+			Expect(r.StreamIn(containerId, "C:\\app", input)).To(Succeed())
+
+			root, containerPath, in := streamer.StreamInArgsForCall(0)
+			Expect(root).To(Equal("C:\\proc\\99\\root"))
+			Expect(containerPath).To(Equal("C:\\app"))
+			Expect(in).To(Equal(input))
+		})
+
+		It("streams out through the mounted root of the init process", func() {
+			output := gbytes.NewBuffer()
+			Expect(r.StreamOut(containerId, "C:\\app", output)).To(Succeed())
+
+			root, containerPath, out := streamer.StreamOutArgsForCall(0)
+			Expect(root).To(Equal("C:\\proc\\99\\root"))
+			Expect(containerPath).To(Equal("C:\\app"))
+			Expect(out).To(Equal(output))
+		})
+
+		Context("the streamer fails", func() {
+			BeforeEach(func() {
+				streamer.CopyOutReturns(errors.New("couldn't copy"))
+			})
+
+			It("returns the error", func() {
+				err := r.CopyOut(containerId, "C:\\app\\file", "C:\\host\\file")
+				Expect(err).To(MatchError("couldn't copy"))
+			})
+		})
+	})
+
+	Context("the container has been created but not started", func() {
+		BeforeEach(func() {
+			sm.StateReturns(&specs.State{Status: "created", Bundle: bundlePath}, nil)
+			cm.SpecReturns(&specs.Spec{Root: &specs.Root{Path: "some-volume-path"}}, nil)
+		})
+
+		It("uses the container volume from the bundle", func() {
+			Expect(r.CopyIn(containerId, "C:\\host\\file", "C:\\app\\file")).To(Succeed())
+
+			Expect(mounter.RootPathCallCount()).To(Equal(0))
+			Expect(cm.SpecArgsForCall(0)).To(Equal(bundlePath))
+			root, _, _ := streamer.CopyInArgsForCall(0)
+			Expect(root).To(Equal("some-volume-path"))
+		})
+
+		Context("winc mounted the container layers", func() {
+			BeforeEach(func() {
+				sm.StateReturns(&specs.State{
+					Status:      "created",
+					Bundle:      bundlePath,
+					Annotations: map[string]string{"winc.volume-path": "mounted-volume-path"},
+				}, nil)
+				cm.LayerSpecReturns(&specs.Spec{}, nil)
+			})
+
+			It("uses the recorded volume path", func() {
+				Expect(r.CopyIn(containerId, "C:\\host\\file", "C:\\app\\file")).To(Succeed())
+
+				root, _, _ := streamer.CopyInArgsForCall(0)
+				Expect(root).To(Equal("mounted-volume-path"))
+			})
+		})
+
+		Context("loading the spec fails", func() {
+			BeforeEach(func() {
+				cm.SpecReturns(nil, errors.New("bad spec"))
+			})
+
+			It("returns the error", func() {
+				err := r.CopyIn(containerId, "C:\\host\\file", "C:\\app\\file")
+				Expect(err).To(MatchError("bad spec"))
+				Expect(streamer.CopyInCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Context("getting the state fails", func() {
+		BeforeEach(func() {
+			sm.StateReturns(nil, errors.New("couldn't get state"))
+		})
+
+		It("returns the error", func() {
+			err := r.StreamIn(containerId, "C:\\app", gbytes.NewBuffer())
+			Expect(err).To(MatchError("couldn't get state"))
+			Expect(streamer.StreamInCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("provided output is nil", func() {
+		It("returns an error", func() {
+			err := r.StreamOut(containerId, "C:\\app", nil)
+			Expect(err).To(MatchError("provided output is nil"))
+		})
+	})
+})